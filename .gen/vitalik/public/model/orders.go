@@ -9,24 +9,36 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"time"
 )
 
 type Orders struct {
-	ID           uuid.UUID `sql:"primary_key"`
-	Type         string
-	SellCurrency string
-	SellQuantity *float64
-	SellAddress  string
-	SellUserID   string
-	Price        float64
-	BuyCurrency  string
-	BuyQuantity  *float64
-	BuyAddress   string
-	BuyUserID    string
-	Status       string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	RemovedAt    *time.Time
-	ClosedAt     *time.Time
+	ID                  uuid.UUID `sql:"primary_key"`
+	Type                string
+	SellCurrency        string
+	SellQuantity        *decimal.Decimal
+	SellAddress         string
+	SellUserID          string
+	Price               decimal.Decimal
+	BuyCurrency         string
+	BuyQuantity         *decimal.Decimal
+	BuyAddress          string
+	BuyUserID           string
+	Status              string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	RemovedAt           *time.Time
+	ClosedAt            *time.Time
+	Kind                string
+	TimeInForce         string
+	ExpiresAt           *time.Time
+	ExpiredAt           *time.Time
+	TriggerPrice        *decimal.Decimal
+	TriggeredAt         *time.Time
+	OriginalQuantity    decimal.Decimal
+	FilledQuantity      decimal.Decimal
+	FilledQuoteQuantity decimal.Decimal
+	RemainingQuantity   decimal.Decimal
+	AveragePrice        *decimal.Decimal
 }
@@ -9,6 +9,7 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"time"
 )
 
@@ -18,8 +19,9 @@ type Transactions struct {
 	SenderUserID    string
 	ReceiverAddress string
 	ReceiverUserID  string
-	Amount          float64
+	Amount          decimal.Decimal
 	Currency        string
+	Fee             decimal.Decimal
 	Purpose         *string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
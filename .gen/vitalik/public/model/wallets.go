@@ -8,6 +8,7 @@
 package model
 
 import (
+	"github.com/shopspring/decimal"
 	"time"
 )
 
@@ -15,7 +16,7 @@ type Wallets struct {
 	Address   string `sql:"primary_key"`
 	UserID    string
 	Currency  string
-	Balance   float64
+	Balance   decimal.Decimal
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
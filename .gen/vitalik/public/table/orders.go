@@ -17,22 +17,33 @@ type ordersTable struct {
 	postgres.Table
 
 	// Columns
-	ID           postgres.ColumnString
-	Type         postgres.ColumnString
-	SellCurrency postgres.ColumnString
-	SellQuantity postgres.ColumnFloat
-	SellAddress  postgres.ColumnString
-	SellUserID   postgres.ColumnString
-	Price        postgres.ColumnFloat
-	BuyCurrency  postgres.ColumnString
-	BuyQuantity  postgres.ColumnFloat
-	BuyAddress   postgres.ColumnString
-	BuyUserID    postgres.ColumnString
-	Status       postgres.ColumnString
-	CreatedAt    postgres.ColumnTimestampz
-	UpdatedAt    postgres.ColumnTimestampz
-	RemovedAt    postgres.ColumnTimestampz
-	ClosedAt     postgres.ColumnTimestampz
+	ID                  postgres.ColumnString
+	Type                postgres.ColumnString
+	SellCurrency        postgres.ColumnString
+	SellQuantity        postgres.ColumnFloat
+	SellAddress         postgres.ColumnString
+	SellUserID          postgres.ColumnString
+	Price               postgres.ColumnFloat
+	BuyCurrency         postgres.ColumnString
+	BuyQuantity         postgres.ColumnFloat
+	BuyAddress          postgres.ColumnString
+	BuyUserID           postgres.ColumnString
+	Status              postgres.ColumnString
+	CreatedAt           postgres.ColumnTimestampz
+	UpdatedAt           postgres.ColumnTimestampz
+	RemovedAt           postgres.ColumnTimestampz
+	ClosedAt            postgres.ColumnTimestampz
+	Kind                postgres.ColumnString
+	TimeInForce         postgres.ColumnString
+	ExpiresAt           postgres.ColumnTimestampz
+	ExpiredAt           postgres.ColumnTimestampz
+	TriggerPrice        postgres.ColumnFloat
+	TriggeredAt         postgres.ColumnTimestampz
+	OriginalQuantity    postgres.ColumnFloat
+	FilledQuantity      postgres.ColumnFloat
+	FilledQuoteQuantity postgres.ColumnFloat
+	RemainingQuantity   postgres.ColumnFloat
+	AveragePrice        postgres.ColumnFloat
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -73,46 +84,68 @@ func newOrdersTable(schemaName, tableName, alias string) *OrdersTable {
 
 func newOrdersTableImpl(schemaName, tableName, alias string) ordersTable {
 	var (
-		IDColumn           = postgres.StringColumn("id")
-		TypeColumn         = postgres.StringColumn("type")
-		SellCurrencyColumn = postgres.StringColumn("sell_currency")
-		SellQuantityColumn = postgres.FloatColumn("sell_quantity")
-		SellAddressColumn  = postgres.StringColumn("sell_address")
-		SellUserIDColumn   = postgres.StringColumn("sell_user_id")
-		PriceColumn        = postgres.FloatColumn("price")
-		BuyCurrencyColumn  = postgres.StringColumn("buy_currency")
-		BuyQuantityColumn  = postgres.FloatColumn("buy_quantity")
-		BuyAddressColumn   = postgres.StringColumn("buy_address")
-		BuyUserIDColumn    = postgres.StringColumn("buy_user_id")
-		StatusColumn       = postgres.StringColumn("status")
-		CreatedAtColumn    = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn    = postgres.TimestampzColumn("updated_at")
-		RemovedAtColumn    = postgres.TimestampzColumn("removed_at")
-		ClosedAtColumn     = postgres.TimestampzColumn("closed_at")
-		allColumns         = postgres.ColumnList{IDColumn, TypeColumn, SellCurrencyColumn, SellQuantityColumn, SellAddressColumn, SellUserIDColumn, PriceColumn, BuyCurrencyColumn, BuyQuantityColumn, BuyAddressColumn, BuyUserIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, RemovedAtColumn, ClosedAtColumn}
-		mutableColumns     = postgres.ColumnList{TypeColumn, SellCurrencyColumn, SellQuantityColumn, SellAddressColumn, SellUserIDColumn, PriceColumn, BuyCurrencyColumn, BuyQuantityColumn, BuyAddressColumn, BuyUserIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, RemovedAtColumn, ClosedAtColumn}
+		IDColumn                  = postgres.StringColumn("id")
+		TypeColumn                = postgres.StringColumn("type")
+		SellCurrencyColumn        = postgres.StringColumn("sell_currency")
+		SellQuantityColumn        = postgres.FloatColumn("sell_quantity")
+		SellAddressColumn         = postgres.StringColumn("sell_address")
+		SellUserIDColumn          = postgres.StringColumn("sell_user_id")
+		PriceColumn               = postgres.FloatColumn("price")
+		BuyCurrencyColumn         = postgres.StringColumn("buy_currency")
+		BuyQuantityColumn         = postgres.FloatColumn("buy_quantity")
+		BuyAddressColumn          = postgres.StringColumn("buy_address")
+		BuyUserIDColumn           = postgres.StringColumn("buy_user_id")
+		StatusColumn              = postgres.StringColumn("status")
+		CreatedAtColumn           = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn           = postgres.TimestampzColumn("updated_at")
+		RemovedAtColumn           = postgres.TimestampzColumn("removed_at")
+		ClosedAtColumn            = postgres.TimestampzColumn("closed_at")
+		KindColumn                = postgres.StringColumn("kind")
+		TimeInForceColumn         = postgres.StringColumn("time_in_force")
+		ExpiresAtColumn           = postgres.TimestampzColumn("expires_at")
+		ExpiredAtColumn           = postgres.TimestampzColumn("expired_at")
+		TriggerPriceColumn        = postgres.FloatColumn("trigger_price")
+		TriggeredAtColumn         = postgres.TimestampzColumn("triggered_at")
+		OriginalQuantityColumn    = postgres.FloatColumn("original_quantity")
+		FilledQuantityColumn      = postgres.FloatColumn("filled_quantity")
+		FilledQuoteQuantityColumn = postgres.FloatColumn("filled_quote_quantity")
+		RemainingQuantityColumn   = postgres.FloatColumn("remaining_quantity")
+		AveragePriceColumn        = postgres.FloatColumn("average_price")
+		allColumns                = postgres.ColumnList{IDColumn, TypeColumn, SellCurrencyColumn, SellQuantityColumn, SellAddressColumn, SellUserIDColumn, PriceColumn, BuyCurrencyColumn, BuyQuantityColumn, BuyAddressColumn, BuyUserIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, RemovedAtColumn, ClosedAtColumn, KindColumn, TimeInForceColumn, ExpiresAtColumn, ExpiredAtColumn, TriggerPriceColumn, TriggeredAtColumn, OriginalQuantityColumn, FilledQuantityColumn, FilledQuoteQuantityColumn, RemainingQuantityColumn, AveragePriceColumn}
+		mutableColumns            = postgres.ColumnList{TypeColumn, SellCurrencyColumn, SellQuantityColumn, SellAddressColumn, SellUserIDColumn, PriceColumn, BuyCurrencyColumn, BuyQuantityColumn, BuyAddressColumn, BuyUserIDColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, RemovedAtColumn, ClosedAtColumn, KindColumn, TimeInForceColumn, ExpiresAtColumn, ExpiredAtColumn, TriggerPriceColumn, TriggeredAtColumn, OriginalQuantityColumn, FilledQuantityColumn, FilledQuoteQuantityColumn, RemainingQuantityColumn, AveragePriceColumn}
 	)
 
 	return ordersTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:           IDColumn,
-		Type:         TypeColumn,
-		SellCurrency: SellCurrencyColumn,
-		SellQuantity: SellQuantityColumn,
-		SellAddress:  SellAddressColumn,
-		SellUserID:   SellUserIDColumn,
-		Price:        PriceColumn,
-		BuyCurrency:  BuyCurrencyColumn,
-		BuyQuantity:  BuyQuantityColumn,
-		BuyAddress:   BuyAddressColumn,
-		BuyUserID:    BuyUserIDColumn,
-		Status:       StatusColumn,
-		CreatedAt:    CreatedAtColumn,
-		UpdatedAt:    UpdatedAtColumn,
-		RemovedAt:    RemovedAtColumn,
-		ClosedAt:     ClosedAtColumn,
+		ID:                  IDColumn,
+		Type:                TypeColumn,
+		SellCurrency:        SellCurrencyColumn,
+		SellQuantity:        SellQuantityColumn,
+		SellAddress:         SellAddressColumn,
+		SellUserID:          SellUserIDColumn,
+		Price:               PriceColumn,
+		BuyCurrency:         BuyCurrencyColumn,
+		BuyQuantity:         BuyQuantityColumn,
+		BuyAddress:          BuyAddressColumn,
+		BuyUserID:           BuyUserIDColumn,
+		Status:              StatusColumn,
+		CreatedAt:           CreatedAtColumn,
+		UpdatedAt:           UpdatedAtColumn,
+		RemovedAt:           RemovedAtColumn,
+		ClosedAt:            ClosedAtColumn,
+		Kind:                KindColumn,
+		TimeInForce:         TimeInForceColumn,
+		ExpiresAt:           ExpiresAtColumn,
+		ExpiredAt:           ExpiredAtColumn,
+		TriggerPrice:        TriggerPriceColumn,
+		TriggeredAt:         TriggeredAtColumn,
+		OriginalQuantity:    OriginalQuantityColumn,
+		FilledQuantity:      FilledQuantityColumn,
+		FilledQuoteQuantity: FilledQuoteQuantityColumn,
+		RemainingQuantity:   RemainingQuantityColumn,
+		AveragePrice:        AveragePriceColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -10,9 +10,20 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	ApiKeys = ApiKeys.FromSchema(schema)
+	Candles = Candles.FromSchema(schema)
+	Deposits = Deposits.FromSchema(schema)
+	FeeSchedules = FeeSchedules.FromSchema(schema)
 	GooseDbVersion = GooseDbVersion.FromSchema(schema)
+	Holds = Holds.FromSchema(schema)
+	MarketHalts = MarketHalts.FromSchema(schema)
 	Orders = Orders.FromSchema(schema)
+	RefreshTokens = RefreshTokens.FromSchema(schema)
+	TotpEnrollments = TotpEnrollments.FromSchema(schema)
+	TotpRecoveryCodes = TotpRecoveryCodes.FromSchema(schema)
+	Trades = Trades.FromSchema(schema)
 	Transactions = Transactions.FromSchema(schema)
 	Users = Users.FromSchema(schema)
 	Wallets = Wallets.FromSchema(schema)
+	Withdrawals = Withdrawals.FromSchema(schema)
 }
@@ -24,6 +24,7 @@ type transactionsTable struct {
 	ReceiverUserID  postgres.ColumnString
 	Amount          postgres.ColumnFloat
 	Currency        postgres.ColumnString
+	Fee             postgres.ColumnFloat
 	Purpose         postgres.ColumnString
 	CreatedAt       postgres.ColumnTimestampz
 	UpdatedAt       postgres.ColumnTimestampz
@@ -74,11 +75,12 @@ func newTransactionsTableImpl(schemaName, tableName, alias string) transactionsT
 		ReceiverUserIDColumn  = postgres.StringColumn("receiver_user_id")
 		AmountColumn          = postgres.FloatColumn("amount")
 		CurrencyColumn        = postgres.StringColumn("currency")
+		FeeColumn             = postgres.FloatColumn("fee")
 		PurposeColumn         = postgres.StringColumn("purpose")
 		CreatedAtColumn       = postgres.TimestampzColumn("created_at")
 		UpdatedAtColumn       = postgres.TimestampzColumn("updated_at")
-		allColumns            = postgres.ColumnList{IDColumn, SenderAddressColumn, SenderUserIDColumn, ReceiverAddressColumn, ReceiverUserIDColumn, AmountColumn, CurrencyColumn, PurposeColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns        = postgres.ColumnList{SenderAddressColumn, SenderUserIDColumn, ReceiverAddressColumn, ReceiverUserIDColumn, AmountColumn, CurrencyColumn, PurposeColumn, CreatedAtColumn, UpdatedAtColumn}
+		allColumns            = postgres.ColumnList{IDColumn, SenderAddressColumn, SenderUserIDColumn, ReceiverAddressColumn, ReceiverUserIDColumn, AmountColumn, CurrencyColumn, FeeColumn, PurposeColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns        = postgres.ColumnList{SenderAddressColumn, SenderUserIDColumn, ReceiverAddressColumn, ReceiverUserIDColumn, AmountColumn, CurrencyColumn, FeeColumn, PurposeColumn, CreatedAtColumn, UpdatedAtColumn}
 	)
 
 	return transactionsTable{
@@ -92,6 +94,7 @@ func newTransactionsTableImpl(schemaName, tableName, alias string) transactionsT
 		ReceiverUserID:  ReceiverUserIDColumn,
 		Amount:          AmountColumn,
 		Currency:        CurrencyColumn,
+		Fee:             FeeColumn,
 		Purpose:         PurposeColumn,
 		CreatedAt:       CreatedAtColumn,
 		UpdatedAt:       UpdatedAtColumn,
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/fxmodules/configfx"
+	"vitalik_backend/internal/fxmodules/loggerfx"
+	"vitalik_backend/internal/fxmodules/storefx"
+)
+
+// candles rebuilds the candle tables from the trade history and exits.
+func main() {
+	fx.New(buildFxOptions()).Run()
+}
+
+func buildFxOptions() fx.Option {
+	return fx.Options(
+		loggerfx.Module,
+		configfx.Module,
+		storefx.Module,
+		fx.Invoke(rebuildCandles),
+	)
+}
+
+func rebuildCandles(lc fx.Lifecycle, shutdowner fx.Shutdowner, store dependencies.IStore, l *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// The rebuild can outlast the start timeout, so it runs on its own context.
+			go func() {
+				l.Info("Rebuilding candles...")
+
+				count, err := store.RebuildCandles(context.Background())
+				if err != nil {
+					l.Error("Failed to rebuild candles", zap.Error(err))
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+					return
+				}
+
+				l.Info("Rebuilt candles", zap.Int("trades", count))
+				_ = shutdowner.Shutdown()
+			}()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"github.com/go-jet/jet/v2/generator/metadata"
+	jet_postgres "github.com/go-jet/jet/v2/generator/postgres"
+	"github.com/go-jet/jet/v2/generator/template"
+	"github.com/go-jet/jet/v2/postgres"
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"log"
+)
+
+// jet regenerates go-jet SQL builders and models for the database schema.
+// It differs from the stock jet CLI only in mapping NUMERIC columns to decimal.Decimal.
+func main() {
+	dsn := flag.String("dsn", "", "PostgreSQL connection string")
+	schema := flag.String("schema", "public", "database schema")
+	path := flag.String("path", "./.gen", "destination directory")
+	flag.Parse()
+
+	if err := jet_postgres.GenerateDSN(*dsn, *schema, *path, buildTemplate()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func buildTemplate() template.Template {
+	return template.Default(postgres.Dialect).
+		UseSchema(func(schema metadata.Schema) template.Schema {
+			return template.DefaultSchema(schema).
+				UseModel(template.DefaultModel().
+					UseTable(func(table metadata.Table) template.TableModel {
+						return template.DefaultTableModel(table).
+							UseField(modelField)
+					}),
+				)
+		})
+}
+
+func modelField(column metadata.Column) template.TableModelField {
+	field := template.DefaultTableModelField(column)
+
+	if column.DataType.Name != "numeric" {
+		return field
+	}
+
+	if column.IsNullable {
+		return field.UseType(template.NewType(&decimal.Decimal{}))
+	}
+	return field.UseType(template.NewType(decimal.Decimal{}))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"go.uber.org/fx"
+	"vitalik_backend/internal/fxmodules/configfx"
 	"vitalik_backend/internal/fxmodules/loggerfx"
 	"vitalik_backend/internal/fxmodules/matcherfx"
 	"vitalik_backend/internal/fxmodules/serverfx"
@@ -10,11 +11,16 @@ import (
 )
 
 func main() {
-	fx.New(
+	fx.New(buildFxOptions()).Run()
+}
+
+func buildFxOptions() fx.Option {
+	return fx.Options(
 		loggerfx.Module,
+		configfx.Module,
 		storefx.Module,
 		servicesfx.Module,
 		serverfx.Module,
 		matcherfx.Module,
-	).Run()
+	)
 }
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE NUMERIC;
+
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE NUMERIC;
+
+ALTER TABLE orders
+    ALTER COLUMN sell_quantity TYPE NUMERIC,
+    ALTER COLUMN price TYPE NUMERIC,
+    ALTER COLUMN buy_quantity TYPE NUMERIC;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE DOUBLE PRECISION;
+
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE DOUBLE PRECISION;
+
+ALTER TABLE orders
+    ALTER COLUMN sell_quantity TYPE DOUBLE PRECISION,
+    ALTER COLUMN price TYPE DOUBLE PRECISION,
+    ALTER COLUMN buy_quantity TYPE DOUBLE PRECISION;
+-- +goose StatementEnd
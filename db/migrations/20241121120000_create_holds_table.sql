@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE holds
+(
+    order_id    UUID PRIMARY KEY,
+
+    address     TEXT        NOT NULL,
+    currency    TEXT        NOT NULL,
+    amount      NUMERIC     NOT NULL,
+
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL,
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX holds_active_address_idx ON holds (address) WHERE released_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE holds;
+-- +goose StatementEnd
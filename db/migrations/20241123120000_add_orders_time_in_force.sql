@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN time_in_force TEXT NOT NULL DEFAULT 'GTC',
+    ADD COLUMN expires_at    TIMESTAMPTZ,
+    ADD COLUMN expired_at    TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP COLUMN time_in_force,
+    DROP COLUMN expires_at,
+    DROP COLUMN expired_at;
+-- +goose StatementEnd
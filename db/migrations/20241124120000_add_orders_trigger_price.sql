@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN trigger_price NUMERIC,
+    ADD COLUMN triggered_at  TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP COLUMN trigger_price,
+    DROP COLUMN triggered_at;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE trades
+(
+    id                   UUID PRIMARY KEY,
+
+    base_currency        TEXT        NOT NULL,
+    quote_currency       TEXT        NOT NULL,
+
+    buy_order_id         UUID        NOT NULL,
+    sell_order_id        UUID        NOT NULL,
+    buyer_user_id        TEXT        NOT NULL,
+    seller_user_id       TEXT        NOT NULL,
+
+    price                NUMERIC     NOT NULL,
+    quantity             NUMERIC     NOT NULL,
+    quote_quantity       NUMERIC     NOT NULL,
+    maker_side           TEXT        NOT NULL,
+
+    base_transaction_id  UUID        NOT NULL,
+    quote_transaction_id UUID        NOT NULL,
+
+    created_at           TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX trades_pair_idx ON trades (base_currency, quote_currency, id DESC);
+CREATE INDEX trades_buyer_user_id_idx ON trades (buyer_user_id, id DESC);
+CREATE INDEX trades_seller_user_id_idx ON trades (seller_user_id, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE trades;
+-- +goose StatementEnd
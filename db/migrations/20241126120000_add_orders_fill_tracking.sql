@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN original_quantity     NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN filled_quantity       NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN filled_quote_quantity NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN remaining_quantity    NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN average_price         NUMERIC;
+
+-- Earlier fills overwrote the order quantities with what was left, so existing
+-- orders start their history from the quantity they have now.
+UPDATE orders
+SET original_quantity  = COALESCE(CASE WHEN type = 'BUY' AND kind NOT IN ('MARKET', 'STOP_MARKET', 'TAKE_PROFIT') THEN buy_quantity ELSE sell_quantity END, 0),
+    remaining_quantity = COALESCE(CASE WHEN type = 'BUY' AND kind NOT IN ('MARKET', 'STOP_MARKET', 'TAKE_PROFIT') THEN buy_quantity ELSE sell_quantity END, 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP COLUMN original_quantity,
+    DROP COLUMN filled_quantity,
+    DROP COLUMN filled_quote_quantity,
+    DROP COLUMN remaining_quantity,
+    DROP COLUMN average_price;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE candles
+(
+    base_currency  TEXT        NOT NULL,
+    quote_currency TEXT        NOT NULL,
+    interval       TEXT        NOT NULL,
+    open_time      TIMESTAMPTZ NOT NULL,
+
+    open           NUMERIC     NOT NULL,
+    high           NUMERIC     NOT NULL,
+    low            NUMERIC     NOT NULL,
+    close          NUMERIC     NOT NULL,
+    volume         NUMERIC     NOT NULL,
+    quote_volume   NUMERIC     NOT NULL,
+    trade_count    INTEGER     NOT NULL,
+
+    updated_at     TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (base_currency, quote_currency, interval, open_time)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE candles;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE fee_schedules
+(
+    base_currency  TEXT    NOT NULL,
+    quote_currency TEXT    NOT NULL,
+    tier           TEXT    NOT NULL,
+
+    maker_rate     NUMERIC NOT NULL,
+    taker_rate     NUMERIC NOT NULL,
+
+    PRIMARY KEY (base_currency, quote_currency, tier)
+);
+
+INSERT INTO fee_schedules (base_currency, quote_currency, tier, maker_rate, taker_rate)
+VALUES ('BTC', 'USDT', 'STANDARD', 0.001, 0.002),
+       ('BTC', 'USDT', 'VIP', 0.0005, 0.001),
+       ('BTC', 'USDT', 'MARKET_MAKER', 0, 0.0005),
+       ('ETH', 'USDT', 'STANDARD', 0.001, 0.002),
+       ('ETH', 'USDT', 'VIP', 0.0005, 0.001),
+       ('ETH', 'USDT', 'MARKET_MAKER', 0, 0.0005),
+       ('ETH', 'BTC', 'STANDARD', 0.001, 0.002),
+       ('ETH', 'BTC', 'VIP', 0.0005, 0.001),
+       ('ETH', 'BTC', 'MARKET_MAKER', 0, 0.0005);
+
+ALTER TABLE users
+    ADD COLUMN fee_tier TEXT NOT NULL DEFAULT 'STANDARD';
+
+-- The exchange owns the fee wallets. Its password hash matches no password,
+-- and the user ID can no longer be registered.
+INSERT INTO users (user_id, hashed_password)
+VALUES ('exchange', '!');
+
+INSERT INTO wallets (address, user_id, currency, balance, created_at, updated_at)
+VALUES ('fee-BTC', 'exchange', 'BTC', 0, NOW(), NOW()),
+       ('fee-USDT', 'exchange', 'USDT', 0, NOW(), NOW()),
+       ('fee-ETH', 'exchange', 'ETH', 0, NOW(), NOW());
+
+ALTER TABLE transactions
+    ADD COLUMN fee NUMERIC NOT NULL DEFAULT 0;
+
+ALTER TABLE trades
+    ADD COLUMN buyer_fee  NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN seller_fee NUMERIC NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE trades
+    DROP COLUMN buyer_fee,
+    DROP COLUMN seller_fee;
+
+ALTER TABLE transactions
+    DROP COLUMN fee;
+
+DELETE FROM wallets WHERE user_id = 'exchange';
+DELETE FROM users WHERE user_id = 'exchange';
+
+ALTER TABLE users
+    DROP COLUMN fee_tier;
+
+DROP TABLE fee_schedules;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE withdrawals
+(
+    id          UUID PRIMARY KEY,
+
+    user_id     TEXT        NOT NULL,
+    address     TEXT        NOT NULL,
+    currency    TEXT        NOT NULL,
+    amount      NUMERIC     NOT NULL,
+    destination TEXT        NOT NULL,
+
+    status      TEXT        NOT NULL,
+    reference   TEXT,
+    reason      TEXT,
+    reviewed_by TEXT,
+
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX withdrawals_user_id_idx ON withdrawals (user_id, id DESC);
+CREATE INDEX withdrawals_status_idx ON withdrawals (status, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE withdrawals;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE deposits
+(
+    tx_hash        TEXT PRIMARY KEY,
+
+    user_id        TEXT        NOT NULL,
+    address        TEXT        NOT NULL,
+    currency       TEXT        NOT NULL,
+    amount         NUMERIC     NOT NULL,
+
+    confirmations  INTEGER     NOT NULL,
+    status         TEXT        NOT NULL,
+    transaction_id UUID,
+
+    created_at     TIMESTAMPTZ NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL,
+    credited_at    TIMESTAMPTZ
+);
+
+CREATE INDEX deposits_user_id_idx ON deposits (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE deposits;
+-- +goose StatementEnd
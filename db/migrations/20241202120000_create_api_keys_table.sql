@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_keys
+(
+    id          UUID PRIMARY KEY,
+
+    user_id     TEXT        NOT NULL,
+    name        TEXT        NOT NULL,
+    secret_hash TEXT        NOT NULL,
+
+    scopes      TEXT        NOT NULL,
+    allowed_ips TEXT        NOT NULL,
+
+    created_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_keys;
+-- +goose StatementEnd
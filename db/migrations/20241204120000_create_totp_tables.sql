@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE totp_enrollments
+(
+    user_id      TEXT PRIMARY KEY,
+    secret       TEXT        NOT NULL,
+    last_step    BIGINT      NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL,
+    confirmed_at TIMESTAMPTZ
+);
+
+CREATE TABLE totp_recovery_codes
+(
+    id         UUID PRIMARY KEY,
+    user_id    TEXT        NOT NULL,
+    code_hash  TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+
+    UNIQUE (user_id, code_hash)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE totp_recovery_codes;
+DROP TABLE totp_enrollments;
+-- +goose StatementEnd
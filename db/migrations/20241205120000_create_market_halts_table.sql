@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE market_halts
+(
+    base_currency  TEXT        NOT NULL,
+    quote_currency TEXT        NOT NULL,
+    halted_at      TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (base_currency, quote_currency)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE market_halts;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestDepositCreditsAnyWallet(t *testing.T) {
+	app, _ := newTestApplication()
+
+	// The faucet is routed to admins only, who may credit any user's wallet.
+	rec := serve(t, app.Deposit(), http.MethodPut, fmt.Sprintf(`{"address": %q, "currency": "BTC", "amount": "1"}`, malloryBTC))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestListAllWallets(t *testing.T) {
+	app, _ := newTestApplication()
+
+	rec := serve(t, app.ListAllWallets(), http.MethodPost, `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), aliceBTC)
+	require.Contains(t, rec.Body.String(), malloryBTC)
+
+	rec = serve(t, app.ListAllWallets(), http.MethodPost, `{"user_ids_in": ["mallory"]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), aliceBTC)
+}
+
+func TestSetUserRole(t *testing.T) {
+	app, _ := newTestApplication()
+
+	rec := serve(t, app.SetUserRole(), http.MethodPost, `{"user_id": "mallory", "role": "SUPPORT"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(t, app.SetUserRole(), http.MethodPost, `{"user_id": "mallory", "role": "ROOT"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(t, app.SetUserRole(), http.MethodPost, `{"user_id": "nobody", "role": "ADMIN"}`)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Admins cannot demote themselves.
+	rec = serve(t, app.SetUserRole(), http.MethodPost, `{"user_id": "alice", "role": "USER"}`)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+}
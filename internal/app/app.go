@@ -8,11 +8,15 @@ import (
 
 // Application holds the application state and dependencies
 type Application struct {
-	Logger           *zap.SugaredLogger
-	WalletService    dependencies.IWalletService
-	OrderBookManager dependencies.IOrderBookManager
-	Store            dependencies.IStore
-	AuthService      dependencies.IAuthService
+	Logger            *zap.SugaredLogger
+	WalletService     dependencies.IWalletService
+	OrderBookManager  dependencies.IOrderBookManager
+	Store             dependencies.IStore
+	AuthService       dependencies.IAuthService
+	TickerService     dependencies.ITickerService
+	StreamService     dependencies.IStreamService
+	WithdrawalService dependencies.IWithdrawalService
+	APIKeyService     dependencies.IAPIKeyService
 }
 
 // NewApplication initializes a new Application instance
@@ -22,21 +26,33 @@ func NewApplication(
 	orderBookManager dependencies.IOrderBookManager,
 	store dependencies.IStore,
 	authService dependencies.IAuthService,
+	tickerService dependencies.ITickerService,
+	streamService dependencies.IStreamService,
+	withdrawalService dependencies.IWithdrawalService,
+	apiKeyService dependencies.IAPIKeyService,
 ) (*Application, error) {
 	if logger == nil ||
 		walletService == nil ||
 		orderBookManager == nil ||
 		store == nil ||
-		authService == nil {
+		authService == nil ||
+		tickerService == nil ||
+		streamService == nil ||
+		withdrawalService == nil ||
+		apiKeyService == nil {
 		return nil, errors.New("failed to initialize application")
 	}
 
 	return &Application{
-		Logger:           logger.Sugar(),
-		WalletService:    walletService,
-		OrderBookManager: orderBookManager,
-		Store:            store,
-		AuthService:      authService,
+		Logger:            logger.Sugar(),
+		WalletService:     walletService,
+		OrderBookManager:  orderBookManager,
+		Store:             store,
+		AuthService:       authService,
+		TickerService:     tickerService,
+		StreamService:     streamService,
+		WithdrawalService: withdrawalService,
+		APIKeyService:     apiKeyService,
 	}, nil
 }
 
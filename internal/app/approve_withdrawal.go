@@ -0,0 +1,72 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/store"
+	withdrawal_service_types "vitalik_backend/internal/pkg/services/withdrawal_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type reviewWithdrawalRequest struct {
+	ID     uuid.UUID   `json:"id"`
+	Reason null.String `json:"reason"`
+}
+
+// ApproveWithdrawal approves a pending withdrawal and sends it to the payout backend.
+func (app *Application) ApproveWithdrawal() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		args, code, err := bindReviewWithdrawalArgs(c)
+		if err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		withdrawal, err := app.WithdrawalService.ApproveWithdrawal(ctx, args)
+		if err != nil {
+			return reviewWithdrawalError(c, "WithdrawalService.ApproveWithdrawal", err)
+		}
+
+		return c.JSON(http.StatusOK, withdrawal)
+	}
+}
+
+func bindReviewWithdrawalArgs(c echo.Context) (withdrawal_service_types.ReviewWithdrawalArgs, int, error) {
+	var req reviewWithdrawalRequest
+	if err := c.Bind(&req); err != nil {
+		return withdrawal_service_types.ReviewWithdrawalArgs{}, http.StatusBadRequest, err
+	}
+
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return withdrawal_service_types.ReviewWithdrawalArgs{}, http.StatusUnauthorized, errors.New("user is not authenticated")
+	}
+
+	if req.ID == uuid.Nil {
+		return withdrawal_service_types.ReviewWithdrawalArgs{}, http.StatusBadRequest, errors.New("id must be provided")
+	}
+
+	return withdrawal_service_types.ReviewWithdrawalArgs{
+		ID:      req.ID,
+		AdminID: userID,
+		Reason:  req.Reason,
+	}, http.StatusOK, nil
+}
+
+func reviewWithdrawalError(c echo.Context, method string, err error) error {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "withdrawal not found"})
+	case errors.Is(err, types.ErrWithdrawalTransition):
+		return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	}
+
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"message": fmt.Sprintf("%s failed: %v", method, err),
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"vitalik_backend/internal/pkg/services/order_book_manager"
 	"vitalik_backend/internal/pkg/types"
 )
 
@@ -14,6 +15,7 @@ type removeOrderRequest struct {
 	OrderID      string             `param:"id"`
 }
 
+// CancelOrder cancels an order of the authenticated user.
 func (app *Application) CancelOrder() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -35,11 +37,33 @@ func (app *Application) CancelOrder() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid order order ID"})
 		}
 
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		order, err := app.OrderBookManager.GetOrder(ctx, req.CurrencyPair, orderID)
+		if err != nil {
+			if errors.Is(err, echo.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "order not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("OrderBookManager.GetOrder failed: %s", err.Error()),
+			})
+		}
+
+		if code, err := authorizeUserIDs(userID, order.SellRequisites.UserID); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
 		err = app.OrderBookManager.CancelOrder(ctx, req.CurrencyPair, orderID)
 		if err != nil {
 			if errors.Is(err, echo.ErrNotFound) {
 				return c.JSON(http.StatusNotFound, map[string]string{"message": "order book not found"})
 			}
+			if errors.Is(err, order_book_manager.ErrOrderNotCancellable) {
+				return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+			}
 			return c.JSON(
 				http.StatusInternalServerError, map[string]string{
 					"message": fmt.Sprintf("OrderBookManager.CancelOrder failed: %s", err.Error()),
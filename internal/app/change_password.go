@@ -0,0 +1,50 @@
+package app
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/auth_service"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+)
+
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword replaces the password of the authenticated user. It is routed
+// behind a fresh TOTP code.
+func (app *Application) ChangePassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req changePasswordRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if req.OldPassword == "" || req.NewPassword == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "old_password and new_password must be provided"})
+		}
+
+		err := app.AuthService.ChangePassword(ctx, auth_types.ChangePasswordArgs{
+			UserID:      userID,
+			OldPassword: req.OldPassword,
+			NewPassword: req.NewPassword,
+		})
+		if err != nil {
+			if errors.Is(err, auth_service.ErrInvalidPassword) {
+				return c.JSON(http.StatusForbidden, map[string]string{"message": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "password changed successfully"})
+	}
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/auth_service"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+)
+
+type confirmTOTPRequest struct {
+	Code string `json:"code"`
+}
+
+// ConfirmTOTP enables two-factor authentication with a first code of the enrolled
+// authenticator. The response holds the recovery codes, shown only once.
+func (app *Application) ConfirmTOTP() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req confirmTOTPRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if req.Code == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "code must be provided"})
+		}
+
+		resp, err := app.AuthService.ConfirmTOTP(ctx, auth_types.ConfirmTOTPArgs{
+			UserID: userID,
+			Code:   req.Code,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, auth_service.ErrInvalidOTP):
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+			case errors.Is(err, auth_service.ErrTOTPNotEnrolled):
+				return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+			case errors.Is(err, auth_service.ErrTOTPAlreadyEnabled):
+				return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"net/http"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const (
+	maxAPIKeyNameLength = 64
+	maxAllowedIPs       = 16
+)
+
+type createAPIKeyRequest struct {
+	Name       string              `json:"name"`
+	Scopes     []types.APIKeyScope `json:"scopes"`
+	AllowedIPs []string            `json:"allowed_ips"`
+}
+
+// CreateAPIKey creates an API key of the authenticated user. The secret is in the
+// response only, it cannot be shown again.
+func (app *Application) CreateAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req createAPIKeyRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := app.validateCreateAPIKeyRequest(ctx, &req); err != nil {
+			return c.JSON(code, map[string]string{
+				"message": fmt.Sprintf("validateCreateAPIKeyRequest failed: %v", err),
+			})
+		}
+
+		resp, err := app.APIKeyService.CreateAPIKey(ctx, api_key_service_types.CreateAPIKeyArgs{
+			UserID:     userID,
+			Name:       req.Name,
+			Scopes:     lo.Uniq(req.Scopes),
+			AllowedIPs: lo.Uniq(req.AllowedIPs),
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("APIKeyService.CreateAPIKey failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (app *Application) validateCreateAPIKeyRequest(ctx context.Context, req *createAPIKeyRequest) (int, error) {
+	if req.Name == "" {
+		return http.StatusBadRequest, errors.New("name must be provided")
+	} else if len(req.Name) > maxAPIKeyNameLength {
+		return http.StatusBadRequest, fmt.Errorf("name must be at most %d characters", maxAPIKeyNameLength)
+	}
+
+	if len(req.Scopes) == 0 {
+		return http.StatusBadRequest, errors.New("scopes must be provided")
+	}
+
+	for _, scope := range req.Scopes {
+		if !scope.Validate() {
+			return http.StatusBadRequest, fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+
+	if len(req.AllowedIPs) > maxAllowedIPs {
+		return http.StatusBadRequest, fmt.Errorf("at most %d allowed_ips can be set", maxAllowedIPs)
+	}
+
+	for _, allowed := range req.AllowedIPs {
+		if !types.ValidateAllowedIP(allowed) {
+			return http.StatusBadRequest, fmt.Errorf("invalid allowed ip: %s", allowed)
+		}
+	}
+
+	return http.StatusOK, nil
+}
@@ -1,28 +1,41 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/guregu/null/v5"
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
 	"net/http"
 	"time"
 	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	"vitalik_backend/internal/pkg/services/order_book_manager"
+	"vitalik_backend/internal/pkg/services/store"
 	"vitalik_backend/internal/pkg/types"
 )
 
 type createOrderRequest struct {
 	Type types.OrderType `json:"type"`
+	Kind types.OrderKind `json:"kind"`
 
-	SellCurrency   types.Currency   `json:"sell_currency"`
-	SellQuantity   null.Float       `json:"sell_quantity"`
-	SellRequisites types.Requisites `json:"sell_requisites"`
+	TimeInForce types.TimeInForce `json:"time_in_force"`
+	ExpiresAt   null.Time         `json:"expires_at"`
 
-	Price float64
+	SellCurrency   types.Currency      `json:"sell_currency"`
+	SellQuantity   decimal.NullDecimal `json:"sell_quantity"`
+	SellRequisites types.Requisites    `json:"sell_requisites"`
 
-	BuyCurrency   types.Currency   `json:"buy_currency"`
-	BuyQuantity   null.Float       `json:"buy_quantity"`
-	BuyRequisites types.Requisites `json:"buy_requisites"`
+	Price        decimal.Decimal     `json:"price"`
+	TriggerPrice decimal.NullDecimal `json:"trigger_price"`
+
+	BuyCurrency   types.Currency      `json:"buy_currency"`
+	BuyQuantity   decimal.NullDecimal `json:"buy_quantity"`
+	BuyRequisites types.Requisites    `json:"buy_requisites"`
 }
 
+// CreateOrder places an order between two wallets of the authenticated user.
 func (app *Application) CreateOrder() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -31,12 +44,35 @@ func (app *Application) CreateOrder() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
 		}
 
+		if code, err := app.validateCreateOrderRequest(ctx, &req); err != nil {
+			return c.JSON(code, map[string]string{
+				"message": fmt.Sprintf("validateCreateOrderRequest failed: %v", err),
+			})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := authorizeUserIDs(userID, req.SellRequisites.UserID, req.BuyRequisites.UserID); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		if code, err := app.authorizeWallets(ctx, userID, req.SellRequisites.Address, req.BuyRequisites.Address); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
 		args := order_book_types.CreateOrderArgs{
 			Type:           req.Type,
+			Kind:           req.Kind,
+			TimeInForce:    req.TimeInForce,
+			ExpiresAt:      req.ExpiresAt,
 			SellCurrency:   req.SellCurrency,
 			SellQuantity:   req.SellQuantity,
 			SellRequisites: req.SellRequisites,
 			Price:          req.Price,
+			TriggerPrice:   req.TriggerPrice,
 			BuyCurrency:    req.BuyCurrency,
 			BuyQuantity:    req.BuyQuantity,
 			BuyRequisites:  req.BuyRequisites,
@@ -46,9 +82,158 @@ func (app *Application) CreateOrder() echo.HandlerFunc {
 
 		order, err := app.OrderBookManager.CreateOrder(ctx, args)
 		if err != nil {
+			if errors.Is(err, order_book_manager.ErrOrderBookEmpty) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "order book is empty"})
+			}
+			if errors.Is(err, order_book_manager.ErrMarketHalted) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "market is halted"})
+			}
+			if errors.Is(err, order_book_manager.ErrOrderWouldTrigger) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "order would trigger immediately"})
+			}
+			if errors.Is(err, store.ErrInsufficientFunds) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "insufficient funds"})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
 		}
 
 		return c.JSON(http.StatusOK, order)
 	}
 }
+
+func (app *Application) validateCreateOrderRequest(ctx context.Context, req *createOrderRequest) (int, error) {
+	if !req.SellCurrency.Validate() {
+		return http.StatusBadRequest, fmt.Errorf("invalid sell_currency: %s", req.SellCurrency)
+	}
+
+	if !req.BuyCurrency.Validate() {
+		return http.StatusBadRequest, fmt.Errorf("invalid buy_currency: %s", req.BuyCurrency)
+	}
+
+	if req.Kind == "" {
+		req.Kind = types.Limit
+	}
+
+	if !req.Kind.Validate() {
+		return http.StatusBadRequest, fmt.Errorf("invalid order kind: %s", req.Kind)
+	}
+
+	if code, err := validateTimeInForce(req); err != nil {
+		return code, err
+	}
+
+	if code, err := validateTriggerPrice(req); err != nil {
+		return code, err
+	}
+
+	if req.Kind.IsMarket() {
+		return validateMarketOrderRequest(req)
+	}
+
+	var baseCurrency, quoteCurrency types.Currency
+	var quantity decimal.NullDecimal
+
+	switch req.Type {
+	case types.Buy:
+		baseCurrency, quoteCurrency, quantity = req.BuyCurrency, req.SellCurrency, req.BuyQuantity
+	case types.Sell:
+		baseCurrency, quoteCurrency, quantity = req.SellCurrency, req.BuyCurrency, req.SellQuantity
+	default:
+		return http.StatusBadRequest, fmt.Errorf("invalid order type: %s", req.Type)
+	}
+
+	if !quantity.Valid {
+		return http.StatusBadRequest, errors.New("order quantity must be provided")
+	}
+
+	if err := baseCurrency.ValidateAmount(quantity.Decimal); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid quantity: %w", err)
+	}
+
+	if err := quoteCurrency.ValidateAmount(req.Price); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid price: %w", err)
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateMarketOrderRequest checks a market order, which has no price and spends
+// its sell quantity: the base quantity for sells and the quote budget for buys.
+func validateMarketOrderRequest(req *createOrderRequest) (int, error) {
+	if req.Type != types.Buy && req.Type != types.Sell {
+		return http.StatusBadRequest, fmt.Errorf("invalid order type: %s", req.Type)
+	}
+
+	if !req.Price.IsZero() {
+		return http.StatusBadRequest, errors.New("market order must not have a price")
+	}
+
+	if req.BuyQuantity.Valid {
+		return http.StatusBadRequest, errors.New("market order must not have buy_quantity")
+	}
+
+	if !req.SellQuantity.Valid {
+		return http.StatusBadRequest, errors.New("market order sell_quantity must be provided")
+	}
+
+	if err := req.SellCurrency.ValidateAmount(req.SellQuantity.Decimal); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid sell_quantity: %w", err)
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateTimeInForce defaults the time in force to IOC for market priced orders and GTC
+// for limit orders, and checks that only GTD orders carry an expiry time.
+func validateTimeInForce(req *createOrderRequest) (int, error) {
+	if req.TimeInForce == "" {
+		req.TimeInForce = lo.Ternary(req.Kind.IsMarket(), types.IOC, types.GTC)
+	}
+
+	if !req.TimeInForce.Validate() {
+		return http.StatusBadRequest, fmt.Errorf("invalid time_in_force: %s", req.TimeInForce)
+	}
+
+	if req.Kind.IsMarket() && req.TimeInForce != types.IOC && req.TimeInForce != types.FOK {
+		return http.StatusBadRequest, fmt.Errorf("market order can not be %s", req.TimeInForce)
+	}
+
+	if req.TimeInForce != types.GTD {
+		if req.ExpiresAt.Valid {
+			return http.StatusBadRequest, errors.New("expires_at is only allowed for GTD orders")
+		}
+		return http.StatusOK, nil
+	}
+
+	if !req.ExpiresAt.Valid {
+		return http.StatusBadRequest, errors.New("GTD order expires_at must be provided")
+	}
+
+	if !req.ExpiresAt.Time.After(time.Now()) {
+		return http.StatusBadRequest, errors.New("GTD order expires_at must be in the future")
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateTriggerPrice checks that exactly the stop and take-profit orders carry a
+// trigger price, given in the quote currency.
+func validateTriggerPrice(req *createOrderRequest) (int, error) {
+	if !req.Kind.IsTrigger() {
+		if req.TriggerPrice.Valid {
+			return http.StatusBadRequest, fmt.Errorf("%s order must not have trigger_price", req.Kind)
+		}
+		return http.StatusOK, nil
+	}
+
+	if !req.TriggerPrice.Valid {
+		return http.StatusBadRequest, fmt.Errorf("%s order trigger_price must be provided", req.Kind)
+	}
+
+	quoteCurrency := lo.Ternary(req.Type == types.Buy, req.SellCurrency, req.BuyCurrency)
+	if err := quoteCurrency.ValidateAmount(req.TriggerPrice.Decimal); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid trigger_price: %w", err)
+	}
+
+	return http.StatusOK, nil
+}
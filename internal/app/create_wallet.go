@@ -15,22 +15,37 @@ type createWalletRequest struct {
 	Currency types.Currency `json:"currency"`
 }
 
+// CreateWallet creates a wallet for the authenticated user. The user_id may be
+// omitted, any other user than the authenticated one is forbidden.
 func (app *Application) CreateWallet() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		var req *createWalletRequest
+		var req createWalletRequest
 		if err := c.Bind(&req); err != nil {
 			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
 		}
 
-		if code, err := app.validateCreateWalletRequest(ctx, req); err != nil {
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if req.UserID == "" {
+			req.UserID = userID
+		}
+
+		if code, err := authorizeUserIDs(userID, req.UserID); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		if code, err := app.validateCreateWalletRequest(ctx, &req); err != nil {
 			return c.JSON(code, map[string]string{
 				"message": fmt.Sprintf("validateCreateWalletRequest failed: %v", err),
 			})
 		}
 
-		args := bindCreateWalletArgs(req)
+		args := bindCreateWalletArgs(&req)
 
 		wallet, err := app.WalletService.CreateWallet(ctx, args)
 		if err != nil {
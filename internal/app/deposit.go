@@ -5,19 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 	"net/http"
 	"regexp"
 	"time"
+	"vitalik_backend/internal/pkg/services/store"
 	store_types "vitalik_backend/internal/pkg/services/store/types"
 	"vitalik_backend/internal/pkg/types"
 )
 
 type depositRequest struct {
-	Address  string         `json:"address"`
-	Currency types.Currency `json:"currency"`
-	Amount   float64        `json:"amount"`
+	Address  string          `json:"address"`
+	Currency types.Currency  `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
 }
 
+// Deposit credits any wallet with any amount. It is an admin faucet for development
+// and is only routed when the deposit faucet is enabled, real deposits are
+// credited by the deposit service.
 func (app *Application) Deposit() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -37,6 +42,9 @@ func (app *Application) Deposit() echo.HandlerFunc {
 
 		tx, err := app.Store.Deposit(ctx, args)
 		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "wallet not found"})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{
 				"message": fmt.Sprintf("Store Deposit failed: %v", err),
 			})
@@ -60,8 +68,8 @@ func (app *Application) validateDepositRequest(ctx context.Context, req *deposit
 		return http.StatusBadRequest, fmt.Errorf("invalid currency: %s", req.Currency)
 	}
 
-	if req.Amount <= 0 {
-		return http.StatusBadRequest, errors.New("amount must be greater than zero")
+	if err := req.Currency.ValidateAmount(req.Amount); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid amount: %w", err)
 	}
 
 	return http.StatusOK, nil
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/auth_service"
+)
+
+// DisableTOTP removes the authenticator and the recovery codes of the
+// authenticated user. It is routed behind a fresh TOTP code.
+func (app *Application) DisableTOTP() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if err := app.AuthService.DisableTOTP(ctx, userID); err != nil {
+			if errors.Is(err, auth_service.ErrTOTPNotEnrolled) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "two-factor authentication disabled successfully"})
+	}
+}
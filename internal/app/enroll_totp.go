@@ -0,0 +1,31 @@
+package app
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/auth_service"
+)
+
+// EnrollTOTP starts enrolling an authenticator of the authenticated user. The
+// response holds the secret and the otpauth URI for the QR code.
+func (app *Application) EnrollTOTP() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		resp, err := app.AuthService.EnrollTOTP(ctx, userID)
+		if err != nil {
+			if errors.Is(err, auth_service.ErrTOTPAlreadyEnabled) {
+				return c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"net/http"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const (
+	defaultDepthLimit = 50
+	maxDepthLimit     = 500
+)
+
+type getDepthRequest struct {
+	CurrencyPair types.CurrencyPair `json:"currency_pair"`
+
+	Limit int                 `json:"limit"`
+	Tick  decimal.NullDecimal `json:"tick"`
+}
+
+// GetDepth returns the bids and asks of a currency pair aggregated into price levels.
+func (app *Application) GetDepth() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req getDepthRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if !req.CurrencyPair.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
+		}
+
+		if code, err := validateDepthRequest(&req); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		args := order_book_types.GetDepthArgs{
+			CurrencyPair: req.CurrencyPair,
+			Limit:        req.Limit,
+			Tick:         req.Tick,
+		}
+
+		depth, err := app.OrderBookManager.GetDepth(ctx, args)
+		if err != nil {
+			if errors.Is(err, echo.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "order book not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("OrderBookManager.GetDepth failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, depth)
+	}
+}
+
+func validateDepthRequest(req *getDepthRequest) (int, error) {
+	if req.Limit == 0 {
+		req.Limit = defaultDepthLimit
+	}
+
+	if req.Limit < 0 || req.Limit > maxDepthLimit {
+		return http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxDepthLimit)
+	}
+
+	if req.Tick.Valid && !req.Tick.Decimal.IsPositive() {
+		return http.StatusBadRequest, errors.New("tick must be positive")
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type getFeeRateRequest struct {
+	CurrencyPair types.CurrencyPair `json:"currency_pair"`
+}
+
+// GetFeeRate returns the maker and taker fee rates the authenticated user currently pays on a currency pair.
+func (app *Application) GetFeeRate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req getFeeRateRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if !req.CurrencyPair.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
+		}
+
+		user, err := app.Store.GetUser(ctx, userID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.GetUser failed: %v", err),
+			})
+		}
+
+		schedule, err := app.Store.GetFeeSchedule(ctx, store_types.GetFeeScheduleArgs{
+			CurrencyPair: req.CurrencyPair,
+			Tier:         user.FeeTier,
+		})
+		if errors.Is(err, store.ErrNotFound) {
+			// Pairs without a schedule are traded without fees.
+			schedule = &types.FeeSchedule{CurrencyPair: req.CurrencyPair, Tier: user.FeeTier}
+		} else if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.GetFeeSchedule failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, schedule)
+	}
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type getTickerRequest struct {
+	CurrencyPair types.CurrencyPair `json:"currency_pair"`
+}
+
+// GetTicker returns the 24-hour statistics of a currency pair.
+func (app *Application) GetTicker() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req getTickerRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if !req.CurrencyPair.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
+		}
+
+		ticker, err := app.TickerService.GetTicker(ctx, req.CurrencyPair)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("TickerService.GetTicker failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, ticker)
+	}
+}
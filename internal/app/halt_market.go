@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type haltMarketRequest struct {
+	CurrencyPair types.CurrencyPair `json:"currency_pair"`
+}
+
+// HaltMarket stops trading of a currency pair until it is resumed.
+func (app *Application) HaltMarket() echo.HandlerFunc {
+	return app.setMarketHalted(true)
+}
+
+// ResumeMarket resumes trading of a halted currency pair.
+func (app *Application) ResumeMarket() echo.HandlerFunc {
+	return app.setMarketHalted(false)
+}
+
+func (app *Application) setMarketHalted(halted bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req haltMarketRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if !req.CurrencyPair.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
+		}
+
+		if err := app.OrderBookManager.SetMarketHalted(ctx, req.CurrencyPair, halted); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("OrderBookManager.SetMarketHalted failed: %v", err),
+			})
+		}
+
+		if halted {
+			return c.JSON(http.StatusOK, map[string]string{"message": "market halted successfully"})
+		}
+		return c.JSON(http.StatusOK, map[string]string{"message": "market resumed successfully"})
+	}
+}
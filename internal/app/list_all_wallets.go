@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// ListAllWallets lists the wallets of every user, or of the given users and
+// addresses, for support staff.
+func (app *Application) ListAllWallets() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req *listWalletsRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if req == nil {
+			req = &listWalletsRequest{}
+		}
+
+		if code, err := app.validateListWalletsRequest(ctx, req); err != nil {
+			return c.JSON(code, map[string]string{
+				"message": fmt.Sprintf("validateListWalletsRequest failed: %v", err),
+			})
+		}
+
+		wallets, err := app.Store.ListWallets(ctx, bindListWalletsArgs(req))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListWallets failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, wallets)
+	}
+}
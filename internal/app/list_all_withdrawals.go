@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+)
+
+type listAllWithdrawalsRequest struct {
+	listWithdrawalsRequest
+
+	UserID null.String `json:"user_id"`
+}
+
+// ListAllWithdrawals lists the withdrawals of every user, or of one, for admins reviewing them.
+func (app *Application) ListAllWithdrawals() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listAllWithdrawalsRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if code, err := validateListWithdrawalsRequest(&req.listWithdrawalsRequest); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		withdrawals, err := app.Store.ListWithdrawals(ctx, store_types.ListWithdrawalsArgs{
+			UserID:   req.UserID,
+			StatusIn: req.StatusIn,
+			Cursor:   req.Cursor,
+			Limit:    req.Limit + 1,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListWithdrawals failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, bindListWithdrawalsResponse(withdrawals, req.Limit))
+	}
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// ListAPIKeys lists the API keys of the authenticated user, revoked ones included.
+func (app *Application) ListAPIKeys() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		keys, err := app.APIKeyService.ListAPIKeys(ctx, userID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("APIKeyService.ListAPIKeys failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, keys)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const (
+	defaultCandlesLimit = 500
+	maxCandlesLimit     = 1000
+)
+
+type listCandlesRequest struct {
+	CurrencyPair types.CurrencyPair   `json:"currency_pair"`
+	Interval     types.CandleInterval `json:"interval"`
+
+	From  null.Time `json:"from"`
+	To    null.Time `json:"to"`
+	Limit int       `json:"limit"`
+}
+
+// ListCandles lists the candles of a currency pair and interval, oldest first.
+func (app *Application) ListCandles() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listCandlesRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if code, err := validateListCandlesRequest(&req); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		args := store_types.ListCandlesArgs{
+			CurrencyPair: req.CurrencyPair,
+			Interval:     req.Interval,
+			From:         req.From,
+			To:           req.To,
+			Limit:        req.Limit,
+		}
+
+		candles, err := app.Store.ListCandles(ctx, args)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListCandles failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, candles)
+	}
+}
+
+func validateListCandlesRequest(req *listCandlesRequest) (int, error) {
+	if !req.CurrencyPair.Validate() {
+		return http.StatusBadRequest, errors.New("invalid currency_pair")
+	}
+
+	if !req.Interval.Validate() {
+		return http.StatusBadRequest, errors.New("invalid interval")
+	}
+
+	if req.From.Valid && req.To.Valid && req.From.Time.After(req.To.Time) {
+		return http.StatusBadRequest, errors.New("from must not be after to")
+	}
+
+	if req.Limit == 0 {
+		req.Limit = defaultCandlesLimit
+	}
+
+	if req.Limit < 0 || req.Limit > maxCandlesLimit {
+		return http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxCandlesLimit)
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+)
+
+type listDepositsRequest struct {
+	Limit int `json:"limit"`
+}
+
+// ListDeposits lists the deposits of the authenticated user, newest first, with
+// their confirmations so far.
+func (app *Application) ListDeposits() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listDepositsRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := validateTradesLimit(&req.Limit); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		deposits, err := app.Store.ListDeposits(ctx, store_types.ListDepositsArgs{
+			UserID: userID,
+			Limit:  req.Limit,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListDeposits failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, deposits)
+	}
+}
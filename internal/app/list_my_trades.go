@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type listMyTradesRequest struct {
+	CurrencyPair null.Value[types.CurrencyPair] `json:"currency_pair"`
+
+	Cursor uuid.NullUUID `json:"cursor"`
+	Limit  int           `json:"limit"`
+}
+
+// ListMyTrades lists the fills of the authenticated user, optionally for one currency pair.
+func (app *Application) ListMyTrades() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listMyTradesRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if req.CurrencyPair.Valid && !req.CurrencyPair.V.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
+		}
+
+		if code, err := validateTradesLimit(&req.Limit); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		args := store_types.ListTradesArgs{
+			CurrencyPair: req.CurrencyPair,
+			UserID:       null.StringFrom(userID),
+			Cursor:       req.Cursor,
+			Limit:        req.Limit + 1,
+		}
+
+		trades, err := app.Store.ListTrades(ctx, args)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListTrades failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, bindListTradesResponse(trades, req.Limit))
+	}
+}
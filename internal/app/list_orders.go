@@ -18,6 +18,7 @@ type listOrdersRequest struct {
 	OrderStatus null.Value[types.OrderStatus] `json:"order_status,omitempty"`
 }
 
+// ListOrders lists the orders of the authenticated user in a currency pair.
 func (app *Application) ListOrders() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -30,9 +31,18 @@ func (app *Application) ListOrders() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
 		}
 
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := authorizeUserIDs(userID, req.UserIDIn...); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
 		args := order_book_types.ListOrdersArgs{
 			CurrencyPair: req.CurrencyPair,
-			UserIDIn:     req.UserIDIn,
+			UserIDIn:     []string{userID},
 			OrderType:    req.OrderType,
 			OrderStatus:  req.OrderStatus,
 		}
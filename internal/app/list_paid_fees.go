@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+)
+
+type listPaidFeesRequest struct {
+	From null.Time `json:"from"`
+}
+
+// ListPaidFees returns the trading fees the authenticated user paid per currency, optionally since a time.
+func (app *Application) ListPaidFees() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listPaidFeesRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		fees, err := app.Store.ListPaidFees(ctx, store_types.ListPaidFeesArgs{
+			UserID: userID,
+			From:   req.From,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListPaidFees failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, fees)
+	}
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// ListTickers returns the 24-hour statistics of every currency pair.
+func (app *Application) ListTickers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		tickers, err := app.TickerService.ListTickers(ctx)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("TickerService.ListTickers failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, tickers)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const (
+	defaultTradesLimit = 50
+	maxTradesLimit     = 500
+)
+
+type listTradesRequest struct {
+	CurrencyPair types.CurrencyPair `json:"currency_pair"`
+
+	Cursor uuid.NullUUID `json:"cursor"`
+	Limit  int           `json:"limit"`
+}
+
+// listTradesResponse holds a page of trades, newest first. NextCursor is set when
+// there are more trades and should be passed as the cursor of the next request.
+type listTradesResponse struct {
+	Trades     []*types.Trade `json:"trades"`
+	NextCursor uuid.NullUUID  `json:"next_cursor"`
+}
+
+// ListTrades lists the public trades of a currency pair.
+func (app *Application) ListTrades() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listTradesRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if !req.CurrencyPair.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "invalid currency_pair"})
+		}
+
+		if code, err := validateTradesLimit(&req.Limit); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		args := store_types.ListTradesArgs{
+			CurrencyPair: null.ValueFrom(req.CurrencyPair),
+			Cursor:       req.Cursor,
+			Limit:        req.Limit + 1,
+		}
+
+		trades, err := app.Store.ListTrades(ctx, args)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListTrades failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, bindListTradesResponse(trades, req.Limit))
+	}
+}
+
+func validateTradesLimit(limit *int) (int, error) {
+	if *limit == 0 {
+		*limit = defaultTradesLimit
+	}
+
+	if *limit < 0 || *limit > maxTradesLimit {
+		return http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxTradesLimit)
+	}
+
+	return http.StatusOK, nil
+}
+
+// bindListTradesResponse cuts the trades, fetched with one extra row, to the
+// limit and sets the next cursor when the extra row was there.
+func bindListTradesResponse(trades []*types.Trade, limit int) listTradesResponse {
+	if len(trades) <= limit {
+		return listTradesResponse{Trades: trades}
+	}
+
+	trades = trades[:limit]
+
+	return listTradesResponse{
+		Trades:     trades,
+		NextCursor: uuid.NullUUID{UUID: trades[len(trades)-1].ID, Valid: true},
+	}
+}
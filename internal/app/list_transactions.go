@@ -15,6 +15,7 @@ type ListTransactionsRequest struct {
 	UserIdIn   []string `json:"user_id_in"`
 }
 
+// ListTransactions lists the transactions of the authenticated user's wallets.
 func (app *Application) ListTransactions() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -30,6 +31,19 @@ func (app *Application) ListTransactions() echo.HandlerFunc {
 			})
 		}
 
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := authorizeUserIDs(userID, req.UserIdIn...); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		if code, err := app.authorizeWallets(ctx, userID, req.AddresssIn...); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
 		args := bindListTransactionsArgs(req)
 
 		transactions, err := app.Store.ListTransactions(ctx, args)
@@ -16,6 +16,7 @@ type listWalletsRequest struct {
 	UserIDsIn  []string `json:"user_ids_in"`
 }
 
+// ListWallets lists the wallets of the authenticated user.
 func (app *Application) ListWallets() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -35,6 +36,21 @@ func (app *Application) ListWallets() echo.HandlerFunc {
 			})
 		}
 
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := authorizeUserIDs(userID, req.UserIDsIn...); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		if code, err := app.authorizeWallets(ctx, userID, req.AddresssIn...); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		req.UserIDsIn = []string{userID}
+
 		args := bindListWalletsArgs(req)
 
 		wallets, err := app.Store.ListWallets(ctx, args)
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type listWithdrawalsRequest struct {
+	StatusIn []types.WithdrawalStatus `json:"status_in"`
+
+	Cursor uuid.NullUUID `json:"cursor"`
+	Limit  int           `json:"limit"`
+}
+
+// listWithdrawalsResponse holds a page of withdrawals, newest first. NextCursor is
+// set when there are more withdrawals.
+type listWithdrawalsResponse struct {
+	Withdrawals []*types.Withdrawal `json:"withdrawals"`
+	NextCursor  uuid.NullUUID       `json:"next_cursor"`
+}
+
+// ListWithdrawals lists the withdrawals of the authenticated user.
+func (app *Application) ListWithdrawals() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		var req listWithdrawalsRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := validateListWithdrawalsRequest(&req); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		withdrawals, err := app.Store.ListWithdrawals(ctx, store_types.ListWithdrawalsArgs{
+			UserID:   null.StringFrom(userID),
+			StatusIn: req.StatusIn,
+			Cursor:   req.Cursor,
+			Limit:    req.Limit + 1,
+		})
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.ListWithdrawals failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, bindListWithdrawalsResponse(withdrawals, req.Limit))
+	}
+}
+
+func validateListWithdrawalsRequest(req *listWithdrawalsRequest) (int, error) {
+	for _, status := range req.StatusIn {
+		if !status.Validate() {
+			return http.StatusBadRequest, fmt.Errorf("invalid status: %s", status)
+		}
+	}
+
+	return validateTradesLimit(&req.Limit)
+}
+
+// bindListWithdrawalsResponse cuts the withdrawals, fetched with one extra row, to
+// the limit and sets the next cursor when the extra row was there.
+func bindListWithdrawalsResponse(withdrawals []*types.Withdrawal, limit int) listWithdrawalsResponse {
+	if len(withdrawals) <= limit {
+		return listWithdrawalsResponse{Withdrawals: withdrawals}
+	}
+
+	withdrawals = withdrawals[:limit]
+
+	return listWithdrawalsResponse{
+		Withdrawals: withdrawals,
+		NextCursor:  uuid.NullUUID{UUID: withdrawals[len(withdrawals)-1].ID, Valid: true},
+	}
+}
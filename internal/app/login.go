@@ -11,8 +11,11 @@ import (
 type loginRequest struct {
 	UserID   string `json:"user_id"`
 	Password string `json:"password"`
+	OTPCode  string `json:"otp_code"`
 }
 
+// Login issues tokens for valid credentials. Users with two-factor authentication
+// must also send a TOTP or recovery code.
 func (app *Application) Login() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -25,9 +28,13 @@ func (app *Application) Login() echo.HandlerFunc {
 		resp, err := app.AuthService.Login(ctx, auth_types.AuthArgs{
 			UserID:   req.UserID,
 			Password: req.Password,
+			OTPCode:  req.OTPCode,
 		})
 		if err != nil {
-			if errors.Is(err, auth_service.ErrUserNotFound) {
+			if errors.Is(err, auth_service.ErrUserNotFound) ||
+				errors.Is(err, auth_service.ErrInvalidPassword) ||
+				errors.Is(err, auth_service.ErrOTPRequired) ||
+				errors.Is(err, auth_service.ErrInvalidOTP) {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
 			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/auth_service"
+)
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout revokes a refresh token, the access token issued with it expires on its own.
+func (app *Application) Logout() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req logoutRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if err := app.AuthService.Logout(ctx, req.RefreshToken); err != nil {
+			if errors.Is(err, auth_service.ErrInvalidToken) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "logged out successfully"})
+	}
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+)
+
+var errForbidden = errors.New("access to another user's resources is forbidden")
+
+// authorizeUserIDs checks that the user IDs of a request all belong to the
+// authenticated user.
+func authorizeUserIDs(userID string, userIDs ...string) (int, error) {
+	for _, id := range userIDs {
+		if id != userID {
+			return http.StatusForbidden, errForbidden
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// authorizeWallets checks that every address is a wallet of the authenticated user.
+// A wallet that does not exist is reported as not found.
+func (app *Application) authorizeWallets(ctx context.Context, userID string, addresses ...string) (int, error) {
+	if len(addresses) == 0 {
+		return http.StatusOK, nil
+	}
+
+	wallets, err := app.Store.ListWallets(ctx, store_types.ListWalletsArgs{AddresssIn: addresses})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("Store.ListWallets failed: %w", err)
+	}
+
+	owners := make(map[string]string, len(wallets))
+	for _, wallet := range wallets {
+		owners[wallet.Requisites.Address] = wallet.Requisites.UserID
+	}
+
+	for _, address := range addresses {
+		owner, ok := owners[address]
+		if !ok {
+			return http.StatusNotFound, fmt.Errorf("wallet %s not found", address)
+		}
+		if owner != userID {
+			return http.StatusForbidden, errForbidden
+		}
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,257 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"vitalik_backend/internal/dependencies"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	wallet_service_types "vitalik_backend/internal/pkg/services/wallet_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+var (
+	aliceBTC   = strings.Repeat("a", 64)
+	aliceUSDT  = strings.Repeat("b", 64)
+	malloryBTC = strings.Repeat("c", 64)
+)
+
+// fakeStore knows the wallets of alice and mallory and accepts every write.
+type fakeStore struct {
+	dependencies.IStore
+
+	wallets []*types.Wallet
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		wallets: []*types.Wallet{
+			{Requisites: types.Requisites{UserID: "alice", Address: aliceBTC}, Currency: types.BTC},
+			{Requisites: types.Requisites{UserID: "alice", Address: aliceUSDT}, Currency: types.USDT},
+			{Requisites: types.Requisites{UserID: "mallory", Address: malloryBTC}, Currency: types.BTC},
+		},
+	}
+}
+
+func (s *fakeStore) ListWallets(ctx context.Context, args store_types.ListWalletsArgs) ([]*types.Wallet, error) {
+	wallets := make([]*types.Wallet, 0)
+	for _, wallet := range s.wallets {
+		if len(args.AddresssIn) > 0 && !slices.Contains(args.AddresssIn, wallet.Requisites.Address) {
+			continue
+		}
+		if len(args.UserIDsIn) > 0 && !slices.Contains(args.UserIDsIn, wallet.Requisites.UserID) {
+			continue
+		}
+		wallets = append(wallets, wallet)
+	}
+	return wallets, nil
+}
+
+func (s *fakeStore) Transfer(ctx context.Context, args store_types.TransferArgs) (*types.Transaction, error) {
+	return &types.Transaction{}, nil
+}
+
+func (s *fakeStore) Deposit(ctx context.Context, args store_types.DepositArgs) (*types.Transaction, error) {
+	return &types.Transaction{}, nil
+}
+
+func (s *fakeStore) SetUserRole(ctx context.Context, args store_types.SetUserRoleArgs) error {
+	if args.UserID != "alice" && args.UserID != "mallory" {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *fakeStore) ListTransactions(ctx context.Context, args store_types.ListTransactionsArgs) ([]*types.Transaction, error) {
+	return []*types.Transaction{}, nil
+}
+
+type fakeWalletService struct{}
+
+func (s *fakeWalletService) CreateWallet(ctx context.Context, args wallet_service_types.CreateWalletArgs) (types.Wallet, error) {
+	return types.Wallet{Requisites: types.Requisites{UserID: args.UserID}, Currency: args.Currency}, nil
+}
+
+// fakeOrderBookManager holds a single order of mallory.
+type fakeOrderBookManager struct {
+	dependencies.IOrderBookManager
+
+	order types.Order
+}
+
+func (m *fakeOrderBookManager) CreateOrder(ctx context.Context, args order_book_types.CreateOrderArgs) (*types.Order, error) {
+	return &types.Order{ID: uuid.New(), SellRequisites: args.SellRequisites, BuyRequisites: args.BuyRequisites}, nil
+}
+
+func (m *fakeOrderBookManager) GetOrder(ctx context.Context, currencyPair types.CurrencyPair, orderID uuid.UUID) (*types.Order, error) {
+	if orderID != m.order.ID {
+		return nil, echo.ErrNotFound
+	}
+	order := m.order
+	return &order, nil
+}
+
+func (m *fakeOrderBookManager) CancelOrder(ctx context.Context, currencyPair types.CurrencyPair, orderID uuid.UUID) error {
+	return nil
+}
+
+func (m *fakeOrderBookManager) ListOrders(ctx context.Context, args order_book_types.ListOrdersArgs) ([]*types.Order, error) {
+	if !slices.Contains(args.UserIDIn, m.order.SellRequisites.UserID) {
+		return []*types.Order{}, nil
+	}
+	order := m.order
+	return []*types.Order{&order}, nil
+}
+
+func newTestApplication() (*Application, *fakeOrderBookManager) {
+	manager := &fakeOrderBookManager{
+		order: types.Order{
+			ID:             uuid.New(),
+			SellRequisites: types.Requisites{UserID: "mallory", Address: malloryBTC},
+			BuyRequisites:  types.Requisites{UserID: "mallory", Address: strings.Repeat("d", 64)},
+		},
+	}
+
+	return &Application{
+		WalletService:    &fakeWalletService{},
+		OrderBookManager: manager,
+		Store:            newFakeStore(),
+	}, manager
+}
+
+// serve calls the handler as alice and returns the recorded response.
+func serve(t *testing.T, handler echo.HandlerFunc, method string, body string, params ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	c := echo.New().NewContext(req, rec)
+	c.Set("user_id", "alice")
+	if len(params) == 2 {
+		c.SetParamNames(params[0])
+		c.SetParamValues(params[1])
+	}
+
+	require.NoError(t, handler(c))
+	return rec
+}
+
+func TestCreateWalletOwnership(t *testing.T) {
+	app, _ := newTestApplication()
+
+	rec := serve(t, app.CreateWallet(), http.MethodPost, `{"user_id": "mallory", "currency": "BTC"}`)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(t, app.CreateWallet(), http.MethodPost, `{"currency": "BTC"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"user_id":"alice"`)
+}
+
+func TestListWalletsOwnership(t *testing.T) {
+	app, _ := newTestApplication()
+
+	rec := serve(t, app.ListWallets(), http.MethodPost, `{"user_ids_in": ["mallory"]}`)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(t, app.ListWallets(), http.MethodPost, fmt.Sprintf(`{"addresss_in": [%q]}`, malloryBTC))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Without filters only the wallets of the caller are listed.
+	rec = serve(t, app.ListWallets(), http.MethodPost, `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), aliceBTC)
+	require.NotContains(t, rec.Body.String(), malloryBTC)
+}
+
+func TestTransferOwnership(t *testing.T) {
+	app, _ := newTestApplication()
+
+	rec := serve(t, app.Transfer(), http.MethodPost, fmt.Sprintf(
+		`{"from_address": %q, "to_address": %q, "amount": "1", "currency": "BTC"}`, malloryBTC, aliceBTC,
+	))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Sending to another user's wallet is allowed.
+	rec = serve(t, app.Transfer(), http.MethodPost, fmt.Sprintf(
+		`{"from_address": %q, "to_address": %q, "amount": "1", "currency": "BTC"}`, aliceBTC, malloryBTC,
+	))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestListTransactionsOwnership(t *testing.T) {
+	app, _ := newTestApplication()
+
+	rec := serve(t, app.ListTransactions(), http.MethodPost, `{"user_id_in": ["alice", "mallory"]}`)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(t, app.ListTransactions(), http.MethodPost, fmt.Sprintf(`{"address_in": [%q]}`, malloryBTC))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(t, app.ListTransactions(), http.MethodPost, fmt.Sprintf(`{"address_in": [%q]}`, aliceBTC))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCreateOrderOwnership(t *testing.T) {
+	app, _ := newTestApplication()
+
+	order := func(sellUserID, sellAddress string) string {
+		return fmt.Sprintf(`{
+			"type": "SELL",
+			"sell_currency": "BTC",
+			"sell_quantity": "1",
+			"sell_requisites": {"user_id": %q, "address": %q},
+			"price": "100",
+			"buy_currency": "USDT",
+			"buy_requisites": {"user_id": "alice", "address": %q}
+		}`, sellUserID, sellAddress, aliceUSDT)
+	}
+
+	rec := serve(t, app.CreateOrder(), http.MethodPost, order("mallory", malloryBTC))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Claiming another user's wallet under the caller's user ID is forbidden too.
+	rec = serve(t, app.CreateOrder(), http.MethodPost, order("alice", malloryBTC))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(t, app.CreateOrder(), http.MethodPost, order("alice", aliceBTC))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCancelOrderOwnership(t *testing.T) {
+	app, manager := newTestApplication()
+	body := `{"currency_pair": {"currency1": "BTC", "currency2": "USDT"}}`
+
+	rec := serve(t, app.CancelOrder(), http.MethodDelete, body, "id", manager.order.ID.String())
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(t, app.CancelOrder(), http.MethodDelete, body, "id", uuid.NewString())
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	manager.order.SellRequisites.UserID = "alice"
+	rec = serve(t, app.CancelOrder(), http.MethodDelete, body, "id", manager.order.ID.String())
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestListOrdersOwnership(t *testing.T) {
+	app, _ := newTestApplication()
+	pair := `"currency_pair": {"currency1": "BTC", "currency2": "USDT"}`
+
+	rec := serve(t, app.ListOrders(), http.MethodPost, fmt.Sprintf(`{%s, "user_id_in": ["mallory"]}`, pair))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// The order of mallory is not listed to alice.
+	rec = serve(t, app.ListOrders(), http.MethodPost, fmt.Sprintf(`{%s}`, pair))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[]`, rec.Body.String())
+}
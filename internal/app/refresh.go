@@ -0,0 +1,35 @@
+package app
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/auth_service"
+)
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token. The used
+// refresh token stops working.
+func (app *Application) Refresh() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req refreshRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		resp, err := app.AuthService.Refresh(ctx, req.RefreshToken)
+		if err != nil {
+			if errors.Is(err, auth_service.ErrTokenExpired) || errors.Is(err, auth_service.ErrInvalidToken) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
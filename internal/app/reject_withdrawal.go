@@ -0,0 +1,25 @@
+package app
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// RejectWithdrawal rejects a pending withdrawal and releases its amount back to the wallet.
+func (app *Application) RejectWithdrawal() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		args, code, err := bindReviewWithdrawalArgs(c)
+		if err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
+		withdrawal, err := app.WithdrawalService.RejectWithdrawal(ctx, args)
+		if err != nil {
+			return reviewWithdrawalError(c, "WithdrawalService.RejectWithdrawal", err)
+		}
+
+		return c.JSON(http.StatusOK, withdrawal)
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"regexp"
+	"vitalik_backend/internal/pkg/services/store"
+	withdrawal_service_types "vitalik_backend/internal/pkg/services/withdrawal_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const maxDestinationLength = 128
+
+type requestWithdrawalRequest struct {
+	Address     string          `json:"address"`
+	Amount      decimal.Decimal `json:"amount"`
+	Destination string          `json:"destination"`
+}
+
+// RequestWithdrawal creates a pending withdrawal from a wallet of the authenticated
+// user to an external destination. The amount is held until an admin reviews it.
+func (app *Application) RequestWithdrawal() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req requestWithdrawalRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := app.validateRequestWithdrawalRequest(ctx, &req); err != nil {
+			return c.JSON(code, map[string]string{
+				"message": fmt.Sprintf("validateRequestWithdrawalRequest failed: %v", err),
+			})
+		}
+
+		withdrawal, err := app.WithdrawalService.RequestWithdrawal(ctx, withdrawal_service_types.RequestWithdrawalArgs{
+			UserID:      userID,
+			Address:     req.Address,
+			Amount:      req.Amount,
+			Destination: req.Destination,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "wallet not found"})
+			case errors.Is(err, store.ErrInsufficientFunds):
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "insufficient funds"})
+			case errors.Is(err, types.ErrNonPositiveAmount), errors.Is(err, types.ErrAmountPrecision):
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("invalid amount: %v", err)})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("WithdrawalService.RequestWithdrawal failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, withdrawal)
+	}
+}
+
+func (app *Application) validateRequestWithdrawalRequest(ctx context.Context, req *requestWithdrawalRequest) (int, error) {
+	if req.Address == "" {
+		return http.StatusBadRequest, errors.New("address must be provided")
+	}
+
+	matched, err := regexp.MatchString("^[0-9a-fA-F]{64}$", req.Address)
+	if err != nil {
+		return http.StatusInternalServerError, errors.New("failed to validate address")
+	} else if !matched {
+		return http.StatusBadRequest, errors.New("invalid address format")
+	}
+
+	if req.Destination == "" {
+		return http.StatusBadRequest, errors.New("destination must be provided")
+	} else if len(req.Destination) > maxDestinationLength {
+		return http.StatusBadRequest, fmt.Errorf("destination must be at most %d characters", maxDestinationLength)
+	}
+
+	return http.StatusOK, nil
+}
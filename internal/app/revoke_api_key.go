@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	"vitalik_backend/internal/pkg/services/store"
+)
+
+type revokeAPIKeyRequest struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// RevokeAPIKey revokes an API key of the authenticated user.
+func (app *Application) RevokeAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req revokeAPIKeyRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if req.ID == uuid.Nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "id must be provided"})
+		}
+
+		err := app.APIKeyService.RevokeAPIKey(ctx, api_key_service_types.RevokeAPIKeyArgs{
+			ID:     req.ID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "active API key not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("APIKeyService.RevokeAPIKey failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "API key revoked successfully"})
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type setUserRoleRequest struct {
+	UserID string     `json:"user_id"`
+	Role   types.Role `json:"role"`
+}
+
+// SetUserRole changes the role of a user. The tokens the user holds stop working
+// until they are refreshed, so the new role takes effect immediately. Admins cannot
+// change their own role, which keeps the last admin from locking everyone out.
+func (app *Application) SetUserRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req setUserRoleRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+
+		if req.UserID == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "user_id must be provided"})
+		}
+
+		if !req.Role.Validate() {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": fmt.Sprintf("invalid role: %s", req.Role)})
+		}
+
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if req.UserID == userID {
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "admins cannot change their own role"})
+		}
+
+		err := app.Store.SetUserRole(ctx, store_types.SetUserRoleArgs{
+			UserID: req.UserID,
+			Role:   req.Role,
+		})
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "user not found"})
+			}
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": fmt.Sprintf("Store.SetUserRole failed: %v", err),
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{"message": "role changed successfully"})
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"github.com/guregu/null/v5"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strings"
+)
+
+// Stream upgrades the request to a WebSocket connection streaming market updates.
+// Private channels require a token, passed in the Authorization header or, since
+// browsers cannot set headers on WebSocket requests, in the token query parameter.
+func (app *Application) Stream() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		token := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = c.QueryParam("token")
+		}
+
+		var userID null.String
+		if token != "" {
+			identity, err := app.AuthService.Authenticate(ctx, token)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"message": err.Error(),
+				})
+			}
+			userID = null.StringFrom(identity.UserID)
+		}
+
+		if err := app.StreamService.ServeWS(c.Response(), c.Request(), userID); err != nil {
+			app.Logger.Warnf("StreamService.ServeWS failed: %v", err)
+		}
+
+		return nil
+	}
+}
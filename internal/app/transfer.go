@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/guregu/null/v5"
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
 	"net/http"
 	"regexp"
+	"vitalik_backend/internal/pkg/services/store"
 	store_types "vitalik_backend/internal/pkg/services/store/types"
 	"vitalik_backend/internal/pkg/types"
 )
@@ -16,10 +18,11 @@ type transferRequest struct {
 	FromAddress string `json:"from_address"`
 	ToAddress   string `json:"to_address"`
 
-	Amount   float64        `json:"amount"`
-	Currency types.Currency `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+	Currency types.Currency  `json:"currency"`
 }
 
+// Transfer moves funds from a wallet of the authenticated user to any wallet.
 func (app *Application) Transfer() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -35,10 +38,25 @@ func (app *Application) Transfer() echo.HandlerFunc {
 			})
 		}
 
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "user is not authenticated"})
+		}
+
+		if code, err := app.authorizeWallets(ctx, userID, req.FromAddress); err != nil {
+			return c.JSON(code, map[string]string{"message": err.Error()})
+		}
+
 		args := bindTransferArgs(req)
 
 		tx, err := app.Store.Transfer(ctx, args)
 		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, map[string]string{"message": "wallet not found"})
+			}
+			if errors.Is(err, store.ErrInsufficientFunds) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "insufficient funds"})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{
 				"message": fmt.Sprintf("Store Transfer failed: %v", err),
 			})
@@ -71,16 +89,16 @@ func (app *Application) validateTransferRequest(ctx context.Context, req *transf
 		return http.StatusBadRequest, fmt.Errorf("invalid to_address format")
 	}
 
-	if req.Amount <= 0 {
-		return http.StatusBadRequest, fmt.Errorf("invalid amount")
-	}
-
 	if req.Currency == "" {
 		return http.StatusBadRequest, errors.New("currency must be provided")
 	} else if !req.Currency.Validate() {
 		return http.StatusBadRequest, fmt.Errorf("invalid currency: %s", req.Currency)
 	}
 
+	if err := req.Currency.ValidateAmount(req.Amount); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid amount: %w", err)
+	}
+
 	return http.StatusOK, nil
 }
 
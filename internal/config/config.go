@@ -0,0 +1,286 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment names the setup the binary runs in.
+type Environment string
+
+const (
+	EnvDev  Environment = "dev"
+	EnvTest Environment = "test"
+	EnvProd Environment = "prod"
+)
+
+func (e *Environment) Validate() bool {
+	switch *e {
+	case EnvDev, EnvTest, EnvProd:
+		return true
+	default:
+		return false
+	}
+}
+
+// minProdSecretLength is the shortest JWT secret accepted in production.
+const minProdSecretLength = 32
+
+// Config is the configuration of the whole application. It is loaded from the
+// defaults, then the optional YAML file named by CONFIG_FILE, then the
+// environment, so environment variables override the file.
+type Config struct {
+	Env      Environment    `yaml:"env"`
+	Database DatabaseConfig `yaml:"database"`
+	Server   ServerConfig   `yaml:"server"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Matcher  MatcherConfig  `yaml:"matcher"`
+	Payout   PayoutConfig   `yaml:"payout"`
+}
+
+type DatabaseConfig struct {
+	DSN string `yaml:"dsn"`
+}
+
+type ServerConfig struct {
+	Port                 int  `yaml:"port"`
+	DepositFaucetEnabled bool `yaml:"deposit_faucet_enabled"`
+}
+
+// AuthConfig holds the token settings.
+type AuthConfig struct {
+	JWTSecret       string        `yaml:"jwt_secret"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+
+	APIKeyReplayWindow time.Duration `yaml:"api_key_replay_window"`
+}
+
+// MatcherConfig holds the matcher intervals. A zero SweepInterval disables the
+// periodic sweep of all order books.
+type MatcherConfig struct {
+	SweepInterval  time.Duration `yaml:"sweep_interval"`
+	ExpiryInterval time.Duration `yaml:"expiry_interval"`
+}
+
+// PayoutBackend names the backend approved withdrawals are sent to.
+type PayoutBackend string
+
+// PayoutBackendFake completes every payout without sending anything. It is the
+// only backend so far and is refused in prod.
+const PayoutBackendFake PayoutBackend = "fake"
+
+func (b *PayoutBackend) Validate() bool {
+	switch *b {
+	case PayoutBackendFake:
+		return true
+	default:
+		return false
+	}
+}
+
+// PayoutConfig selects the payout backend. It is left empty by tools that send no
+// payouts, the server refuses to start without one.
+type PayoutConfig struct {
+	Backend PayoutBackend `yaml:"backend"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Env: EnvDev,
+		Server: ServerConfig{
+			Port: 8080,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+
+			APIKeyReplayWindow: 30 * time.Second,
+		},
+		Matcher: MatcherConfig{
+			SweepInterval:  time.Minute,
+			ExpiryInterval: time.Second,
+		},
+	}
+}
+
+// Load builds the configuration and validates it.
+func Load() (*Config, error) {
+	cfg := defaultConfig()
+
+	if path, ok := os.LookupEnv("CONFIG_FILE"); ok && path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("os.Open failed: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	if value, ok := os.LookupEnv("APP_ENV"); ok {
+		c.Env = Environment(value)
+	}
+	if value, ok := os.LookupEnv("DATABASE_DSN"); ok {
+		c.Database.DSN = value
+	}
+	if value, ok := os.LookupEnv("JWT_SECRET"); ok {
+		c.Auth.JWTSecret = value
+	}
+	if value, ok := os.LookupEnv("PAYOUT_BACKEND"); ok {
+		c.Payout.Backend = PayoutBackend(value)
+	}
+
+	var err error
+	if c.Server.Port, err = intFromEnv("SERVER_PORT", c.Server.Port); err != nil {
+		return err
+	}
+	if c.Server.DepositFaucetEnabled, err = boolFromEnv("DEPOSIT_FAUCET_ENABLED", c.Server.DepositFaucetEnabled); err != nil {
+		return err
+	}
+	if c.Auth.AccessTokenTTL, err = durationFromEnv("ACCESS_TOKEN_TTL", c.Auth.AccessTokenTTL); err != nil {
+		return err
+	}
+	if c.Auth.RefreshTokenTTL, err = durationFromEnv("REFRESH_TOKEN_TTL", c.Auth.RefreshTokenTTL); err != nil {
+		return err
+	}
+	if c.Auth.APIKeyReplayWindow, err = durationFromEnv("API_KEY_REPLAY_WINDOW", c.Auth.APIKeyReplayWindow); err != nil {
+		return err
+	}
+	if c.Matcher.SweepInterval, err = durationFromEnv("MATCHER_SWEEP_INTERVAL", c.Matcher.SweepInterval); err != nil {
+		return err
+	}
+	if c.Matcher.ExpiryInterval, err = durationFromEnv("MATCHER_EXPIRY_INTERVAL", c.Matcher.ExpiryInterval); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that the required settings are present and that production runs
+// with strong secrets, without the deposit faucet and without the fake payout backend.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !c.Env.Validate() {
+		errs = append(errs, fmt.Errorf("env must be one of dev, test or prod, got %q", c.Env))
+	}
+
+	if c.Database.DSN == "" {
+		errs = append(errs, errors.New("database dsn must be provided"))
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("auth jwt secret must be provided"))
+	}
+
+	if c.Auth.AccessTokenTTL <= 0 {
+		errs = append(errs, fmt.Errorf("auth access token ttl must be positive, got %s", c.Auth.AccessTokenTTL))
+	}
+
+	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+		errs = append(errs, fmt.Errorf("auth refresh token ttl must be longer than the access token ttl, got %s", c.Auth.RefreshTokenTTL))
+	}
+
+	if c.Auth.APIKeyReplayWindow <= 0 {
+		errs = append(errs, fmt.Errorf("auth api key replay window must be positive, got %s", c.Auth.APIKeyReplayWindow))
+	}
+
+	if c.Matcher.SweepInterval < 0 {
+		errs = append(errs, fmt.Errorf("matcher sweep interval must not be negative, got %s", c.Matcher.SweepInterval))
+	}
+
+	if c.Matcher.ExpiryInterval <= 0 {
+		errs = append(errs, fmt.Errorf("matcher expiry interval must be positive, got %s", c.Matcher.ExpiryInterval))
+	}
+
+	if c.Payout.Backend != "" && !c.Payout.Backend.Validate() {
+		errs = append(errs, fmt.Errorf("payout backend must be one of fake, got %q", c.Payout.Backend))
+	}
+
+	if c.Env == EnvProd {
+		if len(c.Auth.JWTSecret) < minProdSecretLength {
+			errs = append(errs, fmt.Errorf("auth jwt secret must be at least %d bytes in prod", minProdSecretLength))
+		}
+		if c.Server.DepositFaucetEnabled {
+			errs = append(errs, errors.New("deposit faucet must not be enabled in prod"))
+		}
+		if c.Payout.Backend == PayoutBackendFake {
+			errs = append(errs, errors.New("fake payout backend must not be used in prod"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func intFromEnv(key string, fallback int) (int, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return number, nil
+}
+
+func boolFromEnv(key string, fallback bool) (bool, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback, nil
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return flag, nil
+}
+
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return duration, nil
+}
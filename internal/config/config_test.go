@@ -0,0 +1,108 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("DATABASE_DSN", "postgresql://localhost/vitalik")
+	t.Setenv("JWT_SECRET", "secret")
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, EnvDev, cfg.Env)
+	require.Equal(t, 8080, cfg.Server.Port)
+	require.Equal(t, time.Minute, cfg.Matcher.SweepInterval)
+	require.Equal(t, time.Second, cfg.Matcher.ExpiryInterval)
+	require.False(t, cfg.Server.DepositFaucetEnabled)
+	require.Equal(t, 15*time.Minute, cfg.Auth.AccessTokenTTL)
+}
+
+func TestLoadFileWithEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+env: test
+database:
+  dsn: postgresql://file/vitalik
+server:
+  port: 9000
+auth:
+  jwt_secret: file-secret
+  access_token_ttl: 5m
+matcher:
+  sweep_interval: 0s
+  expiry_interval: 500ms
+payout:
+  backend: fake
+`), 0o600))
+
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("SERVER_PORT", "9100")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, EnvTest, cfg.Env)
+	require.Equal(t, "postgresql://file/vitalik", cfg.Database.DSN)
+	require.Equal(t, 9100, cfg.Server.Port)
+	require.Equal(t, "file-secret", cfg.Auth.JWTSecret)
+	require.Equal(t, 5*time.Minute, cfg.Auth.AccessTokenTTL)
+	require.Zero(t, cfg.Matcher.SweepInterval)
+	require.Equal(t, 500*time.Millisecond, cfg.Matcher.ExpiryInterval)
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	t.Run("missing required", func(t *testing.T) {
+		t.Setenv("DATABASE_DSN", "")
+		t.Setenv("JWT_SECRET", "")
+
+		_, err := Load()
+		require.ErrorContains(t, err, "database dsn must be provided")
+		require.ErrorContains(t, err, "auth jwt secret must be provided")
+	})
+
+	t.Run("unknown file key", func(t *testing.T) {
+		setRequiredEnv(t)
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("server:\n  prot: 80\n"), 0o600))
+		t.Setenv("CONFIG_FILE", path)
+
+		_, err := Load()
+		require.ErrorContains(t, err, "prot")
+	})
+
+	t.Run("unknown payout backend", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("PAYOUT_BACKEND", "bank")
+
+		_, err := Load()
+		require.ErrorContains(t, err, `payout backend must be one of fake, got "bank"`)
+	})
+
+	t.Run("malformed env", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("MATCHER_EXPIRY_INTERVAL", "soon")
+
+		_, err := Load()
+		require.ErrorContains(t, err, "invalid MATCHER_EXPIRY_INTERVAL")
+	})
+
+	t.Run("weak prod", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("APP_ENV", "prod")
+		t.Setenv("DEPOSIT_FAUCET_ENABLED", "true")
+		t.Setenv("PAYOUT_BACKEND", "fake")
+
+		_, err := Load()
+		require.ErrorContains(t, err, "at least 32 bytes")
+		require.ErrorContains(t, err, "deposit faucet must not be enabled")
+		require.ErrorContains(t, err, "fake payout backend must not be used in prod")
+	})
+}
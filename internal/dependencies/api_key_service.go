@@ -0,0 +1,17 @@
+package dependencies
+
+import (
+	"context"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// IAPIKeyService defines methods for managing API keys and authenticating the
+// requests signed with them.
+type IAPIKeyService interface {
+	CreateAPIKey(ctx context.Context, args api_key_service_types.CreateAPIKeyArgs) (*api_key_service_types.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]*types.APIKey, error)
+	RevokeAPIKey(ctx context.Context, args api_key_service_types.RevokeAPIKeyArgs) error
+
+	Authenticate(ctx context.Context, args api_key_service_types.AuthenticateArgs) (*types.APIKey, error)
+}
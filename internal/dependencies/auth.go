@@ -8,5 +8,13 @@ import (
 type IAuthService interface {
 	Register(ctx context.Context, args auth_types.AuthArgs) error
 	Login(ctx context.Context, args auth_types.AuthArgs) (*auth_types.LoginResponse, error)
-	Authenticate(ctx context.Context, tokenString string) (string, error)
+	Authenticate(ctx context.Context, tokenString string) (*auth_types.Identity, error)
+	Refresh(ctx context.Context, refreshToken string) (*auth_types.LoginResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	ChangePassword(ctx context.Context, args auth_types.ChangePasswordArgs) error
+
+	EnrollTOTP(ctx context.Context, userID string) (*auth_types.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, args auth_types.ConfirmTOTPArgs) (*auth_types.ConfirmTOTPResponse, error)
+	DisableTOTP(ctx context.Context, userID string) error
+	VerifyOTP(ctx context.Context, args auth_types.VerifyOTPArgs) error
 }
@@ -0,0 +1,11 @@
+package dependencies
+
+import "context"
+
+// IDepositService defines methods for crediting deposits reported by the deposit source.
+type IDepositService interface {
+	Start() error
+	Stop()
+
+	SyncDeposits(ctx context.Context) error
+}
@@ -0,0 +1,13 @@
+package dependencies
+
+import (
+	"context"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// IDepositSource reports transfers to exchange wallets arriving from outside.
+type IDepositSource interface {
+	// Poll returns the transfers that are not final yet with their current
+	// confirmation counts. A transfer may be reported many times.
+	Poll(ctx context.Context) ([]types.IncomingTransfer, error)
+}
@@ -6,16 +6,48 @@ import "github.com/labstack/echo/v4"
 type IHandler interface {
 	Register() echo.HandlerFunc
 	Login() echo.HandlerFunc
+	Refresh() echo.HandlerFunc
+	Logout() echo.HandlerFunc
+	ChangePassword() echo.HandlerFunc
 	HealthCheck() echo.HandlerFunc
 
 	CreateWallet() echo.HandlerFunc
 	ListWallets() echo.HandlerFunc
 	Deposit() echo.HandlerFunc
+	ListDeposits() echo.HandlerFunc
 	ListTransactions() echo.HandlerFunc
 	Transfer() echo.HandlerFunc
 	CreateOrder() echo.HandlerFunc
 	CancelOrder() echo.HandlerFunc
 	ListOrders() echo.HandlerFunc
 	MatchOrders() echo.HandlerFunc
+	GetDepth() echo.HandlerFunc
 	ListAvailableCurrencies() echo.HandlerFunc
+	ListTrades() echo.HandlerFunc
+	ListMyTrades() echo.HandlerFunc
+	ListCandles() echo.HandlerFunc
+	GetTicker() echo.HandlerFunc
+	ListTickers() echo.HandlerFunc
+	GetFeeRate() echo.HandlerFunc
+	ListPaidFees() echo.HandlerFunc
+	Stream() echo.HandlerFunc
+
+	RequestWithdrawal() echo.HandlerFunc
+	ListWithdrawals() echo.HandlerFunc
+	ListAllWithdrawals() echo.HandlerFunc
+	ApproveWithdrawal() echo.HandlerFunc
+	RejectWithdrawal() echo.HandlerFunc
+
+	CreateAPIKey() echo.HandlerFunc
+	ListAPIKeys() echo.HandlerFunc
+	RevokeAPIKey() echo.HandlerFunc
+
+	EnrollTOTP() echo.HandlerFunc
+	ConfirmTOTP() echo.HandlerFunc
+	DisableTOTP() echo.HandlerFunc
+
+	ListAllWallets() echo.HandlerFunc
+	HaltMarket() echo.HandlerFunc
+	ResumeMarket() echo.HandlerFunc
+	SetUserRole() echo.HandlerFunc
 }
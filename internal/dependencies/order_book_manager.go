@@ -9,10 +9,18 @@ import (
 
 // IOrderBookManager defines methods for managing multiple order books.
 type IOrderBookManager interface {
+	LoadOrderBooks(ctx context.Context) error
 	ListAvailableCurrencyPairs(ctx context.Context) ([]types.CurrencyPair, error)
 	MatchOrders(ctx context.Context) error
+	ExpireOrders(ctx context.Context) error
+	SetMarketHalted(ctx context.Context, currencyPair types.CurrencyPair, halted bool) error
 
 	CreateOrder(ctx context.Context, args order_book_types.CreateOrderArgs) (*types.Order, error)
 	CancelOrder(ctx context.Context, currencyPair types.CurrencyPair, orderID uuid.UUID) error
+	GetOrder(ctx context.Context, currencyPair types.CurrencyPair, orderID uuid.UUID) (*types.Order, error)
 	ListOrders(ctx context.Context, args order_book_types.ListOrdersArgs) ([]*types.Order, error)
+	GetDepth(ctx context.Context, args order_book_types.GetDepthArgs) (*types.Depth, error)
+
+	SubscribeTrades(listener order_book_types.TradeListener)
+	SubscribeOrders(listener order_book_types.OrderListener)
 }
@@ -0,0 +1,16 @@
+package dependencies
+
+import (
+	"context"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// IPayout sends approved withdrawals to the backend that moves the funds off the exchange.
+type IPayout interface {
+	// Send submits the withdrawal and returns its reference at the backend. Sending
+	// the same withdrawal again must not pay it twice, since withdrawals left
+	// approved by a failed update are sent again.
+	Send(ctx context.Context, withdrawal types.Withdrawal) (string, error)
+	// Status returns the state of a submitted payout.
+	Status(ctx context.Context, reference string) (types.PayoutStatus, error)
+}
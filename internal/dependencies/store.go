@@ -2,6 +2,7 @@ package dependencies
 
 import (
 	"context"
+	"github.com/google/uuid"
 	store_types "vitalik_backend/internal/pkg/services/store/types"
 	"vitalik_backend/internal/pkg/types"
 )
@@ -11,12 +12,54 @@ type IStore interface {
 	ListWallets(ctx context.Context, args store_types.ListWalletsArgs) ([]*types.Wallet, error)
 
 	Deposit(ctx context.Context, args store_types.DepositArgs) (*types.Transaction, error)
+	SaveDeposit(ctx context.Context, args store_types.SaveDepositArgs) (*types.Deposit, error)
+	ListDeposits(ctx context.Context, args store_types.ListDepositsArgs) ([]*types.Deposit, error)
 
 	ListTransactions(ctx context.Context, args store_types.ListTransactionsArgs) ([]*types.Transaction, error)
 	Transfer(ctx context.Context, args store_types.TransferArgs) (*types.Transaction, error)
+	SettleTrade(ctx context.Context, args store_types.SettleTradeArgs) (*types.Trade, error)
+	ListTrades(ctx context.Context, args store_types.ListTradesArgs) ([]*types.Trade, error)
 
+	GetFeeSchedule(ctx context.Context, args store_types.GetFeeScheduleArgs) (*types.FeeSchedule, error)
+	ListPaidFees(ctx context.Context, args store_types.ListPaidFeesArgs) ([]*types.FeeTotal, error)
+
+	ListCandles(ctx context.Context, args store_types.ListCandlesArgs) ([]*types.Candle, error)
+	RebuildCandles(ctx context.Context) (int, error)
+
+	CreateWithdrawal(ctx context.Context, withdrawal types.Withdrawal) error
+	TransitionWithdrawal(ctx context.Context, args store_types.TransitionWithdrawalArgs) (*types.Withdrawal, error)
+	GetWithdrawal(ctx context.Context, id uuid.UUID) (*types.Withdrawal, error)
+	ListWithdrawals(ctx context.Context, args store_types.ListWithdrawalsArgs) ([]*types.Withdrawal, error)
+
+	PlaceOrder(ctx context.Context, args store_types.PlaceOrderArgs) error
+	CancelOrder(ctx context.Context, order types.Order) error
 	SaveOrder(ctx context.Context, order types.Order) error
+	ListOrders(ctx context.Context, args store_types.ListOrdersArgs) ([]*types.Order, error)
+
+	SetMarketHalted(ctx context.Context, args store_types.SetMarketHaltedArgs) error
+	ListHaltedMarkets(ctx context.Context) ([]types.CurrencyPair, error)
+
+	SubscribeBalances(listener store_types.BalanceListener)
 
 	SaveUser(ctx context.Context, user types.User) error
 	GetUser(ctx context.Context, userID string) (*types.User, error)
+	SetUserRole(ctx context.Context, args store_types.SetUserRoleArgs) error
+	SetUserPassword(ctx context.Context, args store_types.SetUserPasswordArgs) error
+
+	SaveTOTPEnrollment(ctx context.Context, enrollment types.TOTPEnrollment) error
+	GetTOTPEnrollment(ctx context.Context, userID string) (*types.TOTPEnrollment, error)
+	ConfirmTOTPEnrollment(ctx context.Context, args store_types.ConfirmTOTPEnrollmentArgs) error
+	UseTOTPStep(ctx context.Context, args store_types.UseTOTPStepArgs) error
+	UseRecoveryCode(ctx context.Context, args store_types.UseRecoveryCodeArgs) error
+	DeleteTOTPEnrollment(ctx context.Context, userID string) error
+
+	CreateRefreshToken(ctx context.Context, token types.RefreshToken) error
+	GetRefreshToken(ctx context.Context, args store_types.GetRefreshTokenArgs) (*types.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, args store_types.RotateRefreshTokenArgs) error
+	RevokeRefreshToken(ctx context.Context, args store_types.RevokeRefreshTokenArgs) error
+
+	CreateAPIKey(ctx context.Context, key types.APIKey) error
+	GetAPIKey(ctx context.Context, id uuid.UUID) (*types.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]*types.APIKey, error)
+	RevokeAPIKey(ctx context.Context, args store_types.RevokeAPIKeyArgs) error
 }
@@ -0,0 +1,14 @@
+package dependencies
+
+import (
+	"github.com/guregu/null/v5"
+	"net/http"
+)
+
+// IStreamService defines methods for streaming market and account updates over WebSocket.
+type IStreamService interface {
+	Start() error
+	Stop()
+
+	ServeWS(w http.ResponseWriter, r *http.Request, userID null.String) error
+}
@@ -0,0 +1,14 @@
+package dependencies
+
+import (
+	"context"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// ITickerService defines methods for reading the 24-hour statistics of currency pairs.
+type ITickerService interface {
+	Load(ctx context.Context) error
+
+	GetTicker(ctx context.Context, currencyPair types.CurrencyPair) (*types.Ticker, error)
+	ListTickers(ctx context.Context) ([]*types.Ticker, error)
+}
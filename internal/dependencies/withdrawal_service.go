@@ -0,0 +1,18 @@
+package dependencies
+
+import (
+	"context"
+	withdrawal_service_types "vitalik_backend/internal/pkg/services/withdrawal_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// IWithdrawalService defines methods for requesting, reviewing and paying out withdrawals.
+type IWithdrawalService interface {
+	Start() error
+	Stop()
+
+	RequestWithdrawal(ctx context.Context, args withdrawal_service_types.RequestWithdrawalArgs) (*types.Withdrawal, error)
+	ApproveWithdrawal(ctx context.Context, args withdrawal_service_types.ReviewWithdrawalArgs) (*types.Withdrawal, error)
+	RejectWithdrawal(ctx context.Context, args withdrawal_service_types.ReviewWithdrawalArgs) (*types.Withdrawal, error)
+	SyncPayouts(ctx context.Context) error
+}
@@ -0,0 +1,45 @@
+package configfx
+
+import (
+	"vitalik_backend/internal/config"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+	"vitalik_backend/internal/pkg/services/matcher"
+	"vitalik_backend/internal/server"
+)
+
+func newDatabaseConfig(cfg *config.Config) config.DatabaseConfig {
+	return cfg.Database
+}
+
+func newServerConfig(cfg *config.Config) server.Config {
+	return server.Config{
+		Port:                 cfg.Server.Port,
+		DepositFaucetEnabled: cfg.Server.DepositFaucetEnabled,
+	}
+}
+
+func newAuthConfig(cfg *config.Config) auth_types.Config {
+	return auth_types.Config{
+		JWTSecret:       []byte(cfg.Auth.JWTSecret),
+		AccessTokenTTL:  cfg.Auth.AccessTokenTTL,
+		RefreshTokenTTL: cfg.Auth.RefreshTokenTTL,
+	}
+}
+
+func newAPIKeyConfig(cfg *config.Config) api_key_service_types.Config {
+	return api_key_service_types.Config{
+		ReplayWindow: cfg.Auth.APIKeyReplayWindow,
+	}
+}
+
+func newPayoutConfig(cfg *config.Config) config.PayoutConfig {
+	return cfg.Payout
+}
+
+func newMatcherConfig(cfg *config.Config) matcher.Config {
+	return matcher.Config{
+		SweepInterval:  cfg.Matcher.SweepInterval,
+		ExpiryInterval: cfg.Matcher.ExpiryInterval,
+	}
+}
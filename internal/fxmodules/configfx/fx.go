@@ -0,0 +1,18 @@
+package configfx
+
+import (
+	"go.uber.org/fx"
+	"vitalik_backend/internal/config"
+)
+
+var Module = fx.Module("configfx",
+	fx.Provide(config.Load),
+	fx.Provide(
+		newDatabaseConfig,
+		newServerConfig,
+		newAuthConfig,
+		newAPIKeyConfig,
+		newMatcherConfig,
+		newPayoutConfig,
+	),
+)
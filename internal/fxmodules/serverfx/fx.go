@@ -8,12 +8,11 @@ import (
 	"vitalik_backend/internal/server"
 )
 
-func startServer(lc fx.Lifecycle, s *server.Server, l *zap.Logger) {
+func startServer(lc fx.Lifecycle, s *server.Server, config server.Config, l *zap.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go func() {
-				port := 8080
-				if err := s.Start(fmt.Sprintf(":%d", port)); err != nil {
+				if err := s.Start(fmt.Sprintf(":%d", config.Port)); err != nil {
 					l.Error(err.Error())
 				}
 			}()
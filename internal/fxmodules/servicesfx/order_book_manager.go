@@ -0,0 +1,67 @@
+package servicesfx
+
+import (
+	"context"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"vitalik_backend/internal/dependencies"
+)
+
+func loadOrderBooks(lc fx.Lifecycle, m dependencies.IOrderBookManager, l *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l.Info("Loading open orders into order books...")
+			return m.LoadOrderBooks(ctx)
+		},
+	})
+}
+
+// loadTicker runs after loadOrderBooks, so the ticker sees the loaded books.
+func loadTicker(lc fx.Lifecycle, t dependencies.ITickerService, l *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l.Info("Loading 24h ticker statistics...")
+			return t.Load(ctx)
+		},
+	})
+}
+
+// startStream runs after loadTicker, so stream snapshots see the loaded state.
+func startStream(lc fx.Lifecycle, s dependencies.IStreamService, l *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l.Info("Starting WebSocket stream...")
+			return s.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			s.Stop()
+			return nil
+		},
+	})
+}
+
+func startWithdrawals(lc fx.Lifecycle, s dependencies.IWithdrawalService, l *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l.Info("Starting withdrawal payouts...")
+			return s.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			s.Stop()
+			return nil
+		},
+	})
+}
+
+func startDeposits(lc fx.Lifecycle, s dependencies.IDepositService, l *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			l.Info("Starting deposit polling...")
+			return s.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			s.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package servicesfx
+
+import (
+	"errors"
+	"fmt"
+	"vitalik_backend/internal/config"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/services/payout_service"
+)
+
+// newPayout returns the configured payout backend and fails the startup when
+// there is none. The config validation accepts the fake one only outside prod.
+func newPayout(cfg config.PayoutConfig) (dependencies.IPayout, error) {
+	switch cfg.Backend {
+	case config.PayoutBackendFake:
+		return payout_service.NewFakePayout(), nil
+	case "":
+		return nil, errors.New("no payout backend is configured")
+	default:
+		return nil, fmt.Errorf("unknown payout backend %q", cfg.Backend)
+	}
+}
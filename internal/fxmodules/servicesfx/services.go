@@ -4,9 +4,15 @@ import (
 	"go.uber.org/fx"
 	"vitalik_backend/internal/app"
 	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/services/api_key_service"
 	"vitalik_backend/internal/pkg/services/auth_service"
+	"vitalik_backend/internal/pkg/services/deposit_service"
+	"vitalik_backend/internal/pkg/services/deposit_source_service"
 	"vitalik_backend/internal/pkg/services/order_book_manager"
+	"vitalik_backend/internal/pkg/services/stream_service"
+	"vitalik_backend/internal/pkg/services/ticker_service"
 	"vitalik_backend/internal/pkg/services/wallet_service"
+	"vitalik_backend/internal/pkg/services/withdrawal_service"
 )
 
 var Module = fx.Module("servicesfx",
@@ -15,5 +21,18 @@ var Module = fx.Module("servicesfx",
 		fx.Annotate(wallet_service.NewWalletService, fx.As(new(dependencies.IWalletService))),
 		fx.Annotate(order_book_manager.NewOrderBookManager, fx.As(new(dependencies.IOrderBookManager))),
 		fx.Annotate(auth_service.NewAuthService, fx.As(new(dependencies.IAuthService))),
+		fx.Annotate(api_key_service.NewAPIKeyService, fx.As(new(dependencies.IAPIKeyService))),
+		fx.Annotate(ticker_service.NewTickerService, fx.As(new(dependencies.ITickerService))),
+		fx.Annotate(stream_service.NewStreamService, fx.As(new(dependencies.IStreamService))),
+		fx.Annotate(withdrawal_service.NewWithdrawalService, fx.As(new(dependencies.IWithdrawalService))),
+		newPayout,
+		fx.Annotate(deposit_service.NewDepositService, fx.As(new(dependencies.IDepositService))),
+		// No chain is watched yet, the fake source reports nothing unless fed.
+		fx.Annotate(deposit_source_service.NewFakeDepositSource, fx.As(new(dependencies.IDepositSource))),
 	),
+	fx.Invoke(loadOrderBooks),
+	fx.Invoke(loadTicker),
+	fx.Invoke(startStream),
+	fx.Invoke(startWithdrawals),
+	fx.Invoke(startDeposits),
 )
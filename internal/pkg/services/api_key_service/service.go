@@ -0,0 +1,217 @@
+package api_key_service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+var (
+	ErrInvalidAPIKey    = errors.New("invalid API key")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrRequestExpired   = errors.New("request timestamp is outside the replay window")
+	ErrReplayedRequest  = errors.New("request was already received")
+	ErrIPNotAllowed     = errors.New("IP address is not allowed for this API key")
+)
+
+// APIKeyService manages API keys and verifies signed requests.
+//
+// The secret of a key is random and never stored: requests are signed under its
+// SHA-256 hash, which is all the store keeps, so the secret itself cannot be
+// recovered from a leaked database. Signatures seen within the replay window are
+// remembered in memory, which protects a single instance against replays.
+type APIKeyService struct {
+	store  dependencies.IStore
+	config api_key_service_types.Config
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func NewAPIKeyService(store dependencies.IStore, config api_key_service_types.Config) (*APIKeyService, error) {
+	if store == nil || config.ReplayWindow <= 0 {
+		return nil, errors.New("failed to initialize API key service")
+	}
+
+	return &APIKeyService{
+		store:  store,
+		config: config,
+		seen:   make(map[string]time.Time),
+	}, nil
+}
+
+var _ dependencies.IAPIKeyService = (*APIKeyService)(nil)
+
+func (s *APIKeyService) CreateAPIKey(
+	ctx context.Context,
+	args api_key_service_types.CreateAPIKeyArgs,
+) (*api_key_service_types.CreateAPIKeyResponse, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("uuid.NewV7 failed: %w", err)
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	key := types.APIKey{
+		ID:         id,
+		UserID:     args.UserID,
+		Name:       args.Name,
+		SecretHash: hashSecret(secret),
+		Scopes:     args.Scopes,
+		AllowedIPs: args.AllowedIPs,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = s.store.CreateAPIKey(ctx, key); err != nil {
+		return nil, fmt.Errorf("store.CreateAPIKey failed: %w", err)
+	}
+
+	return &api_key_service_types.CreateAPIKeyResponse{
+		APIKey: &key,
+		Secret: secret,
+	}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID string) ([]*types.APIKey, error) {
+	keys, err := s.store.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("store.ListAPIKeys failed: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, args api_key_service_types.RevokeAPIKeyArgs) error {
+	err := s.store.RevokeAPIKey(ctx, store_types.RevokeAPIKeyArgs{
+		ID:        args.ID,
+		UserID:    args.UserID,
+		RevokedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("store.RevokeAPIKey failed: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate verifies a signed request and returns its API key. The request
+// must be signed by an active key, come from an allowed IP, carry a timestamp
+// within the replay window and not have been received before.
+func (s *APIKeyService) Authenticate(
+	ctx context.Context,
+	args api_key_service_types.AuthenticateArgs,
+) (*types.APIKey, error) {
+	id, err := uuid.Parse(args.KeyID)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	millis, err := strconv.ParseInt(args.Timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %w", ErrRequestExpired)
+	}
+
+	now := time.Now()
+	timestamp := time.UnixMilli(millis)
+	if timestamp.Before(now.Add(-s.config.ReplayWindow)) || timestamp.After(now.Add(s.config.ReplayWindow)) {
+		return nil, ErrRequestExpired
+	}
+
+	key, err := s.store.GetAPIKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("store.GetAPIKey failed: %w", err)
+	}
+
+	if key.RevokedAt.Valid {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if !key.AllowsIP(args.RemoteIP) {
+		return nil, ErrIPNotAllowed
+	}
+
+	signature, err := hex.DecodeString(args.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	expected, _ := hex.DecodeString(sign(key.SecretHash, args.Timestamp, args.Method, args.Path, args.Body))
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidSignature
+	}
+
+	if !s.remember(args.Signature, timestamp, now) {
+		return nil, ErrReplayedRequest
+	}
+
+	return key, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of a request under the hex SHA-256 hash of an
+// API key secret. The signed payload is the timestamp, method, path with query and
+// body, each followed by a newline but the body, so no two requests share a payload.
+func Sign(secret string, timestamp string, method string, path string, body []byte) string {
+	return sign(hashSecret(secret), timestamp, method, path, body)
+}
+
+func sign(secretHash string, timestamp string, method string, path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secretHash))
+	mac.Write([]byte(strings.Join([]string{timestamp, method, path}, "\n") + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// remember records a signature and reports whether it is new. Signatures older
+// than the replay window are forgotten, their requests are rejected by timestamp.
+func (s *APIKeyService) remember(signature string, timestamp time.Time, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for seen, at := range s.seen {
+		if at.Before(now.Add(-s.config.ReplayWindow)) {
+			delete(s.seen, seen)
+		}
+	}
+
+	if _, ok := s.seen[signature]; ok {
+		return false
+	}
+	s.seen[signature] = timestamp
+
+	return true
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("rand.Read failed: %w", err)
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,197 @@
+package api_key_service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+var testConfig = api_key_service_types.Config{
+	ReplayWindow: 30 * time.Second,
+}
+
+// fakeStore keeps API keys in memory.
+type fakeStore struct {
+	dependencies.IStore
+
+	mu   sync.Mutex
+	keys map[uuid.UUID]types.APIKey
+}
+
+func (s *fakeStore) CreateAPIKey(ctx context.Context, key types.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *fakeStore) GetAPIKey(ctx context.Context, id uuid.UUID) (*types.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &key, nil
+}
+
+func (s *fakeStore) RevokeAPIKey(ctx context.Context, args store_types.RevokeAPIKeyArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[args.ID]
+	if !ok || key.UserID != args.UserID || key.RevokedAt.Valid {
+		return store.ErrNotFound
+	}
+	key.RevokedAt.SetValid(args.RevokedAt)
+	s.keys[args.ID] = key
+	return nil
+}
+
+func newTestService(t *testing.T) (*APIKeyService, *fakeStore) {
+	t.Helper()
+
+	s := &fakeStore{keys: make(map[uuid.UUID]types.APIKey)}
+	service, err := NewAPIKeyService(s, testConfig)
+	require.NoError(t, err)
+	return service, s
+}
+
+func createKey(t *testing.T, service *APIKeyService, allowedIPs ...string) *api_key_service_types.CreateAPIKeyResponse {
+	t.Helper()
+
+	resp, err := service.CreateAPIKey(context.Background(), api_key_service_types.CreateAPIKeyArgs{
+		UserID:     "alice",
+		Name:       "bot",
+		Scopes:     []types.APIKeyScope{types.APIKeyScopeRead},
+		AllowedIPs: allowedIPs,
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+// signedRequest signs an order listing with the given secret at the given time.
+func signedRequest(key *api_key_service_types.CreateAPIKeyResponse, secret string, at time.Time) api_key_service_types.AuthenticateArgs {
+	timestamp := strconv.FormatInt(at.UnixMilli(), 10)
+	body := []byte(`{"currency_pair": {"currency1": "BTC", "currency2": "USDT"}}`)
+
+	return api_key_service_types.AuthenticateArgs{
+		KeyID:     key.APIKey.ID.String(),
+		Timestamp: timestamp,
+		Signature: Sign(secret, timestamp, http.MethodPost, "/auth/orders", body),
+		Method:    http.MethodPost,
+		Path:      "/auth/orders",
+		Body:      body,
+		RemoteIP:  "203.0.113.7",
+	}
+}
+
+func TestCreateAPIKeyStoresSecretHash(t *testing.T) {
+	service, s := newTestService(t)
+	first := createKey(t, service)
+	second := createKey(t, service)
+
+	require.NotEmpty(t, first.Secret)
+	require.NotEqual(t, first.Secret, second.Secret)
+
+	stored := s.keys[first.APIKey.ID]
+	require.Equal(t, hashSecret(first.Secret), stored.SecretHash)
+	require.NotContains(t, stored.SecretHash, first.Secret)
+}
+
+func TestSignSeparatesFields(t *testing.T) {
+	// Moving bytes between fields changes the signature.
+	require.NotEqual(t,
+		Sign("secret", "1700000000000", http.MethodPost, "/auth/orders", []byte("{}")),
+		Sign("secret", "1700000000000", "POS", "T/auth/orders", []byte("{}")),
+	)
+	require.NotEqual(t,
+		Sign("secret", "1700000000000", http.MethodPost, "/auth/orders", []byte("{}")),
+		Sign("secret", "1700000000000", http.MethodPost, "/auth/orders{", []byte("}")),
+	)
+}
+
+func TestAuthenticate(t *testing.T) {
+	service, _ := newTestService(t)
+	resp := createKey(t, service)
+
+	key, err := service.Authenticate(context.Background(), signedRequest(resp, resp.Secret, time.Now()))
+	require.NoError(t, err)
+	require.Equal(t, "alice", key.UserID)
+}
+
+func TestAuthenticateRejectsTamperedRequest(t *testing.T) {
+	service, _ := newTestService(t)
+	resp := createKey(t, service)
+
+	args := signedRequest(resp, resp.Secret, time.Now())
+	args.Body = []byte(`{"currency_pair": {"currency1": "ETH", "currency2": "USDT"}}`)
+	_, err := service.Authenticate(context.Background(), args)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = service.Authenticate(context.Background(), signedRequest(resp, "wrong-secret", time.Now()))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestAuthenticateRejectsStaleTimestamp(t *testing.T) {
+	service, _ := newTestService(t)
+	resp := createKey(t, service)
+
+	_, err := service.Authenticate(context.Background(), signedRequest(resp, resp.Secret, time.Now().Add(-time.Minute)))
+	require.ErrorIs(t, err, ErrRequestExpired)
+
+	_, err = service.Authenticate(context.Background(), signedRequest(resp, resp.Secret, time.Now().Add(time.Minute)))
+	require.ErrorIs(t, err, ErrRequestExpired)
+}
+
+func TestAuthenticateRejectsReplay(t *testing.T) {
+	service, _ := newTestService(t)
+	resp := createKey(t, service)
+	args := signedRequest(resp, resp.Secret, time.Now())
+
+	_, err := service.Authenticate(context.Background(), args)
+	require.NoError(t, err)
+
+	_, err = service.Authenticate(context.Background(), args)
+	require.ErrorIs(t, err, ErrReplayedRequest)
+}
+
+func TestAuthenticateChecksAllowedIPs(t *testing.T) {
+	service, _ := newTestService(t)
+	resp := createKey(t, service, "198.51.100.0/24")
+
+	_, err := service.Authenticate(context.Background(), signedRequest(resp, resp.Secret, time.Now()))
+	require.ErrorIs(t, err, ErrIPNotAllowed)
+
+	args := signedRequest(resp, resp.Secret, time.Now())
+	args.RemoteIP = "198.51.100.42"
+	_, err = service.Authenticate(context.Background(), args)
+	require.NoError(t, err)
+}
+
+func TestAuthenticateRejectsRevokedKey(t *testing.T) {
+	service, _ := newTestService(t)
+	resp := createKey(t, service)
+
+	// Only the owner can revoke a key.
+	err := service.RevokeAPIKey(context.Background(), api_key_service_types.RevokeAPIKeyArgs{ID: resp.APIKey.ID, UserID: "mallory"})
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	err = service.RevokeAPIKey(context.Background(), api_key_service_types.RevokeAPIKeyArgs{ID: resp.APIKey.ID, UserID: "alice"})
+	require.NoError(t, err)
+
+	_, err = service.Authenticate(context.Background(), signedRequest(resp, resp.Secret, time.Now()))
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+}
@@ -0,0 +1,45 @@
+package api_key_service_types
+
+import (
+	"github.com/google/uuid"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// Config holds how far the timestamp of a signed request may be from the server
+// time.
+type Config struct {
+	ReplayWindow time.Duration
+}
+
+type CreateAPIKeyArgs struct {
+	UserID     string
+	Name       string
+	Scopes     []types.APIKeyScope
+	AllowedIPs []string
+}
+
+// CreateAPIKeyResponse holds the new key and its secret. The secret is shown only
+// once and cannot be recovered later.
+type CreateAPIKeyResponse struct {
+	APIKey *types.APIKey `json:"api_key"`
+	Secret string        `json:"secret"`
+}
+
+type RevokeAPIKeyArgs struct {
+	ID     uuid.UUID
+	UserID string
+}
+
+// AuthenticateArgs holds a signed request. Timestamp is in Unix milliseconds and
+// Signature is the hex HMAC-SHA256 of the timestamp, method, path and body.
+type AuthenticateArgs struct {
+	KeyID     string
+	Timestamp string
+	Signature string
+
+	Method   string
+	Path     string
+	Body     []byte
+	RemoteIP string
+}
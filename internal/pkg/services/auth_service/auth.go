@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
+	"time"
 	"vitalik_backend/internal/dependencies"
 	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
 	"vitalik_backend/internal/pkg/services/store"
@@ -13,22 +14,34 @@ import (
 )
 
 var (
-	ErrTokenExpired  = errors.New("Token expired")
-	ErrUserNotFound  = errors.New("User not found")
-	ErrAlreadyExists = errors.New("User already exists")
+	ErrTokenExpired    = errors.New("Token expired")
+	ErrInvalidToken    = errors.New("Invalid token")
+	ErrUserNotFound    = errors.New("User not found")
+	ErrAlreadyExists   = errors.New("User already exists")
+	ErrInvalidPassword = errors.New("Invalid password")
+
+	ErrOTPRequired        = errors.New("OTP code required")
+	ErrInvalidOTP         = errors.New("Invalid OTP code")
+	ErrTOTPAlreadyEnabled = errors.New("Two-factor authentication already enabled")
+	ErrTOTPNotEnrolled    = errors.New("Two-factor authentication not enrolled")
 )
 
 type AuthService struct {
-	store dependencies.IStore
+	store  dependencies.IStore
+	config auth_types.Config
 }
 
-func NewAuthService(store dependencies.IStore) (*AuthService, error) {
-	if store == nil {
+func NewAuthService(store dependencies.IStore, config auth_types.Config) (*AuthService, error) {
+	if store == nil ||
+		len(config.JWTSecret) == 0 ||
+		config.AccessTokenTTL <= 0 ||
+		config.RefreshTokenTTL <= 0 {
 		return nil, errors.New("failed to initialize auth service")
 	}
 
 	return &AuthService{
-		store: store,
+		store:  store,
+		config: config,
 	}, nil
 }
 
@@ -47,6 +60,8 @@ func (s *AuthService) Register(ctx context.Context, args auth_types.AuthArgs) er
 	user := types.User{
 		UserID:         args.UserID,
 		HashedPassword: string(hashedPassword),
+		FeeTier:        types.FeeTierStandard,
+		Role:           types.RoleUser,
 	}
 
 	if err = s.store.SaveUser(ctx, user); err != nil {
@@ -59,8 +74,6 @@ func (s *AuthService) Register(ctx context.Context, args auth_types.AuthArgs) er
 	return nil
 }
 
-var jwtSecret = []byte("Innopolis")
-
 func (s *AuthService) Login(ctx context.Context, args auth_types.AuthArgs) (*auth_types.LoginResponse, error) {
 	if args.UserID == "" || args.Password == "" {
 		return nil, errors.New("invalid user_id or password")
@@ -75,48 +88,52 @@ func (s *AuthService) Login(ctx context.Context, args auth_types.AuthArgs) (*aut
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(args.Password)); err != nil {
-		return nil, errors.New("invalid password")
+		return nil, ErrInvalidPassword
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.UserID,
-	})
+	// Users with two-factor authentication also need a TOTP or recovery code.
+	if err = s.checkOTP(ctx, user.UserID, args.OTPCode, true); err != nil {
+		return nil, err
+	}
 
-	tokenString, err := token.SignedString(jwtSecret)
+	refreshToken, resp, err := s.issueTokens(user, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign token: %w", err)
+		return nil, err
 	}
 
-	return &auth_types.LoginResponse{
-		Token: tokenString,
-	}, nil
+	if err = s.store.CreateRefreshToken(ctx, *refreshToken); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return resp, nil
 }
 
-func (s *AuthService) Authenticate(ctx context.Context, tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+// Authenticate returns the user and role carried by an access token without
+// reading the store, so it fails only for expired tokens, with ErrTokenExpired,
+// and for invalid ones. A token outlives the rotation or revocation of its refresh
+// token and a role change until it expires, which the short access token TTL bounds.
+func (s *AuthService) Authenticate(ctx context.Context, tokenString string) (*auth_types.Identity, error) {
+	claims := &accessClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
-		return jwtSecret, nil
+		return s.config.JWTSecret, nil
 	})
-	if err != nil || !token.Valid {
-		return "", errors.New("invalid token")
+	var validationErr *jwt.ValidationError
+	if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+		return nil, fmt.Errorf("access token expired: %w", ErrTokenExpired)
 	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return "", errors.New("invalid token claims")
-	}
-
-	userID, ok := claims["user_id"].(string)
-	if !ok || userID == "" {
-		return "", fmt.Errorf("invalid user ID in token claims: %w", ErrUserNotFound)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
 	}
 
-	_, err = s.store.GetUser(ctx, userID)
-	if err != nil {
-		return "", fmt.Errorf("user not found: %w", ErrUserNotFound)
+	if claims.UserID == "" || claims.ExpiresAt == 0 || !claims.Role.Validate() {
+		return nil, fmt.Errorf("invalid token claims: %w", ErrInvalidToken)
 	}
 
-	return userID, nil
+	return &auth_types.Identity{
+		UserID: claims.UserID,
+		Role:   claims.Role,
+	}, nil
 }
@@ -0,0 +1,358 @@
+package auth_service
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+var testSecret = []byte("test-secret")
+
+// fakeStore keeps users, refresh tokens and authenticators in memory.
+type fakeStore struct {
+	dependencies.IStore
+
+	mu              sync.Mutex
+	users           map[string]types.User
+	refreshTokens   map[uuid.UUID]types.RefreshToken
+	totpEnrollments map[string]types.TOTPEnrollment
+	recoveryCodes   map[string][]types.RecoveryCode
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users:           make(map[string]types.User),
+		refreshTokens:   make(map[uuid.UUID]types.RefreshToken),
+		totpEnrollments: make(map[string]types.TOTPEnrollment),
+		recoveryCodes:   make(map[string][]types.RecoveryCode),
+	}
+}
+
+func (s *fakeStore) SaveUser(ctx context.Context, user types.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[user.UserID]; ok {
+		return store.ErrAlreadyExists
+	}
+	s.users[user.UserID] = user
+	return nil
+}
+
+func (s *fakeStore) GetUser(ctx context.Context, userID string) (*types.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (s *fakeStore) SetUserRole(ctx context.Context, args store_types.SetUserRoleArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[args.UserID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	user.Role = args.Role
+	s.users[args.UserID] = user
+	return nil
+}
+
+func (s *fakeStore) SetUserPassword(ctx context.Context, args store_types.SetUserPasswordArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[args.UserID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	user.HashedPassword = args.HashedPassword
+	s.users[args.UserID] = user
+
+	for id, token := range s.refreshTokens {
+		if token.UserID == args.UserID && !token.RevokedAt.Valid {
+			token.RevokedAt.SetValid(args.RevokedAt)
+			s.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) SaveTOTPEnrollment(ctx context.Context, enrollment types.TOTPEnrollment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.totpEnrollments[enrollment.UserID]; ok && current.IsConfirmed() {
+		return store.ErrAlreadyExists
+	}
+	s.totpEnrollments[enrollment.UserID] = enrollment
+	return nil
+}
+
+func (s *fakeStore) GetTOTPEnrollment(ctx context.Context, userID string) (*types.TOTPEnrollment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, ok := s.totpEnrollments[userID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &enrollment, nil
+}
+
+func (s *fakeStore) ConfirmTOTPEnrollment(ctx context.Context, args store_types.ConfirmTOTPEnrollmentArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, ok := s.totpEnrollments[args.UserID]
+	if !ok || enrollment.IsConfirmed() {
+		return store.ErrNotFound
+	}
+	enrollment.LastStep = args.Step
+	enrollment.ConfirmedAt.SetValid(args.ConfirmedAt)
+	s.totpEnrollments[args.UserID] = enrollment
+	s.recoveryCodes[args.UserID] = args.RecoveryCodes
+	return nil
+}
+
+func (s *fakeStore) UseTOTPStep(ctx context.Context, args store_types.UseTOTPStepArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, ok := s.totpEnrollments[args.UserID]
+	if !ok || !enrollment.IsConfirmed() || enrollment.LastStep >= args.Step {
+		return store.ErrNotFound
+	}
+	enrollment.LastStep = args.Step
+	s.totpEnrollments[args.UserID] = enrollment
+	return nil
+}
+
+func (s *fakeStore) UseRecoveryCode(ctx context.Context, args store_types.UseRecoveryCodeArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, code := range s.recoveryCodes[args.UserID] {
+		if code.CodeHash == args.CodeHash && !code.UsedAt.Valid {
+			s.recoveryCodes[args.UserID][i].UsedAt.SetValid(args.UsedAt)
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (s *fakeStore) DeleteTOTPEnrollment(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.totpEnrollments[userID]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.totpEnrollments, userID)
+	delete(s.recoveryCodes, userID)
+	return nil
+}
+
+func (s *fakeStore) CreateRefreshToken(ctx context.Context, token types.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshTokens[token.ID] = token
+	return nil
+}
+
+func (s *fakeStore) GetRefreshToken(ctx context.Context, args store_types.GetRefreshTokenArgs) (*types.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.refreshTokens {
+		if (args.TokenHash.Valid && token.TokenHash == args.TokenHash.String) ||
+			(args.AccessJTI.Valid && token.AccessJTI == args.AccessJTI.UUID) {
+			return &token, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *fakeStore) RotateRefreshToken(ctx context.Context, args store_types.RotateRefreshTokenArgs) error {
+	if err := s.RevokeRefreshToken(ctx, store_types.RevokeRefreshTokenArgs{ID: args.ID, RevokedAt: args.RevokedAt}); err != nil {
+		return err
+	}
+	return s.CreateRefreshToken(ctx, args.Next)
+}
+
+func (s *fakeStore) RevokeRefreshToken(ctx context.Context, args store_types.RevokeRefreshTokenArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.refreshTokens[args.ID]
+	if !ok || token.RevokedAt.Valid {
+		return store.ErrNotFound
+	}
+	token.RevokedAt.SetValid(args.RevokedAt)
+	s.refreshTokens[args.ID] = token
+	return nil
+}
+
+func newTestService(t *testing.T) *AuthService {
+	t.Helper()
+
+	s, err := NewAuthService(newFakeStore(), auth_types.Config{
+		JWTSecret:       testSecret,
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	require.NoError(t, err)
+
+	args := auth_types.AuthArgs{UserID: "alice", Password: "password"}
+	require.NoError(t, s.Register(context.Background(), args))
+
+	return s
+}
+
+func login(t *testing.T, s *AuthService) *auth_types.LoginResponse {
+	t.Helper()
+
+	resp, err := s.Login(context.Background(), auth_types.AuthArgs{UserID: "alice", Password: "password"})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestLoginIssuesClaims(t *testing.T) {
+	s := newTestService(t)
+	resp := login(t, s)
+
+	claims := &accessClaims{}
+	_, err := jwt.ParseWithClaims(resp.Token, claims, func(token *jwt.Token) (interface{}, error) {
+		return testSecret, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "alice", claims.UserID)
+	require.Equal(t, types.RoleUser, claims.Role)
+	require.NotZero(t, claims.IssuedAt)
+	require.Equal(t, resp.ExpiresAt.Unix(), claims.ExpiresAt)
+	require.NoError(t, uuid.Validate(claims.Id))
+
+	// Only the hash of the refresh token is stored.
+	stored, err := s.store.GetRefreshToken(context.Background(), store_types.GetRefreshTokenArgs{
+		AccessJTI: uuid.NullUUID{UUID: uuid.MustParse(claims.Id), Valid: true},
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, resp.RefreshToken, stored.TokenHash)
+	require.Equal(t, hashRefreshToken(resp.RefreshToken), stored.TokenHash)
+
+	identity, err := s.Authenticate(context.Background(), resp.Token)
+	require.NoError(t, err)
+	require.Equal(t, &auth_types.Identity{UserID: "alice", Role: types.RoleUser}, identity)
+}
+
+func TestAuthenticateSkipsStore(t *testing.T) {
+	resp := login(t, newTestService(t))
+
+	// The embedded nil store panics on any call.
+	s, err := NewAuthService(struct{ dependencies.IStore }{}, auth_types.Config{
+		JWTSecret:       testSecret,
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	require.NoError(t, err)
+
+	identity, err := s.Authenticate(context.Background(), resp.Token)
+	require.NoError(t, err)
+	require.Equal(t, &auth_types.Identity{UserID: "alice", Role: types.RoleUser}, identity)
+}
+
+func TestAuthenticateRejectsExpiredToken(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		UserID: "alice",
+		Role:   types.RoleUser,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			IssuedAt:  time.Now().Add(-time.Hour).Unix(),
+			ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+		},
+	}).SignedString(testSecret)
+	require.NoError(t, err)
+
+	_, err = s.Authenticate(ctx, expired)
+	require.ErrorIs(t, err, ErrTokenExpired)
+
+	// Tokens without an expiry, as issued before, are no longer accepted.
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "alice"}).SignedString(testSecret)
+	require.NoError(t, err)
+
+	_, err = s.Authenticate(ctx, legacy)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestRefreshRotatesTokens(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	first := login(t, s)
+
+	second, err := s.Refresh(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	require.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// The rotated refresh token is revoked, the new one works.
+	_, err = s.Refresh(ctx, first.RefreshToken)
+	require.ErrorIs(t, err, ErrTokenExpired)
+
+	identity, err := s.Authenticate(ctx, second.Token)
+	require.NoError(t, err)
+	require.Equal(t, "alice", identity.UserID)
+
+	_, err = s.Refresh(ctx, "unknown")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	resp := login(t, s)
+
+	require.NoError(t, s.Logout(ctx, resp.RefreshToken))
+
+	_, err := s.Refresh(ctx, resp.RefreshToken)
+	require.ErrorIs(t, err, ErrTokenExpired)
+
+	// Logging out twice is harmless.
+	require.NoError(t, s.Logout(ctx, resp.RefreshToken))
+}
+
+func TestRoleChangeRequiresRefresh(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	resp := login(t, s)
+
+	require.NoError(t, s.store.SetUserRole(ctx, store_types.SetUserRoleArgs{UserID: "alice", Role: types.RoleAdmin}))
+
+	// The token keeps the old role until it is refreshed.
+	identity, err := s.Authenticate(ctx, resp.Token)
+	require.NoError(t, err)
+	require.Equal(t, types.RoleUser, identity.Role)
+
+	refreshed, err := s.Refresh(ctx, resp.RefreshToken)
+	require.NoError(t, err)
+
+	identity, err = s.Authenticate(ctx, refreshed.Token)
+	require.NoError(t, err)
+	require.Equal(t, types.RoleAdmin, identity.Role)
+}
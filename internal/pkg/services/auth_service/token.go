@@ -0,0 +1,171 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"time"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// refreshTokenBytes is the entropy of a refresh token.
+const refreshTokenBytes = 32
+
+// accessClaims are the claims of an access token. The standard claims carry the
+// exp, iat and jti.
+type accessClaims struct {
+	UserID string     `json:"user_id"`
+	Role   types.Role `json:"role"`
+	jwt.StandardClaims
+}
+
+// Refresh exchanges an active refresh token for a new access and refresh token
+// carrying the current role of the user. The used refresh token is revoked, so
+// each refresh token works once.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*auth_types.LoginResponse, error) {
+	current, err := s.getRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !current.IsActive(now) {
+		return nil, fmt.Errorf("refresh token is no longer active: %w", ErrTokenExpired)
+	}
+
+	user, err := s.store.GetUser(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("user not found: %w", ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	next, resp, err := s.issueTokens(user, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.RotateRefreshToken(ctx, store_types.RotateRefreshTokenArgs{
+		ID:        current.ID,
+		RevokedAt: now,
+		Next:      *next,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("refresh token is no longer active: %w", ErrTokenExpired)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Logout revokes the refresh token, so the session ends once its access token
+// expires. Logging out of a session that is already revoked succeeds.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.getRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if current.RevokedAt.Valid {
+		return nil
+	}
+
+	err = s.store.RevokeRefreshToken(ctx, store_types.RevokeRefreshTokenArgs{
+		ID:        current.ID,
+		RevokedAt: time.Now(),
+	})
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) getRefreshToken(ctx context.Context, refreshToken string) (*types.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("refresh token must be provided: %w", ErrInvalidToken)
+	}
+
+	token, err := s.store.GetRefreshToken(ctx, store_types.GetRefreshTokenArgs{
+		TokenHash: null.StringFrom(hashRefreshToken(refreshToken)),
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("unknown refresh token: %w", ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// issueTokens signs an access token for the user and its current role and creates
+// the refresh token paired with it. The refresh token is returned in plain text
+// only in the response.
+func (s *AuthService) issueTokens(user *types.User, now time.Time) (*types.RefreshToken, *auth_types.LoginResponse, error) {
+	jti, err := uuid.NewV7()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate jti: %w", err)
+	}
+
+	expiresAt := now.Add(s.config.AccessTokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		UserID: user.UserID,
+		Role:   user.Role,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti.String(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	})
+
+	tokenString, err := token.SignedString(s.config.JWTSecret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	secret := make([]byte, refreshTokenBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	plainRefreshToken := hex.EncodeToString(secret)
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate refresh token id: %w", err)
+	}
+
+	refreshToken := &types.RefreshToken{
+		ID:        id,
+		UserID:    user.UserID,
+		TokenHash: hashRefreshToken(plainRefreshToken),
+		AccessJTI: jti,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.config.RefreshTokenTTL),
+	}
+
+	return refreshToken, &auth_types.LoginResponse{
+		Token:        tokenString,
+		ExpiresAt:    expiresAt,
+		RefreshToken: plainRefreshToken,
+	}, nil
+}
+
+// hashRefreshToken hashes a refresh token for storage. The tokens are random, so
+// an unsalted SHA-256 is enough and keeps them searchable.
+func hashRefreshToken(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,314 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// The authenticator settings follow RFC 6238 defaults, which every authenticator
+// app supports: SHA-1, 6 digits and 30 second steps. A code is accepted one step
+// before or after the current one to allow for clock drift.
+const (
+	totpIssuer      = "vitalik"
+	totpSecretBytes = 20
+	totpDigits      = 6
+	totpPeriod      = 30
+	totpSkew        = 1
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 10
+)
+
+var (
+	totpBase32   = base32.StdEncoding.WithPadding(base32.NoPadding)
+	totpCodeExpr = regexp.MustCompile(fmt.Sprintf(`^[0-9]{%d}$`, totpDigits))
+)
+
+// EnrollTOTP starts enrolling an authenticator. The returned secret and otpauth
+// URI are shown to the user once, the enrollment protects the account only after
+// ConfirmTOTP. Enrolling again replaces a pending enrollment.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID string) (*auth_types.EnrollTOTPResponse, error) {
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	encodedSecret := totpBase32.EncodeToString(secret)
+
+	err := s.store.SaveTOTPEnrollment(ctx, types.TOTPEnrollment{
+		UserID:    userID,
+		Secret:    encodedSecret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrAlreadyExists) {
+			return nil, ErrTOTPAlreadyEnabled
+		}
+		return nil, fmt.Errorf("failed to save totp enrollment: %w", err)
+	}
+
+	return &auth_types.EnrollTOTPResponse{
+		Secret: encodedSecret,
+		URI:    totpURI(userID, encodedSecret),
+	}, nil
+}
+
+// ConfirmTOTP confirms the pending enrollment with a first code and returns the
+// recovery codes. They are shown only once, only their hashes are stored.
+func (s *AuthService) ConfirmTOTP(ctx context.Context, args auth_types.ConfirmTOTPArgs) (*auth_types.ConfirmTOTPResponse, error) {
+	enrollment, err := s.store.GetTOTPEnrollment(ctx, args.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrTOTPNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get totp enrollment: %w", err)
+	}
+	if enrollment.IsConfirmed() {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	now := time.Now()
+	step, ok := matchTOTP(enrollment.Secret, args.Code, now, enrollment.LastStep)
+	if !ok {
+		return nil, ErrInvalidOTP
+	}
+
+	plainCodes := make([]string, 0, recoveryCodeCount)
+	recoveryCodes := make([]types.RecoveryCode, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code id: %w", err)
+		}
+
+		plainCodes = append(plainCodes, code)
+		recoveryCodes = append(recoveryCodes, types.RecoveryCode{
+			ID:        id,
+			UserID:    args.UserID,
+			CodeHash:  hashRecoveryCode(code),
+			CreatedAt: now,
+		})
+	}
+
+	err = s.store.ConfirmTOTPEnrollment(ctx, store_types.ConfirmTOTPEnrollmentArgs{
+		UserID:        args.UserID,
+		Step:          step,
+		ConfirmedAt:   now,
+		RecoveryCodes: recoveryCodes,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrTOTPNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to confirm totp enrollment: %w", err)
+	}
+
+	return &auth_types.ConfirmTOTPResponse{RecoveryCodes: plainCodes}, nil
+}
+
+// DisableTOTP removes the authenticator and the recovery codes of the user.
+func (s *AuthService) DisableTOTP(ctx context.Context, userID string) error {
+	if err := s.store.DeleteTOTPEnrollment(ctx, userID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrTOTPNotEnrolled
+		}
+		return fmt.Errorf("failed to delete totp enrollment: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyOTP checks a fresh TOTP code of a user with two-factor authentication.
+// Users without a confirmed authenticator pass without a code. Recovery codes are
+// accepted only at login.
+func (s *AuthService) VerifyOTP(ctx context.Context, args auth_types.VerifyOTPArgs) error {
+	return s.checkOTP(ctx, args.UserID, args.Code, false)
+}
+
+// ChangePassword replaces the password of a user who knows the current one and
+// revokes all of the user's refresh tokens, so every session has to log in again
+// once its access token expires.
+func (s *AuthService) ChangePassword(ctx context.Context, args auth_types.ChangePasswordArgs) error {
+	if args.NewPassword == "" {
+		return errors.New("invalid new password")
+	}
+
+	user, err := s.store.GetUser(ctx, args.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("user not found: %w", ErrUserNotFound)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(args.OldPassword)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(args.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	err = s.store.SetUserPassword(ctx, store_types.SetUserPasswordArgs{
+		UserID:         args.UserID,
+		HashedPassword: string(hashedPassword),
+		RevokedAt:      time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save password: %w", err)
+	}
+
+	return nil
+}
+
+// checkOTP checks the second factor of a user with a confirmed authenticator. An
+// accepted TOTP code or recovery code is used up, so it cannot be replayed.
+func (s *AuthService) checkOTP(ctx context.Context, userID string, code string, allowRecovery bool) error {
+	enrollment, err := s.store.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get totp enrollment: %w", err)
+	}
+	if !enrollment.IsConfirmed() {
+		return nil
+	}
+
+	if code == "" {
+		return ErrOTPRequired
+	}
+
+	if totpCodeExpr.MatchString(code) {
+		step, ok := matchTOTP(enrollment.Secret, code, time.Now(), enrollment.LastStep)
+		if !ok {
+			return ErrInvalidOTP
+		}
+
+		err = s.store.UseTOTPStep(ctx, store_types.UseTOTPStepArgs{UserID: userID, Step: step})
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return ErrInvalidOTP
+			}
+			return fmt.Errorf("failed to use totp code: %w", err)
+		}
+
+		return nil
+	}
+
+	if !allowRecovery {
+		return ErrInvalidOTP
+	}
+
+	err = s.store.UseRecoveryCode(ctx, store_types.UseRecoveryCodeArgs{
+		UserID:   userID,
+		CodeHash: hashRecoveryCode(code),
+		UsedAt:   time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvalidOTP
+		}
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	return nil
+}
+
+// matchTOTP returns the time step a code is valid for around the time. Steps up
+// to lastStep were already used and are skipped.
+func matchTOTP(secret string, code string, now time.Time, lastStep int64) (int64, bool) {
+	key, err := totpBase32.DecodeString(secret)
+	if err != nil || !totpCodeExpr.MatchString(code) {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode computes the code of a time step as specified by RFC 4226 and RFC 6238.
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%uint32(math.Pow10(totpDigits)))
+}
+
+// totpURI builds the otpauth URI authenticator apps read from a QR code.
+func totpURI(userID string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + userID,
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
+
+// generateRecoveryCode returns a random code formatted as two groups of eight
+// characters for readability.
+func generateRecoveryCode() (string, error) {
+	secret := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+
+	code := strings.ToLower(totpBase32.EncodeToString(secret))
+	return code[:8] + "-" + code[8:], nil
+}
+
+// hashRecoveryCode hashes a recovery code for storage. The codes are random, so an
+// unsalted SHA-256 is enough. Case and dashes are ignored.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
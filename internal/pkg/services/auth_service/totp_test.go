@@ -0,0 +1,170 @@
+package auth_service
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+	"time"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+)
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	// The RFC lists 8 digit codes, the last 6 digits are the 6 digit code.
+	for unix, code := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		require.Equal(t, code, totpCode(key, unix/totpPeriod))
+	}
+}
+
+// enable enrolls and confirms an authenticator of alice and returns its secret
+// and recovery codes.
+func enable(t *testing.T, s *AuthService) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := s.EnrollTOTP(ctx, "alice")
+	require.NoError(t, err)
+
+	resp, err := s.ConfirmTOTP(ctx, auth_types.ConfirmTOTPArgs{
+		UserID: "alice",
+		Code:   codeAt(t, enrollment.Secret, time.Now()),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+
+	return enrollment.Secret, resp.RecoveryCodes
+}
+
+func codeAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	key, err := totpBase32.DecodeString(secret)
+	require.NoError(t, err)
+	return totpCode(key, at.Unix()/totpPeriod)
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	resp, err := s.EnrollTOTP(ctx, "alice")
+	require.NoError(t, err)
+
+	uri, err := url.Parse(resp.URI)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/vitalik:alice", uri.Path)
+	require.Equal(t, resp.Secret, uri.Query().Get("secret"))
+	require.Equal(t, "vitalik", uri.Query().Get("issuer"))
+
+	// A pending enrollment does not protect the account yet.
+	login(t, s)
+
+	_, err = s.ConfirmTOTP(ctx, auth_types.ConfirmTOTPArgs{UserID: "alice", Code: "000000"})
+	require.ErrorIs(t, err, ErrInvalidOTP)
+
+	_, err = s.ConfirmTOTP(ctx, auth_types.ConfirmTOTPArgs{UserID: "mallory", Code: "000000"})
+	require.ErrorIs(t, err, ErrTOTPNotEnrolled)
+}
+
+func TestLoginRequiresOTP(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	secret, _ := enable(t, s)
+
+	_, err := s.EnrollTOTP(ctx, "alice")
+	require.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+
+	args := auth_types.AuthArgs{UserID: "alice", Password: "password"}
+	_, err = s.Login(ctx, args)
+	require.ErrorIs(t, err, ErrOTPRequired)
+
+	args.OTPCode = "000000"
+	_, err = s.Login(ctx, args)
+	require.ErrorIs(t, err, ErrInvalidOTP)
+
+	// The code of the next step is accepted once, the confirming code was used up.
+	args.OTPCode = codeAt(t, secret, time.Now().Add(totpPeriod*time.Second))
+	_, err = s.Login(ctx, args)
+	require.NoError(t, err)
+
+	_, err = s.Login(ctx, args)
+	require.ErrorIs(t, err, ErrInvalidOTP)
+}
+
+func TestLoginWithRecoveryCode(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	_, recoveryCodes := enable(t, s)
+
+	// Only hashes of the recovery codes are stored.
+	stored := s.store.(*fakeStore).recoveryCodes["alice"]
+	require.Len(t, stored, recoveryCodeCount)
+	require.NotEqual(t, recoveryCodes[0], stored[0].CodeHash)
+
+	args := auth_types.AuthArgs{UserID: "alice", Password: "password", OTPCode: recoveryCodes[0]}
+	_, err := s.Login(ctx, args)
+	require.NoError(t, err)
+
+	_, err = s.Login(ctx, args)
+	require.ErrorIs(t, err, ErrInvalidOTP)
+
+	// Recovery codes do not replace a fresh code for sensitive operations.
+	err = s.VerifyOTP(ctx, auth_types.VerifyOTPArgs{UserID: "alice", Code: recoveryCodes[1]})
+	require.ErrorIs(t, err, ErrInvalidOTP)
+}
+
+func TestVerifyOTP(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	// Users without an authenticator pass without a code.
+	require.NoError(t, s.VerifyOTP(ctx, auth_types.VerifyOTPArgs{UserID: "alice"}))
+
+	secret, _ := enable(t, s)
+
+	err := s.VerifyOTP(ctx, auth_types.VerifyOTPArgs{UserID: "alice"})
+	require.ErrorIs(t, err, ErrOTPRequired)
+
+	code := codeAt(t, secret, time.Now().Add(totpPeriod*time.Second))
+	require.NoError(t, s.VerifyOTP(ctx, auth_types.VerifyOTPArgs{UserID: "alice", Code: code}))
+
+	err = s.VerifyOTP(ctx, auth_types.VerifyOTPArgs{UserID: "alice", Code: code})
+	require.ErrorIs(t, err, ErrInvalidOTP)
+
+	require.NoError(t, s.DisableTOTP(ctx, "alice"))
+	require.NoError(t, s.VerifyOTP(ctx, auth_types.VerifyOTPArgs{UserID: "alice"}))
+}
+
+func TestChangePassword(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	first := login(t, s)
+	second := login(t, s)
+
+	err := s.ChangePassword(ctx, auth_types.ChangePasswordArgs{UserID: "alice", OldPassword: "wrong", NewPassword: "new-password"})
+	require.ErrorIs(t, err, ErrInvalidPassword)
+
+	err = s.ChangePassword(ctx, auth_types.ChangePasswordArgs{UserID: "alice", OldPassword: "password", NewPassword: "new-password"})
+	require.NoError(t, err)
+
+	_, err = s.Login(ctx, auth_types.AuthArgs{UserID: "alice", Password: "password"})
+	require.ErrorIs(t, err, ErrInvalidPassword)
+
+	// Sessions opened with the old password are revoked.
+	for _, resp := range []*auth_types.LoginResponse{first, second} {
+		_, err = s.Refresh(ctx, resp.RefreshToken)
+		require.ErrorIs(t, err, ErrTokenExpired)
+	}
+
+	_, err = s.Login(ctx, auth_types.AuthArgs{UserID: "alice", Password: "new-password"})
+	require.NoError(t, err)
+}
@@ -1,10 +1,64 @@
 package auth_types
 
+import (
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// AuthArgs holds the credentials of a user. OTPCode is a TOTP or recovery code,
+// required at login once two-factor authentication is enabled.
 type AuthArgs struct {
 	UserID   string `json:"user_id"`
 	Password string `json:"password"`
+	OTPCode  string `json:"otp_code"`
 }
 
+// LoginResponse holds a short-lived access token and the refresh token that
+// exchanges it for a new pair once it expires.
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+// Config holds the secret the access tokens are signed with and the lifetimes
+// of the access and refresh tokens.
+type Config struct {
+	JWTSecret       []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// Identity is the user an access token was issued to and the role it carries.
+type Identity struct {
+	UserID string
+	Role   types.Role
+}
+
+// EnrollTOTPResponse holds the secret of a new authenticator and the otpauth URI
+// to render as a QR code.
+type EnrollTOTPResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type ConfirmTOTPArgs struct {
+	UserID string
+	Code   string
+}
+
+// ConfirmTOTPResponse holds the recovery codes, shown only once.
+type ConfirmTOTPResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type VerifyOTPArgs struct {
+	UserID string
+	Code   string
+}
+
+type ChangePasswordArgs struct {
+	UserID      string
+	OldPassword string
+	NewPassword string
 }
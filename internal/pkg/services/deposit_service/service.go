@@ -0,0 +1,114 @@
+package deposit_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+)
+
+// syncInterval is how often the deposit source is polled.
+const syncInterval = 15 * time.Second
+
+// DepositService polls the deposit source and credits every transfer once it has
+// the confirmations its currency requires.
+type DepositService struct {
+	store  dependencies.IStore
+	source dependencies.IDepositSource
+	logger *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewDepositService(
+	store dependencies.IStore,
+	source dependencies.IDepositSource,
+	logger *zap.Logger,
+) (*DepositService, error) {
+	if store == nil || source == nil || logger == nil {
+		return nil, errors.New("failed to initialize deposit service")
+	}
+
+	return &DepositService{
+		store:  store,
+		source: source,
+		logger: logger,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+var _ dependencies.IDepositService = (*DepositService)(nil)
+
+// Start polls the deposit source periodically until Stop is called.
+func (s *DepositService) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.SyncDeposits(ctx); err != nil {
+					s.logger.Error("SyncDeposits failed", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *DepositService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+}
+
+// SyncDeposits records the transfers reported by the deposit source and credits
+// the confirmed ones. Transfers to unknown wallets are skipped.
+func (s *DepositService) SyncDeposits(ctx context.Context) error {
+	transfers, err := s.source.Poll(ctx)
+	if err != nil {
+		return fmt.Errorf("source.Poll failed: %w", err)
+	}
+
+	var errs []error
+	for _, transfer := range transfers {
+		deposit, err := s.store.SaveDeposit(ctx, store_types.SaveDepositArgs{
+			Transfer:              transfer,
+			RequiredConfirmations: transfer.Currency.RequiredConfirmations(),
+		})
+		if errors.Is(err, store.ErrNotFound) {
+			s.logger.Warn("deposit to unknown wallet",
+				zap.String("tx_hash", transfer.TxHash),
+				zap.String("address", transfer.Address),
+			)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deposit %s: %w", transfer.TxHash, err))
+			continue
+		}
+
+		s.logger.Debug("deposit saved",
+			zap.String("tx_hash", deposit.TxHash),
+			zap.Int("confirmations", deposit.Confirmations),
+			zap.String("status", string(deposit.Status)),
+		)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package deposit_service
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/services/deposit_source_service"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// fakeStore keeps deposits in memory and credits them once like the real store.
+type fakeStore struct {
+	dependencies.IStore
+
+	mu       sync.Mutex
+	wallets  map[string]types.Currency
+	balances map[string]decimal.Decimal
+	deposits map[string]types.Deposit
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		wallets:  map[string]types.Currency{"alice-BTC": types.BTC},
+		balances: make(map[string]decimal.Decimal),
+		deposits: make(map[string]types.Deposit),
+	}
+}
+
+func (s *fakeStore) SaveDeposit(ctx context.Context, args store_types.SaveDepositArgs) (*types.Deposit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfer := args.Transfer
+	if currency, ok := s.wallets[transfer.Address]; !ok || currency != transfer.Currency {
+		return nil, store.ErrNotFound
+	}
+
+	deposit, ok := s.deposits[transfer.TxHash]
+	if !ok {
+		deposit = types.Deposit{
+			TxHash:     transfer.TxHash,
+			Requisites: types.Requisites{Address: transfer.Address},
+			Currency:   transfer.Currency,
+			Amount:     transfer.Amount,
+			Status:     types.DepositPending,
+		}
+	}
+
+	if deposit.Status == types.DepositPending {
+		deposit.Confirmations = max(deposit.Confirmations, transfer.Confirmations)
+		if deposit.Confirmations >= args.RequiredConfirmations {
+			deposit.Status = types.DepositCredited
+			s.balances[deposit.Requisites.Address] = s.balances[deposit.Requisites.Address].Add(deposit.Amount)
+		}
+	}
+	s.deposits[transfer.TxHash] = deposit
+
+	return &deposit, nil
+}
+
+func (s *fakeStore) balance(address string) decimal.Decimal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.balances[address]
+}
+
+func (s *fakeStore) status(txHash string) types.DepositStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deposits[txHash].Status
+}
+
+func TestSyncDepositsCreditsOnce(t *testing.T) {
+	ctx := context.Background()
+	fakeStore := newFakeStore()
+	source := deposit_source_service.NewFakeDepositSource()
+
+	s, err := NewDepositService(fakeStore, source, zap.NewNop())
+	require.NoError(t, err)
+
+	source.Add(types.IncomingTransfer{
+		TxHash:        "tx-1",
+		Address:       "alice-BTC",
+		Currency:      types.BTC,
+		Amount:        decimal.NewFromInt(2),
+		Confirmations: 1,
+	})
+
+	// Below the required confirmations the deposit stays pending.
+	require.NoError(t, s.SyncDeposits(ctx))
+	require.Equal(t, types.DepositPending, fakeStore.status("tx-1"))
+	require.True(t, fakeStore.balance("alice-BTC").IsZero())
+
+	currency := types.BTC
+	source.Confirm("tx-1", currency.RequiredConfirmations())
+	require.NoError(t, s.SyncDeposits(ctx))
+	require.Equal(t, types.DepositCredited, fakeStore.status("tx-1"))
+	require.Equal(t, "2", fakeStore.balance("alice-BTC").String())
+
+	// The source keeps reporting the transfer, it is not credited again.
+	source.Confirm("tx-1", currency.RequiredConfirmations()+1)
+	require.NoError(t, s.SyncDeposits(ctx))
+	require.Equal(t, "2", fakeStore.balance("alice-BTC").String())
+}
+
+func TestSyncDepositsSkipsUnknownWallets(t *testing.T) {
+	ctx := context.Background()
+	fakeStore := newFakeStore()
+	source := deposit_source_service.NewFakeDepositSource()
+
+	s, err := NewDepositService(fakeStore, source, zap.NewNop())
+	require.NoError(t, err)
+
+	source.Add(types.IncomingTransfer{
+		TxHash:        "tx-unknown",
+		Address:       "nobody-BTC",
+		Currency:      types.BTC,
+		Amount:        decimal.NewFromInt(1),
+		Confirmations: 10,
+	})
+	source.Add(types.IncomingTransfer{
+		TxHash:        "tx-2",
+		Address:       "alice-BTC",
+		Currency:      types.BTC,
+		Amount:        decimal.NewFromInt(1),
+		Confirmations: 10,
+	})
+
+	require.NoError(t, s.SyncDeposits(ctx))
+	require.Equal(t, "1", fakeStore.balance("alice-BTC").String())
+}
@@ -0,0 +1,50 @@
+package deposit_source_service
+
+import (
+	"context"
+	"sync"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// FakeDepositSource is an in-memory deposit source. Transfers are added with Add
+// and gain confirmations with Confirm, and are reported on every poll.
+type FakeDepositSource struct {
+	mu        sync.Mutex
+	transfers []types.IncomingTransfer
+}
+
+func NewFakeDepositSource() *FakeDepositSource {
+	return &FakeDepositSource{}
+}
+
+var _ dependencies.IDepositSource = (*FakeDepositSource)(nil)
+
+func (s *FakeDepositSource) Poll(ctx context.Context) ([]types.IncomingTransfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers := make([]types.IncomingTransfer, len(s.transfers))
+	copy(transfers, s.transfers)
+
+	return transfers, nil
+}
+
+func (s *FakeDepositSource) Add(transfer types.IncomingTransfer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transfers = append(s.transfers, transfer)
+}
+
+// Confirm sets the confirmations of every reported transfer with the tx hash.
+func (s *FakeDepositSource) Confirm(txHash string, confirmations int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.transfers {
+		if s.transfers[i].TxHash == txHash {
+			s.transfers[i].Confirmations = confirmations
+		}
+	}
+}
@@ -7,39 +7,60 @@ import (
 	"vitalik_backend/internal/dependencies"
 )
 
+// Config controls the periodic work of the matcher. Orders are matched as soon as
+// they are created, so the sweep of all books is only a safety net and is
+// disabled when SweepInterval is zero.
+type Config struct {
+	SweepInterval  time.Duration
+	ExpiryInterval time.Duration
+}
+
 type Matcher struct {
 	orderBookManager dependencies.IOrderBookManager
 	logger           *zap.Logger
-	interval         time.Duration
+	config           Config
 }
 
-func NewMatcher(orderBookManager dependencies.IOrderBookManager, logger *zap.Logger) *Matcher {
+func NewMatcher(orderBookManager dependencies.IOrderBookManager, logger *zap.Logger, config Config) *Matcher {
 	return &Matcher{
 		orderBookManager: orderBookManager,
 		logger:           logger,
-		interval:         3 * time.Second,
+		config:           config,
 	}
 }
 
 func (m *Matcher) Start(ctx context.Context) {
-	ticker := time.NewTicker(m.interval)
-	defer ticker.Stop()
+	expiryTicker := time.NewTicker(m.config.ExpiryInterval)
+	defer expiryTicker.Stop()
+
+	// A nil channel never fires, which keeps the sweep off when it is disabled.
+	var sweep <-chan time.Time
+	if m.config.SweepInterval > 0 {
+		sweepTicker := time.NewTicker(m.config.SweepInterval)
+		defer sweepTicker.Stop()
+		sweep = sweepTicker.C
+	}
 
-	m.logger.Info("Matcher started, will call handler every", zap.Duration("interval", m.interval))
+	m.logger.Info("Matcher started",
+		zap.Duration("sweep_interval", m.config.SweepInterval),
+		zap.Duration("expiry_interval", m.config.ExpiryInterval),
+	)
 
 	ctx = context.Background()
 
 	for {
 		select {
-		case <-ticker.C:
-			err := m.orderBookManager.MatchOrders(ctx)
-			if err != nil {
-				m.logger.Error("Error calling handler", zap.Error(err))
+		case <-expiryTicker.C:
+			if err := m.orderBookManager.ExpireOrders(ctx); err != nil {
+				m.logger.Error("Error expiring orders", zap.Error(err))
+			}
+		case <-sweep:
+			if err := m.orderBookManager.MatchOrders(ctx); err != nil {
+				m.logger.Error("Error sweeping order books", zap.Error(err))
 			}
 		case <-ctx.Done():
 			m.logger.Info("Worker stopped")
 			return
 		}
-
 	}
 }
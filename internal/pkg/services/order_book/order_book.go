@@ -1,14 +1,29 @@
 package order_book
 
 import (
+	"github.com/shopspring/decimal"
+	"sync"
 	"vitalik_backend/internal/pkg/types"
 )
 
+// OrderBook holds open orders of a single currency pair.
+// The embedded mutex must be held while reading or mutating any of its fields.
 type OrderBook struct {
+	sync.Mutex
+
 	CurrencyPair types.CurrencyPair
 
 	SellOrders []*types.Order
 	BuyOrders  []*types.Order
+
+	// PendingOrders are trigger orders waiting off-book for LastPrice to cross their trigger price.
+	PendingOrders []*types.Order
+
+	LastPrice decimal.NullDecimal
+
+	// Halted stops the pair from trading: new orders are rejected and the book is
+	// not matched, while resting orders can still be cancelled.
+	Halted bool
 }
 
 func NewOrderBook(currencyPair types.CurrencyPair) (*OrderBook, error) {
@@ -17,5 +32,7 @@ func NewOrderBook(currencyPair types.CurrencyPair) (*OrderBook, error) {
 
 		SellOrders: make([]*types.Order, 0),
 		BuyOrders:  make([]*types.Order, 0),
+
+		PendingOrders: make([]*types.Order, 0),
 	}, nil
 }
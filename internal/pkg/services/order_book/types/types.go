@@ -2,21 +2,27 @@ package order_book_types
 
 import (
 	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
 	"time"
 	"vitalik_backend/internal/pkg/types"
 )
 
 type CreateOrderArgs struct {
 	Type types.OrderType
+	Kind types.OrderKind
+
+	TimeInForce types.TimeInForce
+	ExpiresAt   null.Time
 
 	SellCurrency   types.Currency
-	SellQuantity   null.Float
+	SellQuantity   decimal.NullDecimal
 	SellRequisites types.Requisites
 
-	Price float64
+	Price        decimal.Decimal
+	TriggerPrice decimal.NullDecimal
 
 	BuyCurrency   types.Currency
-	BuyQuantity   null.Float
+	BuyQuantity   decimal.NullDecimal
 	BuyRequisites types.Requisites
 
 	CreatedAt time.Time
@@ -30,3 +36,21 @@ type ListOrdersArgs struct {
 	OrderType   null.Value[types.OrderType]
 	OrderStatus null.Value[types.OrderStatus]
 }
+
+// GetDepthArgs selects the depth of a currency pair. Limit caps the number of
+// price levels per side; Tick, when set, groups prices into buckets of that size.
+type GetDepthArgs struct {
+	CurrencyPair types.CurrencyPair
+
+	Limit int
+	Tick  decimal.NullDecimal
+}
+
+// TradeListener is called with every settled trade once the book of the trade is
+// unlocked. Listeners are called by one goroutine at a time in the order of the
+// trades, so a listener should return quickly.
+type TradeListener func(trade types.Trade)
+
+// OrderListener is called with every persisted change of an order under the same
+// rules as TradeListener.
+type OrderListener func(order types.Order)
@@ -8,29 +8,73 @@ import (
 	"github.com/guregu/null/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"slices"
 	"sort"
+	"sync"
 	"time"
 	"vitalik_backend/internal/dependencies"
 	"vitalik_backend/internal/pkg/services/order_book"
 	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	"vitalik_backend/internal/pkg/services/store"
 	store_types "vitalik_backend/internal/pkg/services/store/types"
 	"vitalik_backend/internal/pkg/types"
 )
 
+var (
+	// ErrOrderBookEmpty is returned when a market order has nothing to match against.
+	ErrOrderBookEmpty = errors.New("order book is empty")
+	// ErrOrderWouldTrigger is returned when the last price already crosses the trigger price of a new order.
+	ErrOrderWouldTrigger = errors.New("order would trigger immediately")
+	// ErrMarketHalted is returned when an order is placed on a halted currency pair.
+	ErrMarketHalted = errors.New("market is halted")
+	// ErrOrderNotCancellable is returned when an order is already closed, cancelled or expired.
+	ErrOrderNotCancellable = errors.New("order can no longer be cancelled")
+)
+
 // OrderBookManager manages multiple order books in memory.
+//
+// Orders are matched as soon as CreateOrder accepts them, MatchOrders only sweeps
+// the books as a safety net.
+//
+// The orderBooks map is guarded by mu, while every book is guarded by its own
+// mutex, so mutations of one currency pair are serialised and different pairs
+// can be matched in parallel.
+//
+// Trades and orders are queued for the listeners under the book lock and passed
+// to them once it is released, one goroutine at a time in the order they were
+// queued, so a slow listener never holds up a book.
 type OrderBookManager struct {
-	store dependencies.IStore
+	store  dependencies.IStore
+	logger *zap.Logger
 
+	mu         sync.RWMutex
 	orderBooks map[string]*order_book.OrderBook
+
+	listenersMu    sync.RWMutex
+	tradeListeners []order_book_types.TradeListener
+	orderListeners []order_book_types.OrderListener
+
+	notificationsMu sync.Mutex
+	notifications   []notification
+	notifying       bool
+}
+
+// notification is a trade or an order queued for the listeners.
+type notification struct {
+	trade *types.Trade
+	order *types.Order
 }
 
-func NewOrderBookManager(store dependencies.IStore) (*OrderBookManager, error) {
-	if store == nil {
+func NewOrderBookManager(store dependencies.IStore, logger *zap.Logger) (*OrderBookManager, error) {
+	if store == nil || logger == nil {
 		return nil, errors.New("failed to initialize order_book_manager")
 	}
 
 	return &OrderBookManager{
 		store:      store,
+		logger:     logger,
 		orderBooks: make(map[string]*order_book.OrderBook),
 	}, nil
 }
@@ -38,13 +82,91 @@ func NewOrderBookManager(store dependencies.IStore) (*OrderBookManager, error) {
 var _ dependencies.IOrderBookManager = (*OrderBookManager)(nil)
 
 func (m *OrderBookManager) ListAvailableCurrencyPairs(ctx context.Context) ([]types.CurrencyPair, error) {
-	pairs := lo.Map(lo.Values(m.orderBooks), func(book *order_book.OrderBook, _ int) types.CurrencyPair {
+	pairs := lo.Map(m.listOrderBooks(), func(book *order_book.OrderBook, _ int) types.CurrencyPair {
 		return book.CurrencyPair
 	})
 
 	return pairs, nil
 }
 
+// SubscribeTrades registers a listener called with every trade settled from now on.
+func (m *OrderBookManager) SubscribeTrades(listener order_book_types.TradeListener) {
+	m.listenersMu.Lock()
+	defer m.listenersMu.Unlock()
+
+	m.tradeListeners = append(m.tradeListeners, listener)
+}
+
+// SubscribeOrders registers a listener called with every order persisted from now
+// on: placed, triggered, filled, cancelled or expired.
+func (m *OrderBookManager) SubscribeOrders(listener order_book_types.OrderListener) {
+	m.listenersMu.Lock()
+	defer m.listenersMu.Unlock()
+
+	m.orderListeners = append(m.orderListeners, listener)
+}
+
+// LoadOrderBooks restores the halted markets and the open and pending orders
+// persisted in the store into their order books.
+func (m *OrderBookManager) LoadOrderBooks(ctx context.Context) error {
+	defer m.flushNotifications()
+
+	haltedPairs, err := m.store.ListHaltedMarkets(ctx)
+	if err != nil {
+		return fmt.Errorf("store.ListHaltedMarkets failed: %w", err)
+	}
+
+	for _, pair := range haltedPairs {
+		orderBook, err := m.getOrCreateOrderBook(ctx, pair)
+		if err != nil {
+			return fmt.Errorf("getOrCreateOrderBook failed: %w", err)
+		}
+
+		orderBook.Lock()
+		orderBook.Halted = true
+		orderBook.Unlock()
+	}
+
+	orders, err := m.store.ListOrders(ctx, store_types.ListOrdersArgs{
+		StatusIn: []types.OrderStatus{types.OrderOpen, types.OrderPartiallyFilled, types.OrderPending},
+	})
+	if err != nil {
+		return fmt.Errorf("store.ListOrders failed: %w", err)
+	}
+
+	for _, order := range orders {
+		// Market, IOC and FOK orders never rest on the book, an open one was interrupted mid-sweep.
+		if order.Status.IsOpen() && !restsOnBook(order) {
+			if _, err = m.cancelOrder(ctx, *order); err != nil {
+				return fmt.Errorf("cancelOrder failed: %w", err)
+			}
+			continue
+		}
+
+		orderBook, err := m.getOrCreateOrderBook(ctx, types.CurrencyPair{
+			Currency1: order.BuyCurrency,
+			Currency2: order.SellCurrency,
+		})
+		if err != nil {
+			return fmt.Errorf("getOrCreateOrderBook failed: %w", err)
+		}
+
+		orderBook.Lock()
+		if order.Status == types.OrderPending {
+			orderBook.PendingOrders = append(orderBook.PendingOrders, order)
+		} else {
+			err = m.createOrder(orderBook, order)
+		}
+		orderBook.Unlock()
+
+		if err != nil {
+			return fmt.Errorf("createOrder failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (m *OrderBookManager) CreateOrder(ctx context.Context, args order_book_types.CreateOrderArgs) (*types.Order, error) {
 	orderBook, err := m.getOrCreateOrderBook(ctx, types.CurrencyPair{
 		Currency1: args.BuyCurrency,
@@ -63,11 +185,47 @@ func (m *OrderBookManager) CreateOrder(ctx context.Context, args order_book_type
 		return nil, err
 	}
 
-	if err = m.createOrder(orderBook, order); err != nil {
-		return nil, fmt.Errorf("createOrder failed: %w", err)
+	defer m.flushNotifications()
+	orderBook.Lock()
+	defer orderBook.Unlock()
+
+	if orderBook.Halted {
+		return nil, ErrMarketHalted
 	}
 
-	return order, nil
+	if order.Kind == types.Market && !lo.SomeBy(counterOrders(orderBook, order), isOpenOrder) {
+		return nil, ErrOrderBookEmpty
+	}
+
+	if order.Status == types.OrderPending && isTriggered(order, orderBook.LastPrice) {
+		return nil, ErrOrderWouldTrigger
+	}
+
+	placeOrderArgs := store_types.PlaceOrderArgs{
+		Order: *order,
+		Hold:  bindOrderHold(order),
+	}
+
+	if err = m.store.PlaceOrder(ctx, placeOrderArgs); err != nil {
+		return nil, fmt.Errorf("store.PlaceOrder failed: %w", err)
+	}
+	m.notifyOrder(*order)
+
+	if order.Status == types.OrderPending {
+		orderBook.PendingOrders = append(orderBook.PendingOrders, order)
+	} else if err = m.placeOrder(ctx, orderBook, order); err != nil {
+		return nil, err
+	}
+
+	if len(order.Fills) > 0 {
+		if err = m.triggerOrders(ctx, orderBook); err != nil {
+			m.logger.Error("triggerOrders failed", zap.String("pair", orderBook.CurrencyPair.String()), zap.Error(err))
+		}
+	}
+
+	orderCopy := *order
+
+	return &orderCopy, nil
 }
 
 func (m *OrderBookManager) CancelOrder(
@@ -80,18 +238,39 @@ func (m *OrderBookManager) CancelOrder(
 		return fmt.Errorf("getOrCreateOrderBook failed: %w", err)
 	}
 
+	defer m.flushNotifications()
+	orderBook.Lock()
+	defer orderBook.Unlock()
+
 	for i, buyOrder := range orderBook.BuyOrders {
 		if buyOrder.ID == orderID {
-			buyOrder.Status = types.OrderCancelled
-			orderBook.BuyOrders[i] = buyOrder
+			cancelledOrder, err := m.cancelOrder(ctx, *buyOrder)
+			if err != nil {
+				return err
+			}
+			orderBook.BuyOrders[i] = cancelledOrder
 			return nil
 		}
 	}
 
 	for i, sellOrder := range orderBook.SellOrders {
 		if sellOrder.ID == orderID {
-			sellOrder.Status = types.OrderCancelled
-			orderBook.SellOrders[i] = sellOrder
+			cancelledOrder, err := m.cancelOrder(ctx, *sellOrder)
+			if err != nil {
+				return err
+			}
+			orderBook.SellOrders[i] = cancelledOrder
+			return nil
+		}
+	}
+
+	for i, pendingOrder := range orderBook.PendingOrders {
+		if pendingOrder.ID == orderID {
+			cancelledOrder, err := m.cancelOrder(ctx, *pendingOrder)
+			if err != nil {
+				return err
+			}
+			orderBook.PendingOrders[i] = cancelledOrder
 			return nil
 		}
 	}
@@ -99,14 +278,69 @@ func (m *OrderBookManager) CancelOrder(
 	return echo.ErrNotFound
 }
 
+// GetOrder returns a copy of an order in the order book of the currency pair, or
+// echo.ErrNotFound when the book has no such order.
+func (m *OrderBookManager) GetOrder(
+	ctx context.Context,
+	currencyPair types.CurrencyPair,
+	orderID uuid.UUID,
+) (*types.Order, error) {
+	orderBook, err := m.getOrCreateOrderBook(ctx, currencyPair)
+	if err != nil {
+		return nil, fmt.Errorf("getOrCreateOrderBook failed: %w", err)
+	}
+
+	orderBook.Lock()
+	defer orderBook.Unlock()
+
+	for _, orders := range [][]*types.Order{orderBook.BuyOrders, orderBook.SellOrders, orderBook.PendingOrders} {
+		for _, order := range orders {
+			if order.ID == orderID {
+				orderCopy := *order
+				return &orderCopy, nil
+			}
+		}
+	}
+
+	return nil, echo.ErrNotFound
+}
+
+// cancelOrder cancels an open or pending order. Finished orders are left as they
+// are, so their history is not rewritten.
+func (m *OrderBookManager) cancelOrder(ctx context.Context, order types.Order) (*types.Order, error) {
+	if !order.Status.IsOpen() && order.Status != types.OrderPending {
+		return nil, ErrOrderNotCancellable
+	}
+
+	now := time.Now()
+
+	order.Status = types.OrderCancelled
+	order.UpdatedAt = now
+	order.RemovedAt = null.TimeFrom(now)
+
+	if err := m.store.CancelOrder(ctx, order); err != nil {
+		return nil, fmt.Errorf("store.CancelOrder failed: %w", err)
+	}
+	m.notifyOrder(order)
+
+	return &order, nil
+}
+
 func (m *OrderBookManager) ListOrders(ctx context.Context, args order_book_types.ListOrdersArgs) ([]*types.Order, error) {
 	orderBook, err := m.getOrCreateOrderBook(ctx, args.CurrencyPair)
 	if err != nil {
 		return nil, fmt.Errorf("getOrderBook failed: %w", err)
 	}
 
-	allOrders := append([]*types.Order{}, orderBook.SellOrders...)
-	allOrders = append(allOrders, orderBook.BuyOrders...)
+	orderBook.Lock()
+	allOrders := make([]*types.Order, 0, len(orderBook.SellOrders)+len(orderBook.BuyOrders)+len(orderBook.PendingOrders))
+	for _, orders := range [][]*types.Order{orderBook.SellOrders, orderBook.BuyOrders, orderBook.PendingOrders} {
+		for _, order := range orders {
+			orderCopy := *order
+			allOrders = append(allOrders, &orderCopy)
+		}
+	}
+	orderBook.Unlock()
 
 	return lo.Filter(allOrders, func(order *types.Order, _ int) bool {
 		if len(args.UserIDIn) > 0 &&
@@ -129,17 +363,141 @@ func (m *OrderBookManager) ListOrders(ctx context.Context, args order_book_types
 	}), nil
 }
 
+// GetDepth aggregates the open orders of the book into price levels. Only prices,
+// quantities and order counts leave the book, never the orders themselves.
+func (m *OrderBookManager) GetDepth(ctx context.Context, args order_book_types.GetDepthArgs) (*types.Depth, error) {
+	orderBook, err := m.getOrCreateOrderBook(ctx, args.CurrencyPair)
+	if err != nil {
+		return nil, fmt.Errorf("getOrderBook failed: %w", err)
+	}
+
+	orderBook.Lock()
+	defer orderBook.Unlock()
+
+	return &types.Depth{
+		CurrencyPair: orderBook.CurrencyPair,
+		Bids:         aggregateDepth(orderBook.BuyOrders, args.Limit, args.Tick, false),
+		Asks:         aggregateDepth(orderBook.SellOrders, args.Limit, args.Tick, true),
+	}, nil
+}
+
 func (m *OrderBookManager) MatchOrders(ctx context.Context) error {
-	for key, book := range m.orderBooks {
-		if err := m.matchOrders(ctx, book); err != nil {
-			return fmt.Errorf("MatchOrders failed: %w", err)
+	books := m.listOrderBooks()
+	errs := make([]error, len(books))
+
+	var wg sync.WaitGroup
+	for i, book := range books {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			book.Lock()
+			defer book.Unlock()
+
+			if book.Halted {
+				return
+			}
+
+			if err := m.matchOrders(ctx, book); err != nil {
+				errs[i] = fmt.Errorf("MatchOrders failed for %s: %w", book.CurrencyPair.String(), err)
+				return
+			}
+
+			if err := m.triggerOrders(ctx, book); err != nil {
+				errs[i] = fmt.Errorf("triggerOrders failed for %s: %w", book.CurrencyPair.String(), err)
+			}
+		}()
+	}
+	wg.Wait()
+	m.flushNotifications()
+
+	return errors.Join(errs...)
+}
+
+// SetMarketHalted halts or resumes trading of a currency pair. The halt is saved
+// in the store, so it survives a restart.
+func (m *OrderBookManager) SetMarketHalted(ctx context.Context, currencyPair types.CurrencyPair, halted bool) error {
+	orderBook, err := m.getOrCreateOrderBook(ctx, currencyPair)
+	if err != nil {
+		return fmt.Errorf("getOrCreateOrderBook failed: %w", err)
+	}
+
+	orderBook.Lock()
+	defer orderBook.Unlock()
+
+	err = m.store.SetMarketHalted(ctx, store_types.SetMarketHaltedArgs{
+		CurrencyPair: orderBook.CurrencyPair,
+		Halted:       halted,
+		At:           time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("store.SetMarketHalted failed: %w", err)
+	}
+
+	orderBook.Halted = halted
+
+	return nil
+}
+
+// ExpireOrders takes GTD orders past their expiry time off the books.
+func (m *OrderBookManager) ExpireOrders(ctx context.Context) error {
+	books := m.listOrderBooks()
+	errs := make([]error, len(books))
+
+	var wg sync.WaitGroup
+	for i, book := range books {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			book.Lock()
+			defer book.Unlock()
+
+			if err := m.expireOrders(ctx, book); err != nil {
+				errs[i] = fmt.Errorf("ExpireOrders failed for %s: %w", book.CurrencyPair.String(), err)
+			}
+		}()
+	}
+	wg.Wait()
+	m.flushNotifications()
+
+	return errors.Join(errs...)
+}
+
+// expireOrders expires the orders of the book past their expiry time. The caller must hold the book lock.
+func (m *OrderBookManager) expireOrders(ctx context.Context, orderBook *order_book.OrderBook) error {
+	now := time.Now()
+
+	for _, orders := range [][]*types.Order{orderBook.BuyOrders, orderBook.SellOrders, orderBook.PendingOrders} {
+		for i, order := range orders {
+			if (!order.Status.IsOpen() && order.Status != types.OrderPending) || !order.IsExpired(now) {
+				continue
+			}
+
+			expiredOrder := *order
+			expiredOrder.Status = types.OrderExpired
+			expiredOrder.UpdatedAt = now
+			expiredOrder.ExpiredAt = null.TimeFrom(now)
+
+			if err := m.store.CancelOrder(ctx, expiredOrder); err != nil {
+				return fmt.Errorf("store.CancelOrder failed: %w", err)
+			}
+			m.notifyOrder(expiredOrder)
+
+			orders[i] = &expiredOrder
 		}
-		m.orderBooks[key] = book
 	}
 
 	return nil
 }
 
+func (m *OrderBookManager) listOrderBooks() []*order_book.OrderBook {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return lo.Values(m.orderBooks)
+}
+
 func (m *OrderBookManager) validateOrder(ctx context.Context, order *types.Order) error {
 	wallets, err := m.getOrderWallets(ctx, order)
 	if err != nil {
@@ -190,7 +548,7 @@ func (m *OrderBookManager) validateSellWallet(order *types.Order, wallets []*typ
 			order.SellCurrency,
 		)
 	}
-	if sellRequisites.Balance < order.SellQuantity.ValueOrZero() {
+	if sellRequisites.Available.LessThan(bindOrderHold(order).Amount) {
 		return fmt.Errorf("insufficient balance for sell wallet: %v", sellRequisites.Requisites.Address)
 	}
 	return nil
@@ -218,6 +576,7 @@ func findWallet(wallets []*types.Wallet, Address string) (*types.Wallet, bool) {
 	})
 }
 
+// createOrder places the order into the book. The caller must hold the book lock.
 func (m *OrderBookManager) createOrder(orderBook *order_book.OrderBook, order *types.Order) error {
 	if order.Type == types.Sell {
 		orderBook.SellOrders = append(orderBook.SellOrders, order)
@@ -232,46 +591,409 @@ func (m *OrderBookManager) createOrder(orderBook *order_book.OrderBook, order *t
 	return fmt.Errorf("invalid order type: %v", order.Type)
 }
 
+// triggerOrders activates pending orders whose trigger price the last trade price
+// has crossed, until none is left to trigger. The caller must hold the book lock.
+func (m *OrderBookManager) triggerOrders(ctx context.Context, orderBook *order_book.OrderBook) error {
+	for {
+		i := slices.IndexFunc(orderBook.PendingOrders, func(order *types.Order) bool {
+			return order.Status == types.OrderPending && isTriggered(order, orderBook.LastPrice)
+		})
+		if i < 0 {
+			return nil
+		}
+
+		now := time.Now()
+
+		order := *orderBook.PendingOrders[i]
+		order.Kind = lo.Ternary(order.Kind == types.StopLimit, types.Limit, types.Market)
+		order.Status = types.OrderOpen
+		order.TriggeredAt = null.TimeFrom(now)
+		order.UpdatedAt = now
+
+		if err := m.store.SaveOrder(ctx, order); err != nil {
+			return fmt.Errorf("store.SaveOrder failed: %w", err)
+		}
+		m.notifyOrder(order)
+
+		orderBook.PendingOrders = slices.Delete(orderBook.PendingOrders, i, i+1)
+
+		if err := m.placeOrder(ctx, orderBook, &order); err != nil {
+			return err
+		}
+	}
+}
+
+// isTriggered reports whether the last price has crossed the trigger price of a
+// pending order. Stop buys and take-profit sells trigger on a rise, stop sells
+// and take-profit buys on a fall.
+func isTriggered(order *types.Order, lastPrice decimal.NullDecimal) bool {
+	if !lastPrice.Valid {
+		return false
+	}
+
+	isStop := order.Kind == types.StopMarket || order.Kind == types.StopLimit
+	if isStop == (order.Type == types.Buy) {
+		return lastPrice.Decimal.GreaterThanOrEqual(order.TriggerPrice.Decimal)
+	}
+	return lastPrice.Decimal.LessThanOrEqual(order.TriggerPrice.Decimal)
+}
+
+// placeOrder matches an open order against the opposite side of the book right
+// away. What is left rests on the book for GTC and GTD limit orders and is
+// cancelled otherwise, as well as when a settlement fails. The caller must hold the book lock.
+func (m *OrderBookManager) placeOrder(ctx context.Context, orderBook *order_book.OrderBook, order *types.Order) error {
+	var sweepErr error
+	if order.TimeInForce != types.FOK || canFillCompletely(orderBook, order) {
+		sweepErr = m.sweepOrderBook(ctx, orderBook, order)
+	}
+
+	if !order.Status.IsOpen() {
+		return sweepErr
+	}
+
+	if sweepErr == nil && restsOnBook(order) {
+		restingOrder := *order
+		restingOrder.Fills = nil
+
+		if err := m.createOrder(orderBook, &restingOrder); err != nil {
+			return fmt.Errorf("createOrder failed: %w", err)
+		}
+		return nil
+	}
+
+	cancelledOrder, err := m.cancelOrder(ctx, *order)
+	if err != nil {
+		return errors.Join(sweepErr, err)
+	}
+	cancelledOrder.Fills = order.Fills
+	*order = *cancelledOrder
+
+	return sweepErr
+}
+
+// sweepOrderBook fills the order against crossing counter orders from the best price on.
+func (m *OrderBookManager) sweepOrderBook(ctx context.Context, orderBook *order_book.OrderBook, order *types.Order) error {
+	counters := counterOrders(orderBook, order)
+
+	for i := 0; i < len(counters) && order.Status.IsOpen(); i++ {
+		takerOrder := *order
+		counterOrder := *counters[i]
+
+		if !isOpenOrder(&counterOrder) {
+			continue
+		}
+		if !crosses(&takerOrder, &counterOrder) {
+			break
+		}
+
+		baseQuantity, quoteQuantity := fillQuantities(&takerOrder, &counterOrder)
+		if !baseQuantity.IsPositive() || !quoteQuantity.IsPositive() {
+			break
+		}
+
+		now := time.Now()
+		applyFill(&takerOrder, baseQuantity, quoteQuantity, now)
+		applyFill(&counterOrder, baseQuantity, quoteQuantity, now)
+		closeSpentMarketBuy(&takerOrder, counterOrder.Price, now)
+
+		buyOrder, sellOrder := &counterOrder, &takerOrder
+		if takerOrder.Type == types.Buy {
+			buyOrder, sellOrder = &takerOrder, &counterOrder
+		}
+
+		trade, err := m.settleTrade(ctx, orderBook, *buyOrder, *sellOrder, counterOrder.Type, counterOrder.Price, baseQuantity, quoteQuantity)
+		if err != nil {
+			return err
+		}
+
+		takerOrder.Fills = append(takerOrder.Fills, types.Fill{
+			TradeID:        trade.ID,
+			CounterOrderID: counterOrder.ID,
+			Price:          counterOrder.Price,
+			BaseQuantity:   baseQuantity,
+			QuoteQuantity:  quoteQuantity,
+			CreatedAt:      now,
+		})
+
+		*order = takerOrder
+		counters[i] = &counterOrder
+	}
+
+	return nil
+}
+
+// canFillCompletely reports whether the crossing counter orders have enough
+// liquidity to fill the whole order. A market buy is complete once the budget
+// left is dust, as in sweepOrderBook.
+func canFillCompletely(orderBook *order_book.OrderBook, order *types.Order) bool {
+	takerOrder := *order
+
+	for _, counterOrder := range counterOrders(orderBook, order) {
+		if !isOpenOrder(counterOrder) {
+			continue
+		}
+		if !crosses(&takerOrder, counterOrder) {
+			break
+		}
+
+		baseQuantity, quoteQuantity := fillQuantities(&takerOrder, counterOrder)
+		if !baseQuantity.IsPositive() || !quoteQuantity.IsPositive() {
+			break
+		}
+
+		now := time.Now()
+		applyFill(&takerOrder, baseQuantity, quoteQuantity, now)
+		closeSpentMarketBuy(&takerOrder, counterOrder.Price, now)
+		if takerOrder.Status == types.OrderClosed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// counterOrders returns the side of the book the order trades against, best price first.
+func counterOrders(orderBook *order_book.OrderBook, order *types.Order) []*types.Order {
+	if order.Type == types.Buy {
+		return orderBook.SellOrders
+	}
+	return orderBook.BuyOrders
+}
+
+// crosses reports whether the taker order accepts the price of the counter order.
+func crosses(takerOrder, counterOrder *types.Order) bool {
+	switch {
+	case takerOrder.Kind == types.Market:
+		return true
+	case takerOrder.Type == types.Buy:
+		return counterOrder.Price.LessThanOrEqual(takerOrder.Price)
+	default:
+		return counterOrder.Price.GreaterThanOrEqual(takerOrder.Price)
+	}
+}
+
+// fillQuantities returns the base and quote quantities the taker order can trade
+// with the counter order at the counter order price.
+func fillQuantities(takerOrder, counterOrder *types.Order) (decimal.Decimal, decimal.Decimal) {
+	var baseQuantity decimal.Decimal
+	if takerOrder.Kind == types.Market && takerOrder.Type == types.Buy {
+		affordable, _ := affordableQuantities(takerOrder, counterOrder.Price)
+		baseQuantity = decimal.Min(counterOrder.RemainingQuantity, affordable)
+	} else {
+		baseQuantity = decimal.Min(takerOrder.RemainingQuantity, counterOrder.RemainingQuantity)
+	}
+
+	quoteCurrency := quoteCurrency(takerOrder)
+
+	return baseQuantity, quoteCurrency.Quantize(baseQuantity.Mul(counterOrder.Price))
+}
+
+// affordableQuantities returns the base quantity the remaining budget of a market
+// buy buys at the price, in whole base ticks, and the quote quantity it costs.
+func affordableQuantities(order *types.Order, price decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	baseQuantity, _ := order.RemainingQuantity.QuoRem(price, order.BuyCurrency.Precision())
+	return baseQuantity, order.SellCurrency.Quantize(baseQuantity.Mul(price))
+}
+
+// closeSpentMarketBuy closes a market buy whose remaining budget is dust: it can
+// not buy a base tick for a positive quote amount at the price. Later counter
+// orders are no cheaper, so the order is as filled as it can get.
+func closeSpentMarketBuy(order *types.Order, price decimal.Decimal, now time.Time) {
+	if order.Kind != types.Market || order.Type != types.Buy || !order.Status.IsOpen() {
+		return
+	}
+
+	baseQuantity, quoteQuantity := affordableQuantities(order, price)
+	if baseQuantity.IsPositive() && quoteQuantity.IsPositive() {
+		return
+	}
+
+	order.Status = types.OrderClosed
+	order.ClosedAt = null.TimeFrom(now)
+}
+
+// applyFill records a fill on the order: it adds to the filled quantities, updates
+// the average price and subtracts from the remaining quantity, which is the quote
+// budget of a market buy and the base quantity otherwise. The order is closed once
+// nothing is left to fill and partially filled until then.
+func applyFill(order *types.Order, baseQuantity, quoteQuantity decimal.Decimal, now time.Time) {
+	order.FilledQuantity = order.FilledQuantity.Add(baseQuantity)
+	order.FilledQuoteQuantity = order.FilledQuoteQuantity.Add(quoteQuantity)
+
+	quoteCurrency := quoteCurrency(order)
+	order.AveragePrice = decimal.NewNullDecimal(order.FilledQuoteQuantity.DivRound(order.FilledQuantity, quoteCurrency.Precision()))
+
+	if order.Kind == types.Market && order.Type == types.Buy {
+		order.RemainingQuantity = order.RemainingQuantity.Sub(quoteQuantity)
+	} else {
+		order.RemainingQuantity = order.RemainingQuantity.Sub(baseQuantity)
+	}
+
+	if order.RemainingQuantity.IsPositive() {
+		order.Status = types.OrderPartiallyFilled
+	} else {
+		order.Status = types.OrderClosed
+		order.ClosedAt = null.TimeFrom(now)
+	}
+	order.UpdatedAt = now
+}
+
+// sizedQuantity returns the quantity the order is sized in: the base quantity of
+// a limit buy, the sell quantity otherwise.
+func sizedQuantity(order *types.Order) decimal.Decimal {
+	if !order.Kind.IsMarket() && order.Type == types.Buy {
+		return order.BuyQuantity.Decimal
+	}
+	return order.SellQuantity.Decimal
+}
+
+func quoteCurrency(order *types.Order) types.Currency {
+	if order.Type == types.Buy {
+		return order.SellCurrency
+	}
+	return order.BuyCurrency
+}
+
+// isOpenOrder reports whether the order can still be matched.
+func isOpenOrder(order *types.Order) bool {
+	return order.Status.IsOpen() && !order.IsExpired(time.Now())
+}
+
+// restsOnBook reports whether the unfilled part of the order stays on the book.
+func restsOnBook(order *types.Order) bool {
+	return order.Kind == types.Limit && (order.TimeInForce == types.GTC || order.TimeInForce == types.GTD)
+}
+
+// aggregateDepth sums the remaining quantities of open orders, sorted best price
+// first, into at most limit price levels, or all of them when limit is 0. With a
+// tick, bids are grouped down and asks up to a multiple of it, so a level never
+// looks better than the orders in it.
+func aggregateDepth(orders []*types.Order, limit int, tick decimal.NullDecimal, roundUp bool) []types.DepthLevel {
+	levels := make([]types.DepthLevel, 0)
+
+	for _, order := range orders {
+		if !isOpenOrder(order) || !order.RemainingQuantity.IsPositive() {
+			continue
+		}
+
+		price := order.Price
+		if tick.Valid {
+			price = roundToTick(price, tick.Decimal, roundUp)
+		}
+
+		if n := len(levels); n > 0 && levels[n-1].Price.Equal(price) {
+			levels[n-1].Quantity = levels[n-1].Quantity.Add(order.RemainingQuantity)
+			levels[n-1].OrderCount++
+			continue
+		}
+
+		if limit > 0 && len(levels) == limit {
+			break
+		}
+
+		levels = append(levels, types.DepthLevel{
+			Price:      price,
+			Quantity:   order.RemainingQuantity,
+			OrderCount: 1,
+		})
+	}
+
+	return levels
+}
+
+// roundToTick rounds the price down, or up when roundUp is set, to a multiple of the tick.
+func roundToTick(price, tick decimal.Decimal, roundUp bool) decimal.Decimal {
+	quotient, remainder := price.QuoRem(tick, 0)
+
+	rounded := quotient.Mul(tick)
+	if roundUp && !remainder.IsZero() {
+		rounded = rounded.Add(tick)
+	}
+
+	return rounded
+}
+
 func sortOrderBook(orderBook *order_book.OrderBook) {
 	sort.Slice(orderBook.SellOrders, func(i, j int) bool {
 		o1 := orderBook.SellOrders[i]
 		o2 := orderBook.SellOrders[j]
 
-		if o1.Price == o2.Price {
+		if o1.Price.Equal(o2.Price) {
 			return o1.CreatedAt.Before(o2.CreatedAt)
 		}
-		return o1.Price < o2.Price
+		return o1.Price.LessThan(o2.Price)
 	})
 
 	sort.Slice(orderBook.BuyOrders, func(i, j int) bool {
 		o1 := orderBook.BuyOrders[i]
 		o2 := orderBook.BuyOrders[j]
 
-		if o1.Price == o2.Price {
+		if o1.Price.Equal(o2.Price) {
 			return o1.CreatedAt.Before(o2.CreatedAt)
 		}
-		return o1.Price > o2.Price
+		return o1.Price.GreaterThan(o2.Price)
 	})
 }
 
 func bindOrderFromArgs(args order_book_types.CreateOrderArgs) (*types.Order, error) {
-	return &types.Order{
+	kind := lo.Ternary(args.Kind == "", types.Limit, args.Kind)
+
+	timeInForce := args.TimeInForce
+	if timeInForce == "" {
+		timeInForce = lo.Ternary(kind.IsMarket(), types.IOC, types.GTC)
+	}
+
+	order := &types.Order{
 		ID:             uuid.Must(uuid.NewV7()),
 		Type:           args.Type,
+		Kind:           kind,
+		TimeInForce:    timeInForce,
+		ExpiresAt:      args.ExpiresAt,
 		SellCurrency:   args.SellCurrency,
 		SellQuantity:   args.SellQuantity,
 		SellRequisites: args.SellRequisites,
 		Price:          args.Price,
+		TriggerPrice:   args.TriggerPrice,
 		BuyCurrency:    args.BuyCurrency,
 		BuyQuantity:    args.BuyQuantity,
 		BuyRequisites:  args.BuyRequisites,
-		Status:         types.OrderOpen,
+		Status:         lo.Ternary(kind.IsTrigger(), types.OrderPending, types.OrderOpen),
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
-	}, nil
+	}
+
+	order.OriginalQuantity = sizedQuantity(order)
+	order.RemainingQuantity = order.OriginalQuantity
+
+	return order, nil
+}
+
+// bindOrderHold returns the hold on the sell wallet needed to cover the order:
+// the quantity at the limit price for limit buy orders, the sell quantity otherwise.
+func bindOrderHold(order *types.Order) types.Hold {
+	amount := order.SellQuantity.Decimal
+	if !order.Kind.IsMarket() && order.Type == types.Buy {
+		amount = order.SellCurrency.Quantize(order.BuyQuantity.Decimal.Mul(order.Price))
+	}
+
+	return types.Hold{
+		OrderID:   order.ID,
+		Address:   order.SellRequisites.Address,
+		Currency:  order.SellCurrency,
+		Amount:    amount,
+		CreatedAt: order.CreatedAt,
+		UpdatedAt: order.UpdatedAt,
+	}
 }
 
 func (m *OrderBookManager) getOrCreateOrderBook(ctx context.Context, currencyPair types.CurrencyPair) (*order_book.OrderBook, error) {
+	if orderBook, err := m.getOrderBook(ctx, currencyPair); err == nil {
+		return orderBook, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if lo.HasKey(m.orderBooks, currencyPair.String()) {
 		return m.orderBooks[currencyPair.String()], nil
 	} else if lo.HasKey(m.orderBooks, currencyPair.StringReverse()) {
@@ -289,6 +1011,9 @@ func (m *OrderBookManager) getOrCreateOrderBook(ctx context.Context, currencyPai
 }
 
 func (m *OrderBookManager) getOrderBook(ctx context.Context, currencyPair types.CurrencyPair) (*order_book.OrderBook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	if lo.HasKey(m.orderBooks, currencyPair.String()) {
 		return m.orderBooks[currencyPair.String()], nil
 	} else if lo.HasKey(m.orderBooks, currencyPair.StringReverse()) {
@@ -298,6 +1023,7 @@ func (m *OrderBookManager) getOrderBook(ctx context.Context, currencyPair types.
 	return nil, echo.ErrNotFound
 }
 
+// matchOrders crosses buy and sell orders of the book. The caller must hold the book lock.
 func (m *OrderBookManager) matchOrders(ctx context.Context, orderBook *order_book.OrderBook) error {
 	i := 0
 	j := 0
@@ -306,59 +1032,29 @@ func (m *OrderBookManager) matchOrders(ctx context.Context, orderBook *order_boo
 		buyOrder := *orderBook.BuyOrders[i]
 		sellOrder := *orderBook.SellOrders[j]
 
-		if buyOrder.Status != types.OrderOpen {
+		if !isOpenOrder(&buyOrder) {
 			i++
 			continue
-		} else if sellOrder.Status != types.OrderOpen {
+		} else if !isOpenOrder(&sellOrder) {
 			j++
 			continue
 		}
 
-		if buyOrder.Price >= sellOrder.Price {
-			sellQuantity := min(buyOrder.BuyQuantity.ValueOrZero(), sellOrder.SellQuantity.ValueOrZero())
-
-			transferArgs := store_types.TransferArgs{
-				FromAddress: sellOrder.SellRequisites.Address,
-				ToAddress:   buyOrder.BuyRequisites.Address,
-				Amount:      sellQuantity,
-				Currency:    sellOrder.SellCurrency,
-				Purpose:     null.StringFrom(fmt.Sprintf("Trading %v for %v", sellOrder.SellCurrency, sellOrder.BuyCurrency)),
-			}
+		if buyOrder.Price.GreaterThanOrEqual(sellOrder.Price) {
+			// The order placed first is the maker, the trade executes at its price.
+			makerSide := lo.Ternary(buyOrder.CreatedAt.Before(sellOrder.CreatedAt), types.Buy, types.Sell)
+			price := lo.Ternary(makerSide == types.Buy, buyOrder.Price, sellOrder.Price)
 
-			_, err := m.store.Transfer(ctx, transferArgs)
-			if err != nil {
-				return fmt.Errorf("storefx.Transfer failed: %w", err)
-			}
+			sellQuantity := decimal.Min(buyOrder.RemainingQuantity, sellOrder.RemainingQuantity)
 
-			buyQuantity := sellQuantity * sellOrder.Price
+			buyQuantity := sellOrder.BuyCurrency.Quantize(sellQuantity.Mul(price))
 
-			transferArgs = store_types.TransferArgs{
-				FromAddress: buyOrder.SellRequisites.Address,
-				ToAddress:   sellOrder.BuyRequisites.Address,
-				Amount:      buyQuantity,
-				Currency:    sellOrder.BuyCurrency,
-				Purpose:     null.StringFrom(fmt.Sprintf("Trading %v for %v", sellOrder.SellCurrency, sellOrder.BuyCurrency)),
-			}
+			now := time.Now()
+			applyFill(&buyOrder, sellQuantity, buyQuantity, now)
+			applyFill(&sellOrder, sellQuantity, buyQuantity, now)
 
-			_, err = m.store.Transfer(ctx, transferArgs)
-			if err != nil {
-				return fmt.Errorf("storefx.Transfer failed: %w", err)
-			}
-
-			buyOrder.BuyQuantity = null.FloatFrom(buyOrder.BuyQuantity.ValueOrZero() - sellQuantity)
-			sellOrder.SellQuantity = null.FloatFrom(sellOrder.SellQuantity.ValueOrZero() - sellQuantity)
-
-			if buyOrder.BuyQuantity.ValueOrZero() <= 0 {
-				buyOrder.Status = types.OrderClosed
-				if err = m.store.SaveOrder(ctx, buyOrder); err != nil {
-					return fmt.Errorf("storefx.SaveOrder failed: %w", err)
-				}
-			}
-			if sellOrder.SellQuantity.ValueOrZero() <= 0 {
-				sellOrder.Status = types.OrderClosed
-				if err = m.store.SaveOrder(ctx, sellOrder); err != nil {
-					return fmt.Errorf("storefx.SaveOrder failed: %w", err)
-				}
+			if _, err := m.settleTrade(ctx, orderBook, buyOrder, sellOrder, makerSide, price, sellQuantity, buyQuantity); err != nil {
+				return err
 			}
 
 			orderBook.BuyOrders[i] = &buyOrder
@@ -370,3 +1066,160 @@ func (m *OrderBookManager) matchOrders(ctx context.Context, orderBook *order_boo
 
 	return nil
 }
+
+// settleTrade moves the base quantity from the seller to the buyer and the quote
+// quantity back, persisting both orders and the trade record in the same database
+// transaction. On success the trade price becomes the last price of the book.
+func (m *OrderBookManager) settleTrade(
+	ctx context.Context,
+	orderBook *order_book.OrderBook,
+	buyOrder, sellOrder types.Order,
+	makerSide types.OrderType,
+	price, baseQuantity, quoteQuantity decimal.Decimal,
+) (*types.Trade, error) {
+	purpose := null.StringFrom(fmt.Sprintf("Trading %v for %v", sellOrder.SellCurrency, sellOrder.BuyCurrency))
+	pair := types.CurrencyPair{Currency1: sellOrder.SellCurrency, Currency2: sellOrder.BuyCurrency}
+
+	buyerSchedule, err := m.feeSchedule(ctx, pair, buyOrder.BuyRequisites.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sellerSchedule, err := m.feeSchedule(ctx, pair, sellOrder.SellRequisites.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each side pays its fee out of the currency it receives.
+	buyerFee := buyerSchedule.Fee(baseQuantity, sellOrder.SellCurrency, makerSide == types.Buy)
+	sellerFee := sellerSchedule.Fee(quoteQuantity, sellOrder.BuyCurrency, makerSide == types.Sell)
+
+	settleTradeArgs := store_types.SettleTradeArgs{
+		BuyOrder:  buyOrder,
+		SellOrder: sellOrder,
+		BaseTransfer: store_types.TransferArgs{
+			FromAddress: sellOrder.SellRequisites.Address,
+			ToAddress:   buyOrder.BuyRequisites.Address,
+			Amount:      baseQuantity,
+			Fee:         buyerFee,
+			Currency:    sellOrder.SellCurrency,
+			Purpose:     purpose,
+		},
+		QuoteTransfer: store_types.TransferArgs{
+			FromAddress: buyOrder.SellRequisites.Address,
+			ToAddress:   sellOrder.BuyRequisites.Address,
+			Amount:      quoteQuantity,
+			Fee:         sellerFee,
+			Currency:    sellOrder.BuyCurrency,
+			Purpose:     purpose,
+		},
+		Trade: types.Trade{
+			ID:            uuid.Must(uuid.NewV7()),
+			BaseCurrency:  sellOrder.SellCurrency,
+			QuoteCurrency: sellOrder.BuyCurrency,
+			BuyOrderID:    buyOrder.ID,
+			SellOrderID:   sellOrder.ID,
+			BuyerUserID:   buyOrder.BuyRequisites.UserID,
+			SellerUserID:  sellOrder.SellRequisites.UserID,
+			Price:         price,
+			Quantity:      baseQuantity,
+			QuoteQuantity: quoteQuantity,
+			MakerSide:     makerSide,
+			BuyerFee:      buyerFee,
+			SellerFee:     sellerFee,
+			CreatedAt:     lo.Latest(buyOrder.UpdatedAt, sellOrder.UpdatedAt),
+		},
+	}
+
+	trade, err := m.store.SettleTrade(ctx, settleTradeArgs)
+	if err != nil {
+		return nil, fmt.Errorf("store.SettleTrade failed: %w", err)
+	}
+
+	orderBook.LastPrice = decimal.NewNullDecimal(price)
+
+	m.notifyTrade(*trade)
+	m.notifyOrder(buyOrder)
+	m.notifyOrder(sellOrder)
+
+	return trade, nil
+}
+
+// feeSchedule returns the fee schedule of the pair for the tier of the user.
+// Pairs without a schedule are traded without fees.
+func (m *OrderBookManager) feeSchedule(ctx context.Context, pair types.CurrencyPair, userID string) (*types.FeeSchedule, error) {
+	user, err := m.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("store.GetUser failed: %w", err)
+	}
+
+	schedule, err := m.store.GetFeeSchedule(ctx, store_types.GetFeeScheduleArgs{
+		CurrencyPair: pair,
+		Tier:         user.FeeTier,
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		return &types.FeeSchedule{CurrencyPair: pair, Tier: user.FeeTier}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store.GetFeeSchedule failed: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// notifyTrade queues the trade for the trade listeners. The caller must hold the
+// book lock and call flushNotifications once it is released.
+func (m *OrderBookManager) notifyTrade(trade types.Trade) {
+	m.queueNotification(notification{trade: &trade})
+}
+
+// notifyOrder queues the order, without its fills, for the order listeners under
+// the same rules as notifyTrade.
+func (m *OrderBookManager) notifyOrder(order types.Order) {
+	order.Fills = nil
+	m.queueNotification(notification{order: &order})
+}
+
+func (m *OrderBookManager) queueNotification(n notification) {
+	m.notificationsMu.Lock()
+	defer m.notificationsMu.Unlock()
+
+	m.notifications = append(m.notifications, n)
+}
+
+// flushNotifications passes the queued trades and orders to the listeners. When
+// another goroutine is already passing them, it returns at once and that goroutine
+// passes the new ones too, so the listeners see them in the order they were queued.
+func (m *OrderBookManager) flushNotifications() {
+	m.notificationsMu.Lock()
+	if m.notifying {
+		m.notificationsMu.Unlock()
+		return
+	}
+	m.notifying = true
+
+	for len(m.notifications) > 0 {
+		notifications := m.notifications
+		m.notifications = nil
+		m.notificationsMu.Unlock()
+
+		m.listenersMu.RLock()
+		for _, n := range notifications {
+			if n.trade != nil {
+				for _, listener := range m.tradeListeners {
+					listener(*n.trade)
+				}
+			} else {
+				for _, listener := range m.orderListeners {
+					listener(*n.order)
+				}
+			}
+		}
+		m.listenersMu.RUnlock()
+
+		m.notificationsMu.Lock()
+	}
+
+	m.notifying = false
+	m.notificationsMu.Unlock()
+}
@@ -0,0 +1,1236 @@
+package order_book_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// fakeStore is an in-memory IStore with unlimited balances. It implements only
+// the methods the manager calls, the embedded interface panics on any other.
+type fakeStore struct {
+	dependencies.IStore
+
+	mu      sync.Mutex
+	wallets map[string]*types.Wallet
+	orders  map[uuid.UUID]types.Order
+	trades  []types.Trade
+	halted  map[string]types.CurrencyPair
+
+	// feeSchedules and feeTiers are empty unless a test charges fees.
+	feeSchedules map[types.FeeTier]types.FeeSchedule
+	feeTiers     map[string]types.FeeTier
+
+	settleErr error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		wallets: make(map[string]*types.Wallet),
+		orders:  make(map[uuid.UUID]types.Order),
+		halted:  make(map[string]types.CurrencyPair),
+	}
+}
+
+func (s *fakeStore) addWallet(userID string, currency types.Currency, balance int64) types.Requisites {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requisites := types.Requisites{
+		UserID:  userID,
+		Address: fmt.Sprintf("%s-%s", userID, currency),
+	}
+	s.wallets[requisites.Address] = &types.Wallet{
+		Requisites: requisites,
+		Currency:   currency,
+		Balance:    decimal.NewFromInt(balance),
+		Available:  decimal.NewFromInt(balance),
+	}
+
+	return requisites
+}
+
+func (s *fakeStore) ListWallets(ctx context.Context, args store_types.ListWalletsArgs) ([]*types.Wallet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallets := make([]*types.Wallet, 0, len(args.AddresssIn))
+	for _, address := range args.AddresssIn {
+		if wallet, ok := s.wallets[address]; ok {
+			walletCopy := *wallet
+			wallets = append(wallets, &walletCopy)
+		}
+	}
+	return wallets, nil
+}
+
+func (s *fakeStore) SettleTrade(ctx context.Context, args store_types.SettleTradeArgs) (*types.Trade, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settleErr != nil {
+		return nil, s.settleErr
+	}
+
+	s.orders[args.BuyOrder.ID] = args.BuyOrder
+	s.orders[args.SellOrder.ID] = args.SellOrder
+	s.trades = append(s.trades, args.Trade)
+	return &args.Trade, nil
+}
+
+func (s *fakeStore) GetFeeSchedule(ctx context.Context, args store_types.GetFeeScheduleArgs) (*types.FeeSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule, ok := s.feeSchedules[args.Tier]; ok {
+		return &schedule, nil
+	}
+	if schedule, ok := s.feeSchedules[types.FeeTierStandard]; ok {
+		return &schedule, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *fakeStore) PlaceOrder(ctx context.Context, args store_types.PlaceOrderArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[args.Order.ID] = args.Order
+	return nil
+}
+
+func (s *fakeStore) CancelOrder(ctx context.Context, order types.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[order.ID] = order
+	return nil
+}
+
+func (s *fakeStore) SaveOrder(ctx context.Context, order types.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[order.ID] = order
+	return nil
+}
+
+func (s *fakeStore) ListOrders(ctx context.Context, args store_types.ListOrdersArgs) ([]*types.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := make([]*types.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		if len(args.StatusIn) > 0 && !slices.Contains(args.StatusIn, order.Status) {
+			continue
+		}
+		orderCopy := order
+		orders = append(orders, &orderCopy)
+	}
+	return orders, nil
+}
+
+func (s *fakeStore) SetMarketHalted(ctx context.Context, args store_types.SetMarketHaltedArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.halted, args.CurrencyPair.StringReverse())
+	if args.Halted {
+		s.halted[args.CurrencyPair.String()] = args.CurrencyPair
+	} else {
+		delete(s.halted, args.CurrencyPair.String())
+	}
+	return nil
+}
+
+func (s *fakeStore) ListHaltedMarkets(ctx context.Context) ([]types.CurrencyPair, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return lo.Values(s.halted), nil
+}
+
+func (s *fakeStore) GetUser(ctx context.Context, userID string) (*types.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tier, ok := s.feeTiers[userID]
+	if !ok {
+		tier = types.FeeTierStandard
+	}
+	return &types.User{UserID: userID, FeeTier: tier}, nil
+}
+
+func TestOrderBookManagerConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	pairs := []types.CurrencyPair{
+		{Currency1: types.BTC, Currency2: types.USDT},
+		{Currency1: types.ETH, Currency2: types.USDT},
+	}
+
+	const workers = 8
+	const ordersPerWorker = 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		userID := fmt.Sprintf("user-%d", w)
+		requisites := map[types.Currency]types.Requisites{}
+		for _, currency := range types.ListAvailableCurrencies() {
+			requisites[currency] = store.addWallet(userID, currency, 1_000_000_000)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < ordersPerWorker; i++ {
+				pair := pairs[i%len(pairs)]
+
+				args := order_book_types.CreateOrderArgs{
+					Type:           types.Buy,
+					SellCurrency:   pair.Currency2,
+					SellRequisites: requisites[pair.Currency2],
+					Price:          decimal.NewFromInt(int64(100 + i%5)),
+					BuyCurrency:    pair.Currency1,
+					BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+					BuyRequisites:  requisites[pair.Currency1],
+				}
+				if i%2 == 1 {
+					args = order_book_types.CreateOrderArgs{
+						Type:           types.Sell,
+						SellCurrency:   pair.Currency1,
+						SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+						SellRequisites: requisites[pair.Currency1],
+						Price:          decimal.NewFromInt(int64(100 + i%5)),
+						BuyCurrency:    pair.Currency2,
+						BuyRequisites:  requisites[pair.Currency2],
+					}
+				}
+
+				order, err := m.CreateOrder(ctx, args)
+				require.NoError(t, err)
+
+				if i%3 == 0 {
+					err = m.CancelOrder(ctx, pair, order.ID)
+					require.NoError(t, err)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < ordersPerWorker; j++ {
+				require.NoError(t, m.MatchOrders(ctx))
+
+				_, err := m.ListOrders(ctx, order_book_types.ListOrdersArgs{CurrencyPair: pairs[j%len(pairs)]})
+				require.NoError(t, err)
+
+				_, err = m.ListAvailableCurrencyPairs(ctx)
+				require.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.NoError(t, m.MatchOrders(ctx))
+
+	pairsCount, err := m.ListAvailableCurrencyPairs(ctx)
+	require.NoError(t, err)
+	require.Len(t, pairsCount, len(pairs))
+
+	for _, pair := range pairs {
+		orders, err := m.ListOrders(ctx, order_book_types.ListOrdersArgs{CurrencyPair: pair})
+		require.NoError(t, err)
+		require.Len(t, orders, workers*ordersPerWorker/len(pairs))
+	}
+}
+
+func TestCancelOrderPersistsStatus(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	btc := store.addWallet("alice", types.BTC, 10)
+	usdt := store.addWallet("alice", types.USDT, 0)
+
+	order, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: btc,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  usdt,
+		CreatedAt:      time.Now(),
+	})
+	require.NoError(t, err)
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	require.NoError(t, m.CancelOrder(ctx, pair, order.ID))
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.Equal(t, types.OrderCancelled, store.orders[order.ID].Status)
+	require.True(t, store.orders[order.ID].RemovedAt.Valid)
+}
+
+func TestCancelOrderRejectsFinishedOrders(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+	malloryBTC := store.addWallet("mallory", types.BTC, 0)
+	malloryUSDT := store.addWallet("mallory", types.USDT, 1000)
+
+	sell, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: aliceBTC,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	})
+	require.NoError(t, err)
+
+	_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Buy,
+		SellCurrency:   types.USDT,
+		SellRequisites: malloryUSDT,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.BTC,
+		BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		BuyRequisites:  malloryBTC,
+	})
+	require.NoError(t, err)
+
+	filled, err := m.GetOrder(ctx, pair, sell.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.OrderClosed, filled.Status)
+
+	require.ErrorIs(t, m.CancelOrder(ctx, pair, sell.ID), ErrOrderNotCancellable)
+
+	expiring, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		TimeInForce:    types.GTD,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: aliceBTC,
+		Price:          decimal.NewFromInt(200),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+		ExpiresAt:      null.TimeFrom(time.Now().Add(10 * time.Millisecond)),
+	})
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, m.ExpireOrders(ctx))
+
+	require.ErrorIs(t, m.CancelOrder(ctx, pair, expiring.ID), ErrOrderNotCancellable)
+
+	// The history of both orders is left as it was.
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.Equal(t, types.OrderClosed, store.orders[sell.ID].Status)
+	require.False(t, store.orders[sell.ID].RemovedAt.Valid)
+	require.Equal(t, types.OrderExpired, store.orders[expiring.ID].Status)
+	require.False(t, store.orders[expiring.ID].RemovedAt.Valid)
+}
+
+func TestMatchOrdersLeavesBookUnchangedOnSettlementFailure(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	// Crossing orders placed through separate managers rest unmatched in the store,
+	// so the restored book is left for the sweep.
+	for _, args := range []order_book_types.CreateOrderArgs{
+		{
+			Type:           types.Sell,
+			SellCurrency:   types.BTC,
+			SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			SellRequisites: aliceBTC,
+			Price:          decimal.NewFromInt(100),
+			BuyCurrency:    types.USDT,
+			BuyRequisites:  aliceUSDT,
+		},
+		{
+			Type:           types.Buy,
+			SellCurrency:   types.USDT,
+			SellRequisites: bobUSDT,
+			Price:          decimal.NewFromInt(100),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			BuyRequisites:  bobBTC,
+		},
+	} {
+		seed, err := NewOrderBookManager(store, zap.NewNop())
+		require.NoError(t, err)
+
+		_, err = seed.CreateOrder(ctx, args)
+		require.NoError(t, err)
+	}
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, m.LoadOrderBooks(ctx))
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	listOrders := func() []*types.Order {
+		orders, err := m.ListOrders(ctx, order_book_types.ListOrdersArgs{CurrencyPair: pair})
+		require.NoError(t, err)
+		return orders
+	}
+	before := listOrders()
+
+	store.settleErr = errors.New("insufficient funds")
+	require.Error(t, m.MatchOrders(ctx))
+	require.Equal(t, before, listOrders())
+
+	store.settleErr = nil
+	require.NoError(t, m.MatchOrders(ctx))
+	for _, order := range listOrders() {
+		require.Equal(t, types.OrderClosed, order.Status)
+	}
+}
+
+func TestMatchOrdersExecutesAtRestingBuyPrice(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	// Bob's buy rests first, so it is the maker of the trade the sweep makes.
+	for _, args := range []order_book_types.CreateOrderArgs{
+		{
+			Type:           types.Buy,
+			SellCurrency:   types.USDT,
+			SellRequisites: bobUSDT,
+			Price:          decimal.NewFromInt(110),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			BuyRequisites:  bobBTC,
+		},
+		{
+			Type:           types.Sell,
+			SellCurrency:   types.BTC,
+			SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			SellRequisites: aliceBTC,
+			Price:          decimal.NewFromInt(100),
+			BuyCurrency:    types.USDT,
+			BuyRequisites:  aliceUSDT,
+		},
+	} {
+		seed, err := NewOrderBookManager(store, zap.NewNop())
+		require.NoError(t, err)
+
+		_, err = seed.CreateOrder(ctx, args)
+		require.NoError(t, err)
+	}
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, m.LoadOrderBooks(ctx))
+	require.NoError(t, m.MatchOrders(ctx))
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	require.Len(t, store.trades, 1)
+	trade := store.trades[0]
+	require.Equal(t, types.Buy, trade.MakerSide)
+	require.True(t, trade.Price.Equal(decimal.NewFromInt(110)))
+	require.True(t, trade.QuoteQuantity.Equal(decimal.NewFromInt(110)))
+}
+
+func TestMarketOrderRejectedOnEmptyBook(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	btc := store.addWallet("alice", types.BTC, 10)
+	usdt := store.addWallet("alice", types.USDT, 0)
+
+	_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		Kind:           types.Market,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: btc,
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  usdt,
+	})
+	require.ErrorIs(t, err, ErrOrderBookEmpty)
+	require.Empty(t, store.orders)
+}
+
+func TestHaltedMarketRejectsOrders(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	sell := order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: aliceBTC,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	}
+	resting, err := m.CreateOrder(ctx, sell)
+	require.NoError(t, err)
+
+	require.NoError(t, m.SetMarketHalted(ctx, pair, true))
+
+	_, err = m.CreateOrder(ctx, sell)
+	require.ErrorIs(t, err, ErrMarketHalted)
+
+	// Resting orders can still be cancelled while the market is halted.
+	require.NoError(t, m.CancelOrder(ctx, pair, resting.ID))
+
+	// The halt survives a restart, whichever way round the pair is given.
+	restored, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, restored.LoadOrderBooks(ctx))
+
+	_, err = restored.CreateOrder(ctx, sell)
+	require.ErrorIs(t, err, ErrMarketHalted)
+
+	require.NoError(t, restored.SetMarketHalted(ctx, types.CurrencyPair{Currency1: types.USDT, Currency2: types.BTC}, false))
+	require.Empty(t, store.halted)
+
+	_, err = restored.CreateOrder(ctx, sell)
+	require.NoError(t, err)
+}
+
+func TestMarketSellSweepsBestPrices(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 10000)
+
+	for _, price := range []int64{90, 110, 100} {
+		_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Buy,
+			SellCurrency:   types.USDT,
+			SellRequisites: bobUSDT,
+			Price:          decimal.NewFromInt(price),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			BuyRequisites:  bobBTC,
+		})
+		require.NoError(t, err)
+	}
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	order, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		Kind:           types.Market,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.RequireFromString("1.5")),
+		SellRequisites: aliceBTC,
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, types.OrderClosed, order.Status)
+	require.Len(t, order.Fills, 2)
+	require.True(t, order.Fills[0].Price.Equal(decimal.NewFromInt(110)))
+	require.True(t, order.Fills[1].Price.Equal(decimal.NewFromInt(100)))
+	require.True(t, order.Fills[1].BaseQuantity.Equal(decimal.RequireFromString("0.5")))
+	require.True(t, order.AveragePrice.Decimal.Equal(decimal.RequireFromString("106.666667")))
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	orders, err := m.ListOrders(ctx, order_book_types.ListOrdersArgs{CurrencyPair: pair})
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	for _, o := range orders {
+		require.NotEqual(t, order.ID, o.ID)
+	}
+}
+
+func TestMarketBuySpendsQuoteBudget(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: aliceBTC,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	})
+	require.NoError(t, err)
+
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	order, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Buy,
+		Kind:           types.Market,
+		SellCurrency:   types.USDT,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(150)),
+		SellRequisites: bobUSDT,
+		BuyCurrency:    types.BTC,
+		BuyRequisites:  bobBTC,
+	})
+	require.NoError(t, err)
+
+	// The book only had 1 BTC, so the rest of the budget is released.
+	require.Equal(t, types.OrderCancelled, order.Status)
+	require.Len(t, order.Fills, 1)
+	require.True(t, order.Fills[0].BaseQuantity.Equal(decimal.NewFromInt(1)))
+	require.True(t, order.Fills[0].QuoteQuantity.Equal(decimal.NewFromInt(100)))
+	require.True(t, order.OriginalQuantity.Equal(decimal.NewFromInt(150)))
+	require.True(t, order.RemainingQuantity.Equal(decimal.NewFromInt(50)))
+	require.True(t, order.FilledQuantity.Equal(decimal.NewFromInt(1)))
+	require.True(t, order.FilledQuoteQuantity.Equal(decimal.NewFromInt(100)))
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.Equal(t, types.OrderCancelled, store.orders[order.ID].Status)
+}
+
+// newUnevenBook returns a manager with asks of 1 BTC at 3, 10 BTC at 7 and 10 BTC
+// at 8, which a budget of 10.5 USDT can not buy evenly.
+func newUnevenBook(t *testing.T) (*OrderBookManager, *fakeStore) {
+	t.Helper()
+
+	store := newFakeStore()
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 30)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	for _, ask := range []struct{ quantity, price int64 }{{1, 3}, {10, 7}, {10, 8}} {
+		_, err = m.CreateOrder(context.Background(), order_book_types.CreateOrderArgs{
+			Type:           types.Sell,
+			SellCurrency:   types.BTC,
+			SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(ask.quantity)),
+			SellRequisites: aliceBTC,
+			Price:          decimal.NewFromInt(ask.price),
+			BuyCurrency:    types.USDT,
+			BuyRequisites:  aliceUSDT,
+		})
+		require.NoError(t, err)
+	}
+
+	return m, store
+}
+
+func unevenMarketBuyArgs(store *fakeStore, timeInForce types.TimeInForce) order_book_types.CreateOrderArgs {
+	return order_book_types.CreateOrderArgs{
+		Type:           types.Buy,
+		Kind:           types.Market,
+		TimeInForce:    timeInForce,
+		SellCurrency:   types.USDT,
+		SellQuantity:   decimal.NewNullDecimal(decimal.RequireFromString("10.5")),
+		SellRequisites: store.addWallet("bob", types.USDT, 1000),
+		BuyCurrency:    types.BTC,
+		BuyRequisites:  store.addWallet("bob", types.BTC, 0),
+	}
+}
+
+func TestMarketBuyClosesOnDustBudget(t *testing.T) {
+	m, store := newUnevenBook(t)
+
+	order, err := m.CreateOrder(context.Background(), unevenMarketBuyArgs(store, types.IOC))
+	require.NoError(t, err)
+
+	// 7.5 USDT buys 1.07142857 BTC at 7 for 7.499999 USDT, and the 0.000001 USDT
+	// left can not pay for a satoshi at 7 or 8.
+	require.Equal(t, types.OrderClosed, order.Status)
+	require.Len(t, order.Fills, 2)
+	require.True(t, order.Fills[1].BaseQuantity.Equal(decimal.RequireFromString("1.07142857")))
+	require.True(t, order.Fills[1].QuoteQuantity.Equal(decimal.RequireFromString("7.499999")))
+	require.True(t, order.RemainingQuantity.Equal(decimal.RequireFromString("0.000001")))
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	require.Len(t, store.trades, 2)
+	for _, trade := range store.trades {
+		require.True(t, trade.Quantity.IsPositive())
+		require.True(t, trade.QuoteQuantity.IsPositive())
+	}
+	require.Equal(t, types.OrderClosed, store.orders[order.ID].Status)
+}
+
+func TestFOKMarketBuyWithUnevenBudget(t *testing.T) {
+	m, store := newUnevenBook(t)
+
+	// The dust left after the second level does not count as unfilled.
+	order, err := m.CreateOrder(context.Background(), unevenMarketBuyArgs(store, types.FOK))
+	require.NoError(t, err)
+	require.Equal(t, types.OrderClosed, order.Status)
+	require.Len(t, order.Fills, 2)
+}
+
+func TestTimeInForce(t *testing.T) {
+	ctx := context.Background()
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	// newBook returns a manager with asks of 1 BTC at 100 and 1 BTC at 110.
+	newBook := func(t *testing.T) (*OrderBookManager, *fakeStore, types.Requisites, types.Requisites) {
+		store := newFakeStore()
+
+		m, err := NewOrderBookManager(store, zap.NewNop())
+		require.NoError(t, err)
+
+		aliceBTC := store.addWallet("alice", types.BTC, 10)
+		aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+		for _, price := range []int64{100, 110} {
+			_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+				Type:           types.Sell,
+				SellCurrency:   types.BTC,
+				SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+				SellRequisites: aliceBTC,
+				Price:          decimal.NewFromInt(price),
+				BuyCurrency:    types.USDT,
+				BuyRequisites:  aliceUSDT,
+			})
+			require.NoError(t, err)
+		}
+
+		return m, store, store.addWallet("bob", types.BTC, 0), store.addWallet("bob", types.USDT, 1000)
+	}
+
+	buyArgs := func(btc, usdt types.Requisites, timeInForce types.TimeInForce, quantity int64) order_book_types.CreateOrderArgs {
+		return order_book_types.CreateOrderArgs{
+			Type:           types.Buy,
+			TimeInForce:    timeInForce,
+			SellCurrency:   types.USDT,
+			SellRequisites: usdt,
+			Price:          decimal.NewFromInt(105),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(quantity)),
+			BuyRequisites:  btc,
+		}
+	}
+
+	t.Run("IOC fills what crosses and cancels the rest", func(t *testing.T) {
+		m, _, btc, usdt := newBook(t)
+
+		order, err := m.CreateOrder(ctx, buyArgs(btc, usdt, types.IOC, 2))
+		require.NoError(t, err)
+		require.Equal(t, types.OrderCancelled, order.Status)
+		require.Len(t, order.Fills, 1)
+		require.True(t, order.Fills[0].Price.Equal(decimal.NewFromInt(100)))
+		require.True(t, order.FilledQuantity.Equal(decimal.NewFromInt(1)))
+		require.True(t, order.RemainingQuantity.Equal(decimal.NewFromInt(1)))
+
+		orders, err := m.ListOrders(ctx, order_book_types.ListOrdersArgs{CurrencyPair: pair})
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+	})
+
+	t.Run("FOK does not trade when it can not fill completely", func(t *testing.T) {
+		m, store, btc, usdt := newBook(t)
+
+		order, err := m.CreateOrder(ctx, buyArgs(btc, usdt, types.FOK, 2))
+		require.NoError(t, err)
+		require.Equal(t, types.OrderCancelled, order.Status)
+		require.Empty(t, order.Fills)
+
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		for _, o := range store.orders {
+			if o.ID != order.ID {
+				require.Equal(t, types.OrderOpen, o.Status)
+			}
+		}
+	})
+
+	t.Run("FOK fills completely", func(t *testing.T) {
+		m, _, btc, usdt := newBook(t)
+
+		order, err := m.CreateOrder(ctx, buyArgs(btc, usdt, types.FOK, 1))
+		require.NoError(t, err)
+		require.Equal(t, types.OrderClosed, order.Status)
+		require.Len(t, order.Fills, 1)
+	})
+
+	t.Run("GTD order expires", func(t *testing.T) {
+		m, store, btc, usdt := newBook(t)
+
+		args := buyArgs(btc, usdt, types.GTD, 1)
+		args.Price = decimal.NewFromInt(90)
+		args.ExpiresAt = null.TimeFrom(time.Now().Add(50 * time.Millisecond))
+
+		order, err := m.CreateOrder(ctx, args)
+		require.NoError(t, err)
+		require.Equal(t, types.OrderOpen, order.Status)
+
+		require.NoError(t, m.ExpireOrders(ctx))
+		store.mu.Lock()
+		require.Equal(t, types.OrderOpen, store.orders[order.ID].Status)
+		store.mu.Unlock()
+
+		time.Sleep(100 * time.Millisecond)
+		require.NoError(t, m.ExpireOrders(ctx))
+
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		require.Equal(t, types.OrderExpired, store.orders[order.ID].Status)
+		require.True(t, store.orders[order.ID].ExpiredAt.Valid)
+	})
+}
+
+func TestStopOrderTriggersOnLastPrice(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	for _, price := range []int64{90, 85} {
+		_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Buy,
+			SellCurrency:   types.USDT,
+			SellRequisites: bobUSDT,
+			Price:          decimal.NewFromInt(price),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			BuyRequisites:  bobBTC,
+		})
+		require.NoError(t, err)
+	}
+
+	carolBTC := store.addWallet("carol", types.BTC, 1)
+	carolUSDT := store.addWallet("carol", types.USDT, 0)
+
+	stopArgs := order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		Kind:           types.StopMarket,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: carolBTC,
+		TriggerPrice:   decimal.NewNullDecimal(decimal.NewFromInt(95)),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  carolUSDT,
+	}
+
+	stopOrder, err := m.CreateOrder(ctx, stopArgs)
+	require.NoError(t, err)
+	require.Equal(t, types.OrderPending, stopOrder.Status)
+
+	// The pending order survives a restart.
+	restored, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, restored.LoadOrderBooks(ctx))
+
+	restoredOrders, err := restored.ListOrders(ctx, order_book_types.ListOrdersArgs{
+		CurrencyPair: pair,
+		OrderStatus:  null.ValueFrom(types.OrderPending),
+	})
+	require.NoError(t, err)
+	require.Len(t, restoredOrders, 1)
+	require.Equal(t, stopOrder.ID, restoredOrders[0].ID)
+
+	require.NoError(t, m.MatchOrders(ctx))
+	store.mu.Lock()
+	require.Equal(t, types.OrderPending, store.orders[stopOrder.ID].Status)
+	store.mu.Unlock()
+
+	aliceBTC := store.addWallet("alice", types.BTC, 1)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		Kind:           types.Market,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: aliceBTC,
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	})
+	require.NoError(t, err)
+
+	_, err = m.CreateOrder(ctx, stopArgs)
+	require.ErrorIs(t, err, ErrOrderWouldTrigger)
+
+	require.NoError(t, m.MatchOrders(ctx))
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	triggered := store.orders[stopOrder.ID]
+	require.Equal(t, types.Market, triggered.Kind)
+	require.Equal(t, types.OrderClosed, triggered.Status)
+	require.True(t, triggered.TriggeredAt.Valid)
+}
+
+func TestCreateOrderMatchesImmediately(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	for _, price := range []int64{100, 101} {
+		_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Sell,
+			SellCurrency:   types.BTC,
+			SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			SellRequisites: aliceBTC,
+			Price:          decimal.NewFromInt(price),
+			BuyCurrency:    types.USDT,
+			BuyRequisites:  aliceUSDT,
+		})
+		require.NoError(t, err)
+	}
+
+	var notified []types.Trade
+	m.SubscribeTrades(func(trade types.Trade) {
+		notified = append(notified, trade)
+	})
+
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	order, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Buy,
+		SellCurrency:   types.USDT,
+		SellRequisites: bobUSDT,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.BTC,
+		BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(2)),
+		BuyRequisites:  bobBTC,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, types.OrderPartiallyFilled, order.Status)
+	require.Len(t, order.Fills, 1)
+	require.True(t, order.AveragePrice.Decimal.Equal(decimal.NewFromInt(100)))
+	require.True(t, order.BuyQuantity.Decimal.Equal(decimal.NewFromInt(2)))
+	require.True(t, order.FilledQuantity.Equal(decimal.NewFromInt(1)))
+	require.True(t, order.RemainingQuantity.Equal(decimal.NewFromInt(1)))
+
+	store.mu.Lock()
+	require.Len(t, store.trades, 1)
+	require.Equal(t, order.Fills[0].TradeID, store.trades[0].ID)
+	require.Equal(t, order.ID, store.trades[0].BuyOrderID)
+	require.Equal(t, types.Sell, store.trades[0].MakerSide)
+	require.Equal(t, types.BTC, store.trades[0].BaseCurrency)
+	store.mu.Unlock()
+
+	require.Len(t, notified, 1)
+	require.Equal(t, order.Fills[0].TradeID, notified[0].ID)
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	resting, err := m.ListOrders(ctx, order_book_types.ListOrdersArgs{
+		CurrencyPair: pair,
+		OrderStatus:  null.ValueFrom(types.OrderPartiallyFilled),
+	})
+	require.NoError(t, err)
+	require.Len(t, resting, 1)
+	require.Equal(t, order.ID, resting[0].ID)
+}
+
+func TestListenersRunAfterUnlock(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	// The listener reads the book of the order, which deadlocks under the book lock.
+	var depths []*types.Depth
+	m.SubscribeOrders(func(order types.Order) {
+		depth, err := m.GetDepth(ctx, order_book_types.GetDepthArgs{CurrencyPair: pair})
+		require.NoError(t, err)
+		depths = append(depths, depth)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		_, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Sell,
+			SellCurrency:   types.BTC,
+			SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			SellRequisites: aliceBTC,
+			Price:          decimal.NewFromInt(100),
+			BuyCurrency:    types.USDT,
+			BuyRequisites:  aliceUSDT,
+		})
+		require.NoError(t, err)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("CreateOrder did not return, the listener was called under the book lock")
+	}
+
+	// The listener sees the book with the order resting on it.
+	require.Len(t, depths, 1)
+	require.Len(t, depths[0].Asks, 1)
+}
+
+func TestPartialFillsAccumulate(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+
+	sell, err := m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(3)),
+		SellRequisites: aliceBTC,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.OrderOpen, sell.Status)
+	require.True(t, sell.RemainingQuantity.Equal(decimal.NewFromInt(3)))
+	require.True(t, sell.FilledQuantity.IsZero())
+
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	for _, price := range []int64{100, 110} {
+		_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Buy,
+			SellCurrency:   types.USDT,
+			SellRequisites: bobUSDT,
+			Price:          decimal.NewFromInt(price),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			BuyRequisites:  bobBTC,
+		})
+		require.NoError(t, err)
+	}
+
+	store.mu.Lock()
+	saved := store.orders[sell.ID]
+	store.mu.Unlock()
+
+	// Both buys trade at the resting price of the sell order.
+	require.Equal(t, types.OrderPartiallyFilled, saved.Status)
+	require.True(t, saved.OriginalQuantity.Equal(decimal.NewFromInt(3)))
+	require.True(t, saved.FilledQuantity.Equal(decimal.NewFromInt(2)))
+	require.True(t, saved.FilledQuoteQuantity.Equal(decimal.NewFromInt(200)))
+	require.True(t, saved.RemainingQuantity.Equal(decimal.NewFromInt(1)))
+	require.True(t, saved.AveragePrice.Decimal.Equal(decimal.NewFromInt(100)))
+	require.True(t, saved.SellQuantity.Decimal.Equal(decimal.NewFromInt(3)))
+}
+
+func TestGetDepth(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 10000)
+
+	for _, ask := range []struct{ price, quantity string }{{"101.5", "1"}, {"101.5", "2"}, {"102.25", "1"}, {"110", "1"}} {
+		_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Sell,
+			SellCurrency:   types.BTC,
+			SellQuantity:   decimal.NewNullDecimal(decimal.RequireFromString(ask.quantity)),
+			SellRequisites: aliceBTC,
+			Price:          decimal.RequireFromString(ask.price),
+			BuyCurrency:    types.USDT,
+			BuyRequisites:  aliceUSDT,
+		})
+		require.NoError(t, err)
+	}
+
+	for _, price := range []string{"100.5", "99.75", "99"} {
+		_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+			Type:           types.Buy,
+			SellCurrency:   types.USDT,
+			SellRequisites: aliceUSDT,
+			Price:          decimal.RequireFromString(price),
+			BuyCurrency:    types.BTC,
+			BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+			BuyRequisites:  aliceBTC,
+		})
+		require.NoError(t, err)
+	}
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	t.Run("price levels", func(t *testing.T) {
+		depth, err := m.GetDepth(ctx, order_book_types.GetDepthArgs{CurrencyPair: pair, Limit: 2})
+		require.NoError(t, err)
+
+		require.Len(t, depth.Asks, 2)
+		require.True(t, depth.Asks[0].Price.Equal(decimal.RequireFromString("101.5")))
+		require.True(t, depth.Asks[0].Quantity.Equal(decimal.NewFromInt(3)))
+		require.Equal(t, 2, depth.Asks[0].OrderCount)
+		require.True(t, depth.Asks[1].Price.Equal(decimal.RequireFromString("102.25")))
+
+		require.Len(t, depth.Bids, 2)
+		require.True(t, depth.Bids[0].Price.Equal(decimal.RequireFromString("100.5")))
+		require.True(t, depth.Bids[1].Price.Equal(decimal.RequireFromString("99.75")))
+	})
+
+	t.Run("tick grouping", func(t *testing.T) {
+		depth, err := m.GetDepth(ctx, order_book_types.GetDepthArgs{
+			CurrencyPair: pair,
+			Tick:         decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		})
+		require.NoError(t, err)
+
+		// Asks round up and bids round down to the tick.
+		require.Len(t, depth.Asks, 3)
+		require.True(t, depth.Asks[0].Price.Equal(decimal.NewFromInt(102)))
+		require.True(t, depth.Asks[0].Quantity.Equal(decimal.NewFromInt(3)))
+		require.True(t, depth.Asks[1].Price.Equal(decimal.NewFromInt(103)))
+		require.True(t, depth.Asks[1].Quantity.Equal(decimal.NewFromInt(1)))
+
+		require.Len(t, depth.Bids, 2)
+		require.True(t, depth.Bids[0].Price.Equal(decimal.NewFromInt(100)))
+		require.True(t, depth.Bids[1].Price.Equal(decimal.NewFromInt(99)))
+		require.True(t, depth.Bids[1].Quantity.Equal(decimal.NewFromInt(2)))
+		require.Equal(t, 2, depth.Bids[1].OrderCount)
+	})
+}
+
+func TestSettleTradeChargesFees(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	store.feeSchedules = map[types.FeeTier]types.FeeSchedule{
+		types.FeeTierStandard: {
+			CurrencyPair: pair,
+			Tier:         types.FeeTierStandard,
+			MakerRate:    decimal.RequireFromString("0.001"),
+			TakerRate:    decimal.RequireFromString("0.002"),
+		},
+		types.FeeTierMarketMaker: {
+			CurrencyPair: pair,
+			Tier:         types.FeeTierMarketMaker,
+			MakerRate:    decimal.Zero,
+			TakerRate:    decimal.RequireFromString("0.0005"),
+		},
+	}
+	store.feeTiers = map[string]types.FeeTier{"alice": types.FeeTierMarketMaker}
+
+	m, err := NewOrderBookManager(store, zap.NewNop())
+	require.NoError(t, err)
+
+	aliceBTC := store.addWallet("alice", types.BTC, 10)
+	aliceUSDT := store.addWallet("alice", types.USDT, 0)
+	bobBTC := store.addWallet("bob", types.BTC, 0)
+	bobUSDT := store.addWallet("bob", types.USDT, 1000)
+
+	// Bob has no tier and pays the standard rates.
+	_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Buy,
+		SellCurrency:   types.USDT,
+		SellRequisites: bobUSDT,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.BTC,
+		BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		BuyRequisites:  bobBTC,
+	})
+	require.NoError(t, err)
+
+	_, err = m.CreateOrder(ctx, order_book_types.CreateOrderArgs{
+		Type:           types.Sell,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: aliceBTC,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  aliceUSDT,
+	})
+	require.NoError(t, err)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Bob rested first and is the maker, and Alice takes at the market maker rate.
+	require.Len(t, store.trades, 1)
+	trade := store.trades[0]
+	require.Equal(t, types.Buy, trade.MakerSide)
+	require.True(t, trade.BuyerFee.Equal(decimal.RequireFromString("0.001")))
+	require.True(t, trade.SellerFee.Equal(decimal.RequireFromString("0.05")))
+}
@@ -0,0 +1,67 @@
+package payout_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/types"
+)
+
+var ErrUnknownPayout = errors.New("unknown payout")
+
+// FakePayout is an in-memory payout backend. Payouts take the Result status when
+// they are sent, and SetStatus moves them on. Send fails with SendErr when it is set.
+type FakePayout struct {
+	mu sync.Mutex
+
+	Result  types.PayoutStatus
+	SendErr error
+
+	payouts map[string]types.PayoutStatus
+}
+
+// NewFakePayout returns a backend that completes every payout as soon as it is sent.
+func NewFakePayout() *FakePayout {
+	return &FakePayout{
+		Result:  types.PayoutCompleted,
+		payouts: make(map[string]types.PayoutStatus),
+	}
+}
+
+var _ dependencies.IPayout = (*FakePayout)(nil)
+
+func (p *FakePayout) Send(ctx context.Context, withdrawal types.Withdrawal) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.SendErr != nil {
+		return "", p.SendErr
+	}
+
+	reference := "fake-" + withdrawal.ID.String()
+	if _, ok := p.payouts[reference]; !ok {
+		p.payouts[reference] = p.Result
+	}
+
+	return reference, nil
+}
+
+func (p *FakePayout) Status(ctx context.Context, reference string) (types.PayoutStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status, ok := p.payouts[reference]
+	if !ok {
+		return "", ErrUnknownPayout
+	}
+
+	return status, nil
+}
+
+func (p *FakePayout) SetStatus(reference string, status types.PayoutStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.payouts[reference] = status
+}
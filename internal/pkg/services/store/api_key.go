@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+func (s *Store) CreateAPIKey(ctx context.Context, key types.APIKey) error {
+	sql, queryArgs := table.ApiKeys.
+		INSERT(table.ApiKeys.AllColumns).
+		MODEL(store_types.MapToAPIKeyStore(&key)).
+		Sql()
+
+	if _, err := s.db.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetAPIKey(ctx context.Context, id uuid.UUID) (*types.APIKey, error) {
+	sql, queryArgs := table.ApiKeys.
+		SELECT(table.ApiKeys.AllColumns).
+		WHERE(table.ApiKeys.ID.EQ(postgres.UUID(id))).
+		Sql()
+
+	keys := []store_types.APIKey{}
+	if err := pgxscan.Select(ctx, s.db, &keys, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return store_types.MapToAPIKey(&keys[0]), nil
+}
+
+// ListAPIKeys lists the API keys of the user, revoked ones included, newest first.
+func (s *Store) ListAPIKeys(ctx context.Context, userID string) ([]*types.APIKey, error) {
+	sql, queryArgs := table.ApiKeys.
+		SELECT(table.ApiKeys.AllColumns).
+		WHERE(table.ApiKeys.UserID.EQ(postgres.String(userID))).
+		ORDER_BY(table.ApiKeys.CreatedAt.DESC()).
+		Sql()
+
+	keys := []store_types.APIKey{}
+	if err := pgxscan.Select(ctx, s.db, &keys, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(keys, func(key store_types.APIKey, _ int) *types.APIKey {
+		return store_types.MapToAPIKey(&key)
+	}), nil
+}
+
+// RevokeAPIKey revokes an active API key of the user. It fails with ErrNotFound
+// when the user has no such active key.
+func (s *Store) RevokeAPIKey(ctx context.Context, args store_types.RevokeAPIKeyArgs) error {
+	sql, queryArgs := table.ApiKeys.
+		UPDATE(table.ApiKeys.RevokedAt).
+		SET(postgres.TimestampzT(args.RevokedAt)).
+		WHERE(postgres.AND(
+			table.ApiKeys.ID.EQ(postgres.UUID(args.ID)),
+			table.ApiKeys.UserID.EQ(postgres.String(args.UserID)),
+			table.ApiKeys.RevokedAt.IS_NULL(),
+		)).
+		Sql()
+
+	tag, err := s.db.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// rebuildCandlesBatchSize is the number of trades folded into candles per query.
+const rebuildCandlesBatchSize = 1000
+
+// upsertCandles merges the candles into the stored ones: the stored open is kept,
+// high and low are widened, close is replaced and volumes are added up. Candles
+// must therefore be merged in the order of their trades.
+func upsertCandles(ctx context.Context, db executor, candles []types.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	candlesTable := table.Candles
+
+	sql, queryArgs := candlesTable.
+		INSERT(candlesTable.AllColumns).
+		MODELS(lo.Map(candles, func(candle types.Candle, _ int) *store_types.Candle {
+			return store_types.MapToCandleStore(&candle)
+		})).
+		ON_CONFLICT(candlesTable.BaseCurrency, candlesTable.QuoteCurrency, candlesTable.Interval, candlesTable.OpenTime).
+		DO_UPDATE(postgres.SET(
+			candlesTable.High.SET(postgres.FloatExp(postgres.GREATEST(candlesTable.High, candlesTable.EXCLUDED.High))),
+			candlesTable.Low.SET(postgres.FloatExp(postgres.LEAST(candlesTable.Low, candlesTable.EXCLUDED.Low))),
+			candlesTable.Close.SET(candlesTable.EXCLUDED.Close),
+			candlesTable.Volume.SET(candlesTable.Volume.ADD(candlesTable.EXCLUDED.Volume)),
+			candlesTable.QuoteVolume.SET(candlesTable.QuoteVolume.ADD(candlesTable.EXCLUDED.QuoteVolume)),
+			candlesTable.TradeCount.SET(candlesTable.TradeCount.ADD(candlesTable.EXCLUDED.TradeCount)),
+			candlesTable.UpdatedAt.SET(candlesTable.EXCLUDED.UpdatedAt),
+		)).
+		Sql()
+
+	if _, err := db.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+// foldCandles aggregates trades, oldest first, into one candle per pair, interval and open time.
+func foldCandles(trades []*types.Trade) []types.Candle {
+	candles := make([]types.Candle, 0)
+	indexes := make(map[string]int)
+
+	for _, trade := range trades {
+		for _, interval := range types.CandleIntervals {
+			candle := types.NewCandle(*trade, interval)
+			key := fmt.Sprintf("%s/%s/%s/%d", candle.BaseCurrency, candle.QuoteCurrency, candle.Interval, candle.OpenTime.Unix())
+
+			if i, ok := indexes[key]; ok {
+				candles[i].Add(*trade)
+				continue
+			}
+
+			indexes[key] = len(candles)
+			candles = append(candles, candle)
+		}
+	}
+
+	return candles
+}
+
+// listTradesAfter returns up to limit trades with IDs greater than the cursor, oldest first.
+func listTradesAfter(ctx context.Context, tx pgx.Tx, cursor uuid.NullUUID, limit int) ([]*types.Trade, error) {
+	query := table.Trades.
+		SELECT(table.Trades.AllColumns)
+
+	if cursor.Valid {
+		query = query.
+			WHERE(table.Trades.ID.GT(postgres.UUID(cursor.UUID)))
+	}
+
+	sql, queryArgs := query.
+		ORDER_BY(table.Trades.ID.ASC()).
+		LIMIT(int64(limit)).
+		Sql()
+
+	trades := []store_types.Trade{}
+	if err := pgxscan.Select(ctx, tx, &trades, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(trades, func(trade store_types.Trade, _ int) *types.Trade {
+		return store_types.MapToTrade(&trade)
+	}), nil
+}
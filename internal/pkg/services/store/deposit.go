@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+	"time"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// SaveDeposit records the incoming transfer, or its new confirmation count, and
+// credits the wallet once the transfer has the required confirmations. A deposit
+// is credited at most once, later reports of its tx hash leave it as it is.
+//
+// The first report inserts the deposit as pending, so concurrent reports of the
+// same tx hash always find its row and wait on the row lock before crediting.
+func (s *Store) SaveDeposit(ctx context.Context, args store_types.SaveDepositArgs) (*types.Deposit, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	transfer := args.Transfer
+	now := time.Now()
+
+	deposit, err := lockDeposit(ctx, tx, transfer.TxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if deposit == nil {
+		if err = insertPendingDeposit(ctx, tx, transfer, now); err != nil {
+			return nil, err
+		}
+
+		deposit, err = lockDeposit(ctx, tx, transfer.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		if deposit == nil {
+			return nil, fmt.Errorf("tx hash %s: %w", transfer.TxHash, ErrNotFound)
+		}
+	}
+
+	if deposit.Requisites.Address != transfer.Address ||
+		deposit.Currency != transfer.Currency ||
+		!deposit.Amount.Equal(transfer.Amount) {
+		return nil, fmt.Errorf("tx hash %s: %w", transfer.TxHash, ErrDepositConflict)
+	}
+
+	if deposit.Status == types.DepositCredited {
+		return deposit, nil
+	}
+
+	deposit.Confirmations = max(deposit.Confirmations, transfer.Confirmations)
+	deposit.UpdatedAt = now
+
+	if deposit.Confirmations >= args.RequiredConfirmations {
+		transaction, err := creditWallet(ctx, tx, store_types.DepositArgs{
+			Address:   deposit.Requisites.Address,
+			Currency:  deposit.Currency,
+			Amount:    deposit.Amount,
+			UpdatedAt: now,
+		}, null.StringFrom(fmt.Sprintf("Deposit %s", deposit.TxHash)))
+		if err != nil {
+			return nil, err
+		}
+
+		deposit.Status = types.DepositCredited
+		deposit.TransactionID = null.StringFrom(transaction.ID)
+		deposit.CreditedAt = null.TimeFrom(now)
+	}
+
+	sql, queryArgs := table.Deposits.
+		UPDATE(
+			table.Deposits.Confirmations,
+			table.Deposits.Status,
+			table.Deposits.TransactionID,
+			table.Deposits.UpdatedAt,
+			table.Deposits.CreditedAt,
+		).
+		MODEL(store_types.MapToDepositStore(deposit)).
+		WHERE(table.Deposits.TxHash.EQ(postgres.String(deposit.TxHash))).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	if deposit.Status == types.DepositCredited {
+		s.notifyBalances(deposit.Requisites.UserID)
+	}
+
+	return deposit, nil
+}
+
+// lockDeposit selects the deposit of the tx hash FOR UPDATE, nil if there is none.
+func lockDeposit(ctx context.Context, tx pgx.Tx, txHash string) (*types.Deposit, error) {
+	sql, queryArgs := table.Deposits.
+		SELECT(table.Deposits.AllColumns).
+		WHERE(table.Deposits.TxHash.EQ(postgres.String(txHash))).
+		FOR(postgres.UPDATE()).
+		Sql()
+
+	deposits := []store_types.Deposit{}
+	if err := pgxscan.Select(ctx, tx, &deposits, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(deposits) == 0 {
+		return nil, nil
+	}
+
+	return store_types.MapToDeposit(&deposits[0]), nil
+}
+
+// insertPendingDeposit records the transfer to its wallet as a pending deposit
+// unless its tx hash is already known. The wallet is only read, not locked, so
+// the deposit row is always locked before the wallet it credits.
+func insertPendingDeposit(ctx context.Context, tx pgx.Tx, transfer types.IncomingTransfer, now time.Time) error {
+	sql, queryArgs := table.Wallets.
+		SELECT(table.Wallets.AllColumns).
+		WHERE(table.Wallets.Address.EQ(postgres.String(transfer.Address))).
+		Sql()
+
+	wallets := []store_types.Wallet{}
+	if err := pgxscan.Select(ctx, tx, &wallets, sql, queryArgs...); err != nil {
+		return fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(wallets) == 0 || wallets[0].Currency != string(transfer.Currency) {
+		return ErrNotFound
+	}
+
+	deposit := &types.Deposit{
+		TxHash: transfer.TxHash,
+		Requisites: types.Requisites{
+			UserID:  wallets[0].UserID,
+			Address: wallets[0].Address,
+		},
+		Currency:  transfer.Currency,
+		Amount:    transfer.Amount,
+		Status:    types.DepositPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	sql, queryArgs = table.Deposits.
+		INSERT(table.Deposits.AllColumns).
+		MODEL(store_types.MapToDepositStore(deposit)).
+		ON_CONFLICT(table.Deposits.TxHash).
+		DO_NOTHING().
+		Sql()
+
+	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) ListDeposits(ctx context.Context, args store_types.ListDepositsArgs) ([]*types.Deposit, error) {
+	query := table.Deposits.
+		SELECT(table.Deposits.AllColumns).
+		WHERE(table.Deposits.UserID.EQ(postgres.String(args.UserID))).
+		ORDER_BY(table.Deposits.CreatedAt.DESC())
+
+	if args.Limit > 0 {
+		query = query.LIMIT(int64(args.Limit))
+	}
+
+	sql, queryArgs := query.Sql()
+
+	deposits := []store_types.Deposit{}
+	if err := pgxscan.Select(ctx, s.db, &deposits, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(deposits, func(deposit store_types.Deposit, _ int) *types.Deposit {
+		return store_types.MapToDeposit(&deposit)
+	}), nil
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/shopspring/decimal"
+	"slices"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+func (s *Store) GetFeeSchedule(ctx context.Context, args store_types.GetFeeScheduleArgs) (*types.FeeSchedule, error) {
+	pair := args.CurrencyPair
+
+	sql, queryArgs := table.FeeSchedules.
+		SELECT(table.FeeSchedules.AllColumns).
+		WHERE(postgres.AND(
+			postgres.OR(
+				postgres.AND(
+					table.FeeSchedules.BaseCurrency.EQ(postgres.String(string(pair.Currency1))),
+					table.FeeSchedules.QuoteCurrency.EQ(postgres.String(string(pair.Currency2))),
+				),
+				postgres.AND(
+					table.FeeSchedules.BaseCurrency.EQ(postgres.String(string(pair.Currency2))),
+					table.FeeSchedules.QuoteCurrency.EQ(postgres.String(string(pair.Currency1))),
+				),
+			),
+			table.FeeSchedules.Tier.IN(
+				postgres.String(string(args.Tier)),
+				postgres.String(string(types.FeeTierStandard)),
+			),
+		)).
+		Sql()
+
+	schedules := []store_types.FeeSchedule{}
+	if err := pgxscan.Select(ctx, s.db, &schedules, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(schedules) == 0 {
+		return nil, ErrNotFound
+	}
+
+	for _, schedule := range schedules {
+		if schedule.Tier == string(args.Tier) {
+			return store_types.MapToFeeSchedule(&schedule), nil
+		}
+	}
+
+	return store_types.MapToFeeSchedule(&schedules[0]), nil
+}
+
+// ListPaidFees sums the trading fees the user paid per currency: the buyer fees
+// in the base currency of the trades they bought and the seller fees in the quote
+// currency of the trades they sold.
+func (s *Store) ListPaidFees(ctx context.Context, args store_types.ListPaidFeesArgs) ([]*types.FeeTotal, error) {
+	buyerPredicates := []postgres.BoolExpression{
+		table.Trades.BuyerUserID.EQ(postgres.String(args.UserID)),
+	}
+	sellerPredicates := []postgres.BoolExpression{
+		table.Trades.SellerUserID.EQ(postgres.String(args.UserID)),
+	}
+
+	if args.From.Valid {
+		from := table.Trades.CreatedAt.GT_EQ(postgres.TimestampzT(args.From.Time))
+		buyerPredicates = append(buyerPredicates, from)
+		sellerPredicates = append(sellerPredicates, from)
+	}
+
+	queries := []postgres.SelectStatement{
+		table.Trades.
+			SELECT(
+				table.Trades.BaseCurrency.AS("currency"),
+				postgres.SUMf(table.Trades.BuyerFee).AS("amount"),
+			).
+			WHERE(postgres.AND(buyerPredicates...)).
+			GROUP_BY(table.Trades.BaseCurrency),
+		table.Trades.
+			SELECT(
+				table.Trades.QuoteCurrency.AS("currency"),
+				postgres.SUMf(table.Trades.SellerFee).AS("amount"),
+			).
+			WHERE(postgres.AND(sellerPredicates...)).
+			GROUP_BY(table.Trades.QuoteCurrency),
+	}
+
+	amounts := make(map[types.Currency]decimal.Decimal)
+	for _, query := range queries {
+		sql, queryArgs := query.Sql()
+
+		totals := []store_types.FeeTotal{}
+		if err := pgxscan.Select(ctx, s.db, &totals, sql, queryArgs...); err != nil {
+			return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+		}
+
+		for _, total := range totals {
+			currency := types.Currency(total.Currency)
+			amounts[currency] = amounts[currency].Add(total.Amount)
+		}
+	}
+
+	feeTotals := make([]*types.FeeTotal, 0, len(amounts))
+	for currency, amount := range amounts {
+		feeTotals = append(feeTotals, &types.FeeTotal{Currency: currency, Amount: amount})
+	}
+
+	slices.SortFunc(feeTotals, func(a, b *types.FeeTotal) int {
+		return cmp.Compare(a.Currency, b.Currency)
+	})
+
+	return feeTotals, nil
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"time"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// lockedBalances returns the sum of active holds for each of the addresses.
+func lockedBalances(ctx context.Context, db pgxscan.Querier, addresses []string) (map[string]decimal.Decimal, error) {
+	if len(addresses) == 0 {
+		return map[string]decimal.Decimal{}, nil
+	}
+
+	sql, queryArgs := table.Holds.
+		SELECT(
+			table.Holds.Address,
+			postgres.SUM(table.Holds.Amount).AS("locked"),
+		).
+		WHERE(postgres.AND(
+			table.Holds.Address.IN(
+				lo.Map(addresses, func(address string, _ int) postgres.Expression {
+					return postgres.String(address)
+				})...,
+			),
+			table.Holds.ReleasedAt.IS_NULL(),
+		)).
+		GROUP_BY(table.Holds.Address).
+		Sql()
+
+	balances := []store_types.LockedBalance{}
+	if err := pgxscan.Select(ctx, db, &balances, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.SliceToMap(balances, func(balance store_types.LockedBalance) (string, decimal.Decimal) {
+		return balance.Address, balance.Locked
+	}), nil
+}
+
+func insertHold(ctx context.Context, tx pgx.Tx, hold types.Hold) error {
+	sql, queryArgs := table.Holds.
+		INSERT(table.Holds.AllColumns).
+		MODEL(store_types.MapToHoldStore(&hold)).
+		Sql()
+
+	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+// consumeHold decreases the hold of the order by the amount spent on a fill.
+func consumeHold(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, amount decimal.Decimal) error {
+	sql, queryArgs := table.Holds.
+		UPDATE(table.Holds.Amount, table.Holds.UpdatedAt).
+		SET(
+			postgres.GREATEST(
+				table.Holds.Amount.SUB(postgres.Decimal(amount.String())),
+				postgres.Decimal(decimal.Zero.String()),
+			),
+			postgres.TimestampzT(time.Now()),
+		).
+		WHERE(postgres.AND(
+			table.Holds.OrderID.EQ(postgres.UUID(orderID)),
+			table.Holds.ReleasedAt.IS_NULL(),
+		)).
+		Sql()
+
+	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+// releaseHold returns the remaining held funds of the order to the available balance.
+func releaseHold(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) error {
+	now := time.Now()
+
+	sql, queryArgs := table.Holds.
+		UPDATE(table.Holds.Amount, table.Holds.UpdatedAt, table.Holds.ReleasedAt).
+		SET(
+			postgres.Decimal(decimal.Zero.String()),
+			postgres.TimestampzT(now),
+			postgres.TimestampzT(now),
+		).
+		WHERE(postgres.AND(
+			table.Holds.OrderID.EQ(postgres.UUID(orderID)),
+			table.Holds.ReleasedAt.IS_NULL(),
+		)).
+		Sql()
+
+	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	return nil
+}
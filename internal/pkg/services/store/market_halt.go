@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/samber/lo"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// SetMarketHalted records a halt of the currency pair or removes it. A pair is
+// halted in at most one orientation, halting it again keeps the first halt time.
+func (s *Store) SetMarketHalted(ctx context.Context, args store_types.SetMarketHaltedArgs) error {
+	pair := args.CurrencyPair
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql, queryArgs := table.MarketHalts.
+		DELETE().
+		WHERE(postgres.AND(
+			table.MarketHalts.BaseCurrency.EQ(postgres.String(string(pair.Currency2))),
+			table.MarketHalts.QuoteCurrency.EQ(postgres.String(string(pair.Currency1))),
+		)).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if args.Halted {
+		sql, queryArgs = table.MarketHalts.
+			INSERT(table.MarketHalts.AllColumns).
+			MODEL(store_types.MapToMarketHaltStore(pair, args.At)).
+			ON_CONFLICT(table.MarketHalts.BaseCurrency, table.MarketHalts.QuoteCurrency).
+			DO_NOTHING().
+			Sql()
+	} else {
+		sql, queryArgs = table.MarketHalts.
+			DELETE().
+			WHERE(postgres.AND(
+				table.MarketHalts.BaseCurrency.EQ(postgres.String(string(pair.Currency1))),
+				table.MarketHalts.QuoteCurrency.EQ(postgres.String(string(pair.Currency2))),
+			)).
+			Sql()
+	}
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// ListHaltedMarkets returns the currency pairs that are halted.
+func (s *Store) ListHaltedMarkets(ctx context.Context) ([]types.CurrencyPair, error) {
+	sql, queryArgs := table.MarketHalts.
+		SELECT(table.MarketHalts.AllColumns).
+		ORDER_BY(table.MarketHalts.HaltedAt.ASC()).
+		Sql()
+
+	halts := []store_types.MarketHalt{}
+	if err := pgxscan.Select(ctx, s.db, &halts, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(halts, func(halt store_types.MarketHalt, _ int) types.CurrencyPair {
+		return store_types.MapToCurrencyPair(&halt)
+	}), nil
+}
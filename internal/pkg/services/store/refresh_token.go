@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+func (s *Store) CreateRefreshToken(ctx context.Context, token types.RefreshToken) error {
+	sql, queryArgs := table.RefreshTokens.
+		INSERT(table.RefreshTokens.AllColumns).
+		MODEL(store_types.MapToRefreshTokenStore(&token)).
+		Sql()
+
+	if _, err := s.db.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetRefreshToken(ctx context.Context, args store_types.GetRefreshTokenArgs) (*types.RefreshToken, error) {
+	predicates := make([]postgres.BoolExpression, 0)
+
+	if args.TokenHash.Valid {
+		predicates = append(predicates, table.RefreshTokens.TokenHash.EQ(postgres.String(args.TokenHash.String)))
+	}
+	if args.AccessJTI.Valid {
+		predicates = append(predicates, table.RefreshTokens.AccessJti.EQ(postgres.UUID(args.AccessJTI.UUID)))
+	}
+
+	if len(predicates) == 0 {
+		return nil, ErrNotFound
+	}
+
+	sql, queryArgs := table.RefreshTokens.
+		SELECT(table.RefreshTokens.AllColumns).
+		WHERE(postgres.AND(predicates...)).
+		Sql()
+
+	tokens := []store_types.RefreshToken{}
+	if err := pgxscan.Select(ctx, s.db, &tokens, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return store_types.MapToRefreshToken(&tokens[0]), nil
+}
+
+// RotateRefreshToken revokes a refresh token and saves its successor atomically.
+// It fails with ErrNotFound when the token is missing or already revoked, so a
+// refresh token can be rotated only once even under concurrent requests.
+func (s *Store) RotateRefreshToken(ctx context.Context, args store_types.RotateRefreshTokenArgs) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = revokeRefreshToken(ctx, tx, args.ID, args.RevokedAt); err != nil {
+		return err
+	}
+
+	sql, queryArgs := table.RefreshTokens.
+		INSERT(table.RefreshTokens.AllColumns).
+		MODEL(store_types.MapToRefreshTokenStore(&args.Next)).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeRefreshToken revokes an active refresh token. It fails with ErrNotFound
+// when the token is missing or already revoked.
+func (s *Store) RevokeRefreshToken(ctx context.Context, args store_types.RevokeRefreshTokenArgs) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = revokeRefreshToken(ctx, tx, args.ID, args.RevokedAt); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return nil
+}
+
+func revokeRefreshToken(ctx context.Context, tx pgx.Tx, id uuid.UUID, revokedAt time.Time) error {
+	sql, queryArgs := table.RefreshTokens.
+		UPDATE(table.RefreshTokens.RevokedAt).
+		SET(postgres.TimestampzT(revokedAt)).
+		WHERE(postgres.AND(
+			table.RefreshTokens.ID.EQ(postgres.UUID(id)),
+			table.RefreshTokens.RevokedAt.IS_NULL(),
+		)).
+		Sql()
+
+	tag, err := tx.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -8,8 +8,12 @@ import (
 	"github.com/go-jet/jet/v2/postgres"
 	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"sync"
 	"time"
 	"vitalik_backend/.gen/vitalik/public/table"
 	"vitalik_backend/internal/dependencies"
@@ -18,12 +22,24 @@ import (
 )
 
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrAlreadyExists = errors.New("already exists")
+	ErrNotFound          = errors.New("not found")
+	ErrAlreadyExists     = errors.New("already exists")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrInvalidAmount     = errors.New("amount must be positive")
+	// ErrDepositConflict is returned when a tx hash is reported again with another recipient or amount.
+	ErrDepositConflict = errors.New("deposit conflicts with the recorded transfer")
 )
 
 type Store struct {
 	db *pgxpool.Pool
+
+	listenersMu      sync.RWMutex
+	balanceListeners []store_types.BalanceListener
+}
+
+// executor is implemented by both the connection pool and transactions.
+type executor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 }
 
 func NewStore(db *pgxpool.Pool) *Store {
@@ -34,6 +50,26 @@ func NewStore(db *pgxpool.Pool) *Store {
 
 var _ dependencies.IStore = (*Store)(nil)
 
+// SubscribeBalances registers a listener called after every committed change of
+// wallet balances or holds with the users whose wallets changed.
+func (s *Store) SubscribeBalances(listener store_types.BalanceListener) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+
+	s.balanceListeners = append(s.balanceListeners, listener)
+}
+
+func (s *Store) notifyBalances(userIDs ...string) {
+	userIDs = lo.Uniq(userIDs)
+
+	s.listenersMu.RLock()
+	defer s.listenersMu.RUnlock()
+
+	for _, listener := range s.balanceListeners {
+		listener(userIDs...)
+	}
+}
+
 func (s *Store) CreateWallet(ctx context.Context, wallet types.Wallet) error {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -112,8 +148,18 @@ func (s *Store) ListWallets(ctx context.Context, args store_types.ListWalletsArg
 		return nil, fmt.Errorf("pgxscan.ScanAll failed: %w", err)
 	}
 
+	locked, err := lockedBalances(ctx, s.db, lo.Map(wallets, func(wallet store_types.Wallet, _ int) string {
+		return wallet.Address
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("lockedBalances failed: %w", err)
+	}
+
 	return lo.Map(wallets, func(wallet store_types.Wallet, _ int) *types.Wallet {
-		return store_types.MapToWallet(wallet)
+		result := store_types.MapToWallet(wallet)
+		result.Locked = locked[wallet.Address]
+		result.Available = result.Balance.Sub(result.Locked)
+		return result
 	}), nil
 }
 
@@ -124,34 +170,35 @@ func (s *Store) Deposit(ctx context.Context, args store_types.DepositArgs) (*typ
 	}
 	defer tx.Rollback(ctx)
 
-	sql, queryArgs := table.Wallets.
-		UPDATE(table.Wallets.Balance, table.Wallets.UpdatedAt).
-		SET(
-			table.Wallets.Balance.ADD(postgres.Float(args.Amount)),
-			postgres.TimestampzT(args.UpdatedAt),
-		).
-		WHERE(table.Wallets.Address.EQ(postgres.String(args.Address))).
-		RETURNING(table.Wallets.AllColumns).
-		Sql()
-
-	fmt.Println(sql, args)
-
-	rows, err := s.db.Query(ctx, sql, queryArgs...)
+	transaction, err := creditWallet(ctx, tx, args, null.StringFrom("Deposit"))
 	if err != nil {
-		return nil, fmt.Errorf("s.db.Query failed: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	wallets := []store_types.Wallet{}
-	if err = pgxscan.ScanAll(&wallets, rows); err != nil {
-		return nil, fmt.Errorf("pgxscan.ScanAll failed: %w", err)
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	s.notifyBalances(transaction.ReceiverRequisites.UserID)
+
+	return transaction, nil
+}
+
+// creditWallet adds the amount to the wallet and records the transaction crediting it.
+func creditWallet(ctx context.Context, tx pgx.Tx, args store_types.DepositArgs, purpose null.String) (*types.Transaction, error) {
+	wallets, err := lockWallets(ctx, tx, args.Address)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(wallets) == 0 {
+	wallet, ok := wallets[args.Address]
+	if !ok || wallet.Currency != string(args.Currency) {
 		return nil, ErrNotFound
 	}
 
-	wallet := wallets[0]
+	if err = updateBalance(ctx, tx, args.Address, wallet.Balance.Add(args.Amount), args.UpdatedAt); err != nil {
+		return nil, err
+	}
 
 	transaction := &types.Transaction{
 		ID: uuid.NewString(),
@@ -161,23 +208,13 @@ func (s *Store) Deposit(ctx context.Context, args store_types.DepositArgs) (*typ
 		},
 		Amount:    args.Amount,
 		Currency:  args.Currency,
-		Purpose:   null.StringFrom("Deposit"),
+		Purpose:   purpose,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	sql, queryArgs = table.Transactions.
-		INSERT(table.Transactions.AllColumns).
-		MODEL(store_types.MapToTransactionStore(transaction)).
-		Sql()
-
-	_, err = tx.Exec(ctx, sql, queryArgs...)
-	if err != nil {
-		return nil, fmt.Errorf("tx.Exec failed: %w", err)
-	}
-
-	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	if err = insertTransaction(ctx, tx, transaction); err != nil {
+		return nil, err
 	}
 
 	return transaction, nil
@@ -249,70 +286,287 @@ func (s *Store) Transfer(ctx context.Context, args store_types.TransferArgs) (*t
 	}
 	defer tx.Rollback(ctx)
 
-	sql, queryArgs := table.Wallets.
-		UPDATE(table.Wallets.Balance, table.Wallets.UpdatedAt).
-		SET(
-			table.Wallets.Balance.SUB(postgres.Float(args.Amount)),
-			postgres.TimestampzT(time.Now()),
-		).
-		WHERE(
-			postgres.AND(
-				table.Wallets.Address.EQ(postgres.String(args.FromAddress)),
-				table.Wallets.Balance.GT_EQ(postgres.Float(args.Amount)),
-				table.Wallets.Currency.EQ(postgres.String(string(args.Currency))),
-			),
-		).
-		RETURNING(table.Wallets.AllColumns).
-		Sql()
+	transaction, err := s.transfer(ctx, tx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	s.notifyBalances(transaction.SenderRequisites.UserID, transaction.ReceiverRequisites.UserID)
+
+	return transaction, nil
+}
+
+// SettleTrade moves both legs of a trade and saves both orders in a single transaction.
+// The funds of each leg are taken from the hold of the corresponding order,
+// and the rest of the hold is released once the order is no longer open. A trade
+// with a leg that is not positive fails with ErrInvalidAmount.
+func (s *Store) SettleTrade(ctx context.Context, args store_types.SettleTradeArgs) (*types.Trade, error) {
+	if !args.BaseTransfer.Amount.IsPositive() || !args.QuoteTransfer.Amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
 
-	rows, err := s.db.Query(ctx, sql, queryArgs...)
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("s.db.Query failed: %w", err)
+		return nil, fmt.Errorf("db.Begin failed: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	wallets := []store_types.Wallet{}
-	if err = pgxscan.ScanAll(&wallets, rows); err != nil {
-		return nil, fmt.Errorf("pgxscan.ScanAll failed: %w", err)
+	if err = consumeHold(ctx, tx, args.SellOrder.ID, args.BaseTransfer.Amount); err != nil {
+		return nil, fmt.Errorf("consumeHold failed: %w", err)
 	}
 
-	if len(wallets) == 0 {
-		return nil, ErrNotFound
+	if err = consumeHold(ctx, tx, args.BuyOrder.ID, args.QuoteTransfer.Amount); err != nil {
+		return nil, fmt.Errorf("consumeHold failed: %w", err)
 	}
 
-	senderWallet := wallets[0]
+	baseTransaction, err := s.transfer(ctx, tx, args.BaseTransfer)
+	if err != nil {
+		return nil, fmt.Errorf("base transfer failed: %w", err)
+	}
 
-	sql, queryArgs = table.Wallets.
-		UPDATE(table.Wallets.Balance, table.Wallets.UpdatedAt).
-		SET(
-			table.Wallets.Balance.ADD(postgres.Float(args.Amount)),
-			postgres.TimestampzT(time.Now()),
-		).
-		WHERE(
-			postgres.AND(
-				table.Wallets.Address.EQ(postgres.String(args.ToAddress)),
-				table.Wallets.Currency.EQ(postgres.String(string(args.Currency))),
+	quoteTransaction, err := s.transfer(ctx, tx, args.QuoteTransfer)
+	if err != nil {
+		return nil, fmt.Errorf("quote transfer failed: %w", err)
+	}
+
+	for _, order := range []types.Order{args.BuyOrder, args.SellOrder} {
+		if !order.Status.IsOpen() {
+			if err = releaseHold(ctx, tx, order.ID); err != nil {
+				return nil, fmt.Errorf("releaseHold failed: %w", err)
+			}
+		}
+
+		if err = saveOrder(ctx, tx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	trade := args.Trade
+	trade.BaseTransactionID = baseTransaction.ID
+	trade.QuoteTransactionID = quoteTransaction.ID
+
+	if err = insertTrade(ctx, tx, trade); err != nil {
+		return nil, fmt.Errorf("insertTrade failed: %w", err)
+	}
+
+	if err = upsertCandles(ctx, tx, foldCandles([]*types.Trade{&trade})); err != nil {
+		return nil, fmt.Errorf("upsertCandles failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	s.notifyBalances(trade.BuyerUserID, trade.SellerUserID)
+
+	return &trade, nil
+}
+
+func (s *Store) ListTrades(ctx context.Context, args store_types.ListTradesArgs) ([]*types.Trade, error) {
+	predicates := make([]postgres.BoolExpression, 0)
+
+	if args.CurrencyPair.Valid {
+		pair := args.CurrencyPair.V
+		predicates = append(predicates,
+			postgres.OR(
+				postgres.AND(
+					table.Trades.BaseCurrency.EQ(postgres.String(string(pair.Currency1))),
+					table.Trades.QuoteCurrency.EQ(postgres.String(string(pair.Currency2))),
+				),
+				postgres.AND(
+					table.Trades.BaseCurrency.EQ(postgres.String(string(pair.Currency2))),
+					table.Trades.QuoteCurrency.EQ(postgres.String(string(pair.Currency1))),
+				),
 			),
-		).
-		RETURNING(table.Wallets.AllColumns).
-		Sql()
+		)
+	}
 
-	rows, err = s.db.Query(ctx, sql, queryArgs...)
+	if args.UserID.Valid {
+		predicates = append(predicates,
+			postgres.OR(
+				table.Trades.BuyerUserID.EQ(postgres.String(args.UserID.String)),
+				table.Trades.SellerUserID.EQ(postgres.String(args.UserID.String)),
+			),
+		)
+	}
+
+	if args.From.Valid {
+		predicates = append(predicates, table.Trades.CreatedAt.GT_EQ(postgres.TimestampzT(args.From.Time)))
+	}
+
+	if args.Cursor.Valid {
+		predicates = append(predicates, table.Trades.ID.LT(postgres.UUID(args.Cursor.UUID)))
+	}
+
+	query := table.Trades.
+		SELECT(table.Trades.AllColumns)
+
+	if len(predicates) > 0 {
+		query = query.
+			WHERE(postgres.AND(predicates...))
+	}
+
+	query = query.ORDER_BY(table.Trades.ID.DESC())
+
+	if args.Limit > 0 {
+		query = query.LIMIT(int64(args.Limit))
+	}
+
+	sql, queryArgs := query.Sql()
+
+	trades := []store_types.Trade{}
+	if err := pgxscan.Select(ctx, s.db, &trades, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(trades, func(trade store_types.Trade, _ int) *types.Trade {
+		return store_types.MapToTrade(&trade)
+	}), nil
+}
+
+func (s *Store) ListCandles(ctx context.Context, args store_types.ListCandlesArgs) ([]*types.Candle, error) {
+	predicates := []postgres.BoolExpression{
+		table.Candles.BaseCurrency.EQ(postgres.String(string(args.CurrencyPair.Currency1))),
+		table.Candles.QuoteCurrency.EQ(postgres.String(string(args.CurrencyPair.Currency2))),
+		table.Candles.Interval.EQ(postgres.String(string(args.Interval))),
+	}
+
+	if args.From.Valid {
+		predicates = append(predicates, table.Candles.OpenTime.GT_EQ(postgres.TimestampzT(args.From.Time)))
+	}
+
+	if args.To.Valid {
+		predicates = append(predicates, table.Candles.OpenTime.LT_EQ(postgres.TimestampzT(args.To.Time)))
+	}
+
+	query := table.Candles.
+		SELECT(table.Candles.AllColumns).
+		WHERE(postgres.AND(predicates...)).
+		ORDER_BY(table.Candles.OpenTime.ASC())
+
+	if args.Limit > 0 {
+		query = query.LIMIT(int64(args.Limit))
+	}
+
+	sql, queryArgs := query.Sql()
+
+	candles := []store_types.Candle{}
+	if err := pgxscan.Select(ctx, s.db, &candles, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(candles, func(candle store_types.Candle, _ int) *types.Candle {
+		return store_types.MapToCandle(&candle)
+	}), nil
+}
+
+// RebuildCandles drops all candles and folds the whole trade history into them
+// again in a single transaction. It returns the number of trades folded.
+func (s *Store) RebuildCandles(ctx context.Context) (int, error) {
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("s.db.Query failed: %w", err)
+		return 0, fmt.Errorf("db.Begin failed: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	wallets = []store_types.Wallet{}
-	if err = pgxscan.ScanAll(&wallets, rows); err != nil {
-		return nil, fmt.Errorf("pgxscan.ScanAll failed: %w", err)
+	sql, queryArgs := table.Candles.DELETE().WHERE(postgres.Bool(true)).Sql()
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return 0, fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	var (
+		cursor uuid.NullUUID
+		count  int
+	)
+	for {
+		trades, err := listTradesAfter(ctx, tx, cursor, rebuildCandlesBatchSize)
+		if err != nil {
+			return 0, fmt.Errorf("listTradesAfter failed: %w", err)
+		}
+		if len(trades) == 0 {
+			break
+		}
+
+		if err = upsertCandles(ctx, tx, foldCandles(trades)); err != nil {
+			return 0, fmt.Errorf("upsertCandles failed: %w", err)
+		}
+
+		count += len(trades)
+		cursor = uuid.NullUUID{UUID: trades[len(trades)-1].ID, Valid: true}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return count, nil
+}
+
+// transfer moves funds between two wallets and records the transaction within tx.
+func (s *Store) transfer(ctx context.Context, tx pgx.Tx, args store_types.TransferArgs) (*types.Transaction, error) {
+	if !args.Amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+
+	addresses := []string{args.FromAddress, args.ToAddress}
+	if args.Fee.IsPositive() {
+		addresses = append(addresses, types.FeeWalletAddress(args.Currency))
+	}
+
+	wallets, err := lockWallets(ctx, tx, addresses...)
+	if err != nil {
+		return nil, err
+	}
+
+	senderWallet, ok := wallets[args.FromAddress]
+	if !ok || senderWallet.Currency != string(args.Currency) {
+		return nil, ErrNotFound
 	}
 
-	if len(wallets) == 0 {
+	receiverWallet, ok := wallets[args.ToAddress]
+	if !ok || receiverWallet.Currency != string(args.Currency) {
 		return nil, ErrNotFound
 	}
 
-	receiverWallet := wallets[0]
+	locked, err := lockedBalances(ctx, tx, []string{senderWallet.Address})
+	if err != nil {
+		return nil, fmt.Errorf("lockedBalances failed: %w", err)
+	}
+
+	if senderWallet.Balance.Sub(locked[senderWallet.Address]).LessThan(args.Amount) {
+		return nil, ErrInsufficientFunds
+	}
+
+	now := time.Now()
+
+	if err = updateBalance(ctx, tx, senderWallet.Address, senderWallet.Balance.Sub(args.Amount), now); err != nil {
+		return nil, err
+	}
+
+	// A transfer to the same wallet must not credit the amount on top of the debit.
+	receiverBalance := receiverWallet.Balance
+	if receiverWallet.Address == senderWallet.Address {
+		receiverBalance = receiverBalance.Sub(args.Amount)
+	}
+
+	if err = updateBalance(ctx, tx, receiverWallet.Address, receiverBalance.Add(args.Amount.Sub(args.Fee)), now); err != nil {
+		return nil, err
+	}
+
+	if args.Fee.IsPositive() {
+		feeWallet, ok := wallets[types.FeeWalletAddress(args.Currency)]
+		if !ok || feeWallet.Currency != string(args.Currency) {
+			return nil, fmt.Errorf("fee wallet of %s: %w", args.Currency, ErrNotFound)
+		}
+
+		if err = updateBalance(ctx, tx, feeWallet.Address, feeWallet.Balance.Add(args.Fee), now); err != nil {
+			return nil, err
+		}
+	}
 
 	transaction := &types.Transaction{
 		ID: uuid.NewString(),
@@ -326,40 +580,201 @@ func (s *Store) Transfer(ctx context.Context, args store_types.TransferArgs) (*t
 		},
 		Amount:    args.Amount,
 		Currency:  args.Currency,
+		Fee:       args.Fee,
 		Purpose:   args.Purpose,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = insertTransaction(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// lockWallets selects the wallets FOR UPDATE in address order,
+// so that concurrent transfers in opposite directions cannot deadlock.
+func lockWallets(ctx context.Context, tx pgx.Tx, addresses ...string) (map[string]store_types.Wallet, error) {
+	sql, queryArgs := table.Wallets.
+		SELECT(table.Wallets.AllColumns).
+		WHERE(table.Wallets.Address.IN(
+			lo.Map(lo.Uniq(addresses), func(address string, _ int) postgres.Expression {
+				return postgres.String(address)
+			})...,
+		)).
+		ORDER_BY(table.Wallets.Address.ASC()).
+		FOR(postgres.UPDATE()).
+		Sql()
+
+	wallets := []store_types.Wallet{}
+	if err := pgxscan.Select(ctx, tx, &wallets, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.KeyBy(wallets, func(wallet store_types.Wallet) string {
+		return wallet.Address
+	}), nil
+}
+
+func updateBalance(ctx context.Context, tx pgx.Tx, address string, balance decimal.Decimal, updatedAt time.Time) error {
+	sql, queryArgs := table.Wallets.
+		UPDATE(table.Wallets.Balance, table.Wallets.UpdatedAt).
+		SET(
+			postgres.Decimal(balance.String()),
+			postgres.TimestampzT(updatedAt),
+		).
+		WHERE(table.Wallets.Address.EQ(postgres.String(address))).
+		Sql()
+
+	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
 	}
 
-	sql, queryArgs = table.Transactions.
+	return nil
+}
+
+func insertTransaction(ctx context.Context, tx pgx.Tx, transaction *types.Transaction) error {
+	sql, queryArgs := table.Transactions.
 		INSERT(table.Transactions.AllColumns).
 		MODEL(store_types.MapToTransactionStore(transaction)).
 		Sql()
 
 	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
-		return nil, fmt.Errorf("tx.Exec failed: %w", err)
+		return fmt.Errorf("tx.Exec failed: %w", err)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	return nil
+}
+
+// PlaceOrder saves a new order together with the hold on its funds,
+// failing with ErrInsufficientFunds if the wallet's available balance is too low.
+func (s *Store) PlaceOrder(ctx context.Context, args store_types.PlaceOrderArgs) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	return transaction, nil
+	wallets, err := lockWallets(ctx, tx, args.Hold.Address)
+	if err != nil {
+		return err
+	}
+
+	wallet, ok := wallets[args.Hold.Address]
+	if !ok || wallet.Currency != string(args.Hold.Currency) {
+		return ErrNotFound
+	}
+
+	locked, err := lockedBalances(ctx, tx, []string{wallet.Address})
+	if err != nil {
+		return fmt.Errorf("lockedBalances failed: %w", err)
+	}
+
+	if wallet.Balance.Sub(locked[wallet.Address]).LessThan(args.Hold.Amount) {
+		return ErrInsufficientFunds
+	}
+
+	if err = insertHold(ctx, tx, args.Hold); err != nil {
+		return fmt.Errorf("insertHold failed: %w", err)
+	}
+
+	if err = saveOrder(ctx, tx, args.Order); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	s.notifyBalances(wallet.UserID)
+
+	return nil
+}
+
+// CancelOrder saves an order taken off the book, cancelled or expired, and releases its hold.
+func (s *Store) CancelOrder(ctx context.Context, order types.Order) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = releaseHold(ctx, tx, order.ID); err != nil {
+		return fmt.Errorf("releaseHold failed: %w", err)
+	}
+
+	if err = saveOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	s.notifyBalances(order.SellRequisites.UserID)
+
+	return nil
 }
 
 func (s *Store) SaveOrder(ctx context.Context, order types.Order) error {
+	return saveOrder(ctx, s.db, order)
+}
+
+func saveOrder(ctx context.Context, db executor, order types.Order) error {
 	sql, queryArgs := table.Orders.
 		INSERT(table.Orders.AllColumns).
 		MODEL(store_types.MapToOrderStore(&order)).
+		ON_CONFLICT(table.Orders.ID).
+		DO_UPDATE(postgres.SET(
+			table.Orders.MutableColumns.SET(postgres.ROW(
+				lo.Map(table.Orders.EXCLUDED.MutableColumns, func(column postgres.Column, _ int) postgres.Expression {
+					return column
+				})...,
+			)),
+		)).
 		Sql()
 
-	if _, err := s.db.Exec(ctx, sql, queryArgs...); err != nil {
-		return fmt.Errorf("s.db.Exec failed: %w", err)
+	if _, err := db.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
 	}
 
 	return nil
 }
 
+func (s *Store) ListOrders(ctx context.Context, args store_types.ListOrdersArgs) ([]*types.Order, error) {
+	predicates := make([]postgres.BoolExpression, 0)
+
+	if len(args.StatusIn) > 0 {
+		predicates = append(predicates, table.Orders.Status.IN(
+			lo.Map(args.StatusIn, func(status types.OrderStatus, _ int) postgres.Expression {
+				return postgres.String(string(status))
+			})...,
+		))
+	}
+
+	query := table.Orders.
+		SELECT(table.Orders.AllColumns)
+
+	if len(predicates) > 0 {
+		query = query.
+			WHERE(postgres.AND(predicates...))
+	}
+
+	query = query.ORDER_BY(table.Orders.CreatedAt.ASC(), table.Orders.ID.ASC())
+
+	sql, queryArgs := query.Sql()
+
+	orders := []store_types.Order{}
+	if err := pgxscan.Select(ctx, s.db, &orders, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.ScanAll failed: %w", err)
+	}
+
+	return lo.Map(orders, func(order store_types.Order, _ int) *types.Order {
+		return store_types.MapToOrder(&order)
+	}), nil
+}
+
 func (s *Store) SaveUser(ctx context.Context, user types.User) error {
 	sql, queryArgs := table.Users.
 		INSERT(table.Users.AllColumns).
@@ -391,3 +806,70 @@ func (s *Store) GetUser(ctx context.Context, userID string) (*types.User, error)
 
 	return store_types.MapFromUserStore(&users[0]), nil
 }
+
+// SetUserPassword changes the password hash of a user and revokes all of the
+// user's active refresh tokens in the same transaction, so sessions opened with
+// the old password end with it. It fails with ErrNotFound when the user does not
+// exist.
+func (s *Store) SetUserPassword(ctx context.Context, args store_types.SetUserPasswordArgs) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql, queryArgs := table.Users.
+		UPDATE(table.Users.HashedPassword).
+		SET(postgres.String(args.HashedPassword)).
+		WHERE(table.Users.UserID.EQ(postgres.String(args.UserID))).
+		Sql()
+
+	tag, err := tx.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	sql, queryArgs = table.RefreshTokens.
+		UPDATE(table.RefreshTokens.RevokedAt).
+		SET(postgres.TimestampzT(args.RevokedAt)).
+		WHERE(postgres.AND(
+			table.RefreshTokens.UserID.EQ(postgres.String(args.UserID)),
+			table.RefreshTokens.RevokedAt.IS_NULL(),
+		)).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// SetUserRole changes the role of a user. It fails with ErrNotFound when the user
+// does not exist.
+func (s *Store) SetUserRole(ctx context.Context, args store_types.SetUserRoleArgs) error {
+	sql, queryArgs := table.Users.
+		UPDATE(table.Users.Role).
+		SET(postgres.String(string(args.Role))).
+		WHERE(table.Users.UserID.EQ(postgres.String(args.UserID))).
+		Sql()
+
+	tag, err := s.db.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("s.db.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,1069 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const migrationsDir = "../../../../db/migrations"
+
+// newTestStore creates a disposable database on the server pointed to by TEST_PG_DSN,
+// applies the migrations to it and drops it when the test finishes.
+func newTestStore(t *testing.T) (*Store, *pgxpool.Pool) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_PG_DSN")
+	if dsn == "" {
+		t.Skip("TEST_PG_DSN is not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	require.NoError(t, err)
+
+	dbName := "vitalik_test_" + randomHex(t, 8)
+	_, err = admin.Exec(ctx, fmt.Sprintf("CREATE DATABASE %s", dbName))
+	require.NoError(t, err)
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	cfg.ConnConfig.Database = dbName
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		pool.Close()
+		_, _ = admin.Exec(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", dbName))
+		_ = admin.Close(ctx)
+	})
+
+	applyMigrations(t, pool)
+
+	return NewStore(pool), pool
+}
+
+// applyMigrations executes the "Up" section of every goose migration in order.
+func applyMigrations(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+		_, err = pool.Exec(context.Background(), up)
+		require.NoError(t, err, "migration %s failed", filepath.Base(file))
+	}
+}
+
+func randomHex(t *testing.T, n int) string {
+	t.Helper()
+
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	return hex.EncodeToString(b)
+}
+
+func createTestWallet(t *testing.T, s *Store, userID string, currency types.Currency, balance string) types.Wallet {
+	t.Helper()
+
+	wallet := types.Wallet{
+		Requisites: types.Requisites{
+			UserID:  userID,
+			Address: randomHex(t, 32),
+		},
+		Currency:  currency,
+		Balance:   decimal.RequireFromString(balance),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, s.CreateWallet(context.Background(), wallet))
+
+	return wallet
+}
+
+func requireBalance(t *testing.T, s *Store, address string, expected string) {
+	t.Helper()
+
+	wallets, err := s.ListWallets(context.Background(), store_types.ListWalletsArgs{AddresssIn: []string{address}})
+	require.NoError(t, err)
+	require.Len(t, wallets, 1)
+	require.Truef(t, wallets[0].Balance.Equal(decimal.RequireFromString(expected)),
+		"balance of %s: want %s, got %s", address, expected, wallets[0].Balance)
+}
+
+// failTransactionInserts makes every following insert into transactions fail.
+func failTransactionInserts(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	_, err := pool.Exec(context.Background(), `
+		CREATE FUNCTION fail_transactions_insert() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'transactions insert failed';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER fail_transactions_insert
+			BEFORE INSERT ON transactions
+			FOR EACH ROW EXECUTE FUNCTION fail_transactions_insert();
+	`)
+	require.NoError(t, err)
+}
+
+func TestTransfer(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	sender := createTestWallet(t, s, "alice", types.BTC, "10")
+	receiver := createTestWallet(t, s, "bob", types.BTC, "0")
+
+	tx, err := s.Transfer(ctx, store_types.TransferArgs{
+		FromAddress: sender.Requisites.Address,
+		ToAddress:   receiver.Requisites.Address,
+		Amount:      decimal.RequireFromString("0.12345678"),
+		Currency:    types.BTC,
+		Purpose:     null.StringFrom("test"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bob", tx.ReceiverRequisites.UserID)
+
+	requireBalance(t, s, sender.Requisites.Address, "9.87654322")
+	requireBalance(t, s, receiver.Requisites.Address, "0.12345678")
+}
+
+func TestTransferInsufficientFunds(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	sender := createTestWallet(t, s, "alice", types.BTC, "1")
+	receiver := createTestWallet(t, s, "bob", types.BTC, "0")
+
+	_, err := s.Transfer(ctx, store_types.TransferArgs{
+		FromAddress: sender.Requisites.Address,
+		ToAddress:   receiver.Requisites.Address,
+		Amount:      decimal.RequireFromString("2"),
+		Currency:    types.BTC,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	requireBalance(t, s, sender.Requisites.Address, "1")
+	requireBalance(t, s, receiver.Requisites.Address, "0")
+}
+
+func TestTransferRollsBackWhenTransactionInsertFails(t *testing.T) {
+	s, pool := newTestStore(t)
+	ctx := context.Background()
+
+	sender := createTestWallet(t, s, "alice", types.BTC, "10")
+	receiver := createTestWallet(t, s, "bob", types.BTC, "0")
+
+	failTransactionInserts(t, pool)
+
+	_, err := s.Transfer(ctx, store_types.TransferArgs{
+		FromAddress: sender.Requisites.Address,
+		ToAddress:   receiver.Requisites.Address,
+		Amount:      decimal.RequireFromString("1"),
+		Currency:    types.BTC,
+	})
+	require.Error(t, err)
+
+	requireBalance(t, s, sender.Requisites.Address, "10")
+	requireBalance(t, s, receiver.Requisites.Address, "0")
+}
+
+func TestDepositRollsBackWhenTransactionInsertFails(t *testing.T) {
+	s, pool := newTestStore(t)
+	ctx := context.Background()
+
+	wallet := createTestWallet(t, s, "alice", types.USDT, "5")
+
+	failTransactionInserts(t, pool)
+
+	_, err := s.Deposit(ctx, store_types.DepositArgs{
+		Address:   wallet.Requisites.Address,
+		Currency:  types.USDT,
+		Amount:    decimal.RequireFromString("1"),
+		UpdatedAt: time.Now(),
+	})
+	require.Error(t, err)
+
+	requireBalance(t, s, wallet.Requisites.Address, "5")
+}
+
+func TestDeposit(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	wallet := createTestWallet(t, s, "alice", types.ETH, "0")
+
+	_, err := s.Deposit(ctx, store_types.DepositArgs{
+		Address:   wallet.Requisites.Address,
+		Currency:  types.ETH,
+		Amount:    decimal.RequireFromString("0.000000000000000001"),
+		UpdatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	requireBalance(t, s, wallet.Requisites.Address, "0.000000000000000001")
+
+	_, err = s.Deposit(ctx, store_types.DepositArgs{
+		Address:   wallet.Requisites.Address,
+		Currency:  types.BTC,
+		Amount:    decimal.RequireFromString("1"),
+		UpdatedAt: time.Now(),
+	})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestConcurrentOppositeTransfers(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	a := createTestWallet(t, s, "alice", types.USDT, "100")
+	b := createTestWallet(t, s, "bob", types.USDT, "100")
+
+	const transfers = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*transfers)
+	for i := 0; i < transfers; i++ {
+		for _, pair := range [][2]types.Wallet{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := s.Transfer(ctx, store_types.TransferArgs{
+					FromAddress: pair[0].Requisites.Address,
+					ToAddress:   pair[1].Requisites.Address,
+					Amount:      decimal.NewFromInt(1),
+					Currency:    types.USDT,
+				})
+				errs <- err
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	requireBalance(t, s, a.Requisites.Address, "100")
+	requireBalance(t, s, b.Requisites.Address, "100")
+}
+
+func TestPlaceOrderHoldsFunds(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	sender := createTestWallet(t, s, "alice", types.BTC, "10")
+	receiver := createTestWallet(t, s, "bob", types.BTC, "0")
+
+	order := types.Order{
+		ID:             uuid.New(),
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.RequireFromString("6")),
+		SellRequisites: sender.Requisites,
+		BuyCurrency:    types.USDT,
+		Price:          decimal.RequireFromString("100"),
+		Type:           types.Sell,
+		Status:         types.OrderOpen,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	hold := types.Hold{
+		OrderID:   order.ID,
+		Address:   sender.Requisites.Address,
+		Currency:  types.BTC,
+		Amount:    order.SellQuantity.Decimal,
+		CreatedAt: order.CreatedAt,
+		UpdatedAt: order.UpdatedAt,
+	}
+	require.NoError(t, s.PlaceOrder(ctx, store_types.PlaceOrderArgs{Order: order, Hold: hold}))
+
+	wallets, err := s.ListWallets(ctx, store_types.ListWalletsArgs{AddresssIn: []string{sender.Requisites.Address}})
+	require.NoError(t, err)
+	require.Len(t, wallets, 1)
+	require.True(t, wallets[0].Locked.Equal(decimal.RequireFromString("6")))
+	require.True(t, wallets[0].Available.Equal(decimal.RequireFromString("4")))
+
+	transferArgs := store_types.TransferArgs{
+		FromAddress: sender.Requisites.Address,
+		ToAddress:   receiver.Requisites.Address,
+		Amount:      decimal.RequireFromString("5"),
+		Currency:    types.BTC,
+	}
+	_, err = s.Transfer(ctx, transferArgs)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	order.Status = types.OrderCancelled
+	require.NoError(t, s.CancelOrder(ctx, order))
+
+	_, err = s.Transfer(ctx, transferArgs)
+	require.NoError(t, err)
+
+	requireBalance(t, s, sender.Requisites.Address, "5")
+}
+
+func TestSettleTradeRecordsTrade(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	sellerBTC := createTestWallet(t, s, "alice", types.BTC, "10")
+	sellerUSDT := createTestWallet(t, s, "alice", types.USDT, "0")
+	buyerBTC := createTestWallet(t, s, "bob", types.BTC, "0")
+	buyerUSDT := createTestWallet(t, s, "bob", types.USDT, "1000")
+
+	now := time.Now()
+	sellOrder := types.Order{
+		ID:             uuid.Must(uuid.NewV7()),
+		Type:           types.Sell,
+		Kind:           types.Limit,
+		TimeInForce:    types.GTC,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(3)),
+		SellRequisites: sellerBTC.Requisites,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  sellerUSDT.Requisites,
+		Status:         types.OrderOpen,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	buyOrder := types.Order{
+		ID:             uuid.Must(uuid.NewV7()),
+		Type:           types.Buy,
+		Kind:           types.Limit,
+		TimeInForce:    types.GTC,
+		SellCurrency:   types.USDT,
+		SellRequisites: buyerUSDT.Requisites,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.BTC,
+		BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(3)),
+		BuyRequisites:  buyerBTC.Requisites,
+		Status:         types.OrderOpen,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	holds := map[uuid.UUID]types.Hold{
+		sellOrder.ID: {
+			OrderID:   sellOrder.ID,
+			Address:   sellerBTC.Requisites.Address,
+			Currency:  types.BTC,
+			Amount:    decimal.NewFromInt(3),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		buyOrder.ID: {
+			OrderID:   buyOrder.ID,
+			Address:   buyerUSDT.Requisites.Address,
+			Currency:  types.USDT,
+			Amount:    decimal.NewFromInt(300),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	for _, order := range []types.Order{sellOrder, buyOrder} {
+		require.NoError(t, s.PlaceOrder(ctx, store_types.PlaceOrderArgs{Order: order, Hold: holds[order.ID]}))
+	}
+
+	const trades = 3
+	for i := 0; i < trades; i++ {
+		trade, err := s.SettleTrade(ctx, store_types.SettleTradeArgs{
+			BuyOrder:  buyOrder,
+			SellOrder: sellOrder,
+			BaseTransfer: store_types.TransferArgs{
+				FromAddress: sellerBTC.Requisites.Address,
+				ToAddress:   buyerBTC.Requisites.Address,
+				Amount:      decimal.NewFromInt(1),
+				Currency:    types.BTC,
+			},
+			QuoteTransfer: store_types.TransferArgs{
+				FromAddress: buyerUSDT.Requisites.Address,
+				ToAddress:   sellerUSDT.Requisites.Address,
+				Amount:      decimal.NewFromInt(100),
+				Currency:    types.USDT,
+			},
+			Trade: types.Trade{
+				ID:            uuid.Must(uuid.NewV7()),
+				BaseCurrency:  types.BTC,
+				QuoteCurrency: types.USDT,
+				BuyOrderID:    buyOrder.ID,
+				SellOrderID:   sellOrder.ID,
+				BuyerUserID:   "bob",
+				SellerUserID:  "alice",
+				Price:         decimal.NewFromInt(100 + int64(i)*5),
+				Quantity:      decimal.NewFromInt(1),
+				QuoteQuantity: decimal.NewFromInt(100),
+				MakerSide:     types.Sell,
+				CreatedAt:     time.Now(),
+			},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, trade.BaseTransactionID)
+		require.NotEmpty(t, trade.QuoteTransactionID)
+	}
+
+	pair := types.CurrencyPair{Currency1: types.USDT, Currency2: types.BTC}
+	firstPage, err := s.ListTrades(ctx, store_types.ListTradesArgs{
+		CurrencyPair: null.ValueFrom(pair),
+		Limit:        2,
+	})
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+	require.Equal(t, -1, bytes.Compare(firstPage[1].ID[:], firstPage[0].ID[:]))
+
+	secondPage, err := s.ListTrades(ctx, store_types.ListTradesArgs{
+		CurrencyPair: null.ValueFrom(pair),
+		Cursor:       uuid.NullUUID{UUID: firstPage[1].ID, Valid: true},
+		Limit:        2,
+	})
+	require.NoError(t, err)
+	require.Len(t, secondPage, 1)
+
+	own, err := s.ListTrades(ctx, store_types.ListTradesArgs{UserID: null.StringFrom("alice")})
+	require.NoError(t, err)
+	require.Len(t, own, trades)
+
+	others, err := s.ListTrades(ctx, store_types.ListTradesArgs{UserID: null.StringFrom("carol")})
+	require.NoError(t, err)
+	require.Empty(t, others)
+
+	requireDailyCandle := func(t *testing.T) {
+		candles, err := s.ListCandles(ctx, store_types.ListCandlesArgs{
+			CurrencyPair: types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT},
+			Interval:     types.Candle1d,
+		})
+		require.NoError(t, err)
+		require.Len(t, candles, 1)
+		require.True(t, candles[0].Open.Equal(decimal.NewFromInt(100)))
+		require.True(t, candles[0].High.Equal(decimal.NewFromInt(110)))
+		require.True(t, candles[0].Low.Equal(decimal.NewFromInt(100)))
+		require.True(t, candles[0].Close.Equal(decimal.NewFromInt(110)))
+		require.True(t, candles[0].Volume.Equal(decimal.NewFromInt(trades)))
+		require.Equal(t, trades, candles[0].TradeCount)
+	}
+
+	requireDailyCandle(t)
+
+	folded, err := s.RebuildCandles(ctx)
+	require.NoError(t, err)
+	require.Equal(t, trades, folded)
+
+	requireDailyCandle(t)
+}
+
+func TestSettleTradeRejectsEmptyLeg(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	seller := createTestWallet(t, s, "alice", types.BTC, "1")
+	buyer := createTestWallet(t, s, "bob", types.BTC, "0")
+
+	_, err := s.SettleTrade(ctx, store_types.SettleTradeArgs{
+		BaseTransfer: store_types.TransferArgs{
+			FromAddress: seller.Requisites.Address,
+			ToAddress:   buyer.Requisites.Address,
+			Amount:      decimal.RequireFromString("0.00000014"),
+			Currency:    types.BTC,
+		},
+		QuoteTransfer: store_types.TransferArgs{Currency: types.USDT},
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = s.Transfer(ctx, store_types.TransferArgs{
+		FromAddress: seller.Requisites.Address,
+		ToAddress:   buyer.Requisites.Address,
+		Currency:    types.BTC,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	requireBalance(t, s, seller.Requisites.Address, "1")
+}
+
+func TestSettleTradeChargesFees(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	sellerBTC := createTestWallet(t, s, "alice", types.BTC, "1")
+	sellerUSDT := createTestWallet(t, s, "alice", types.USDT, "0")
+	buyerBTC := createTestWallet(t, s, "bob", types.BTC, "0")
+	buyerUSDT := createTestWallet(t, s, "bob", types.USDT, "100")
+
+	now := time.Now()
+	sellOrder := types.Order{
+		ID:             uuid.Must(uuid.NewV7()),
+		Type:           types.Sell,
+		Kind:           types.Limit,
+		TimeInForce:    types.GTC,
+		SellCurrency:   types.BTC,
+		SellQuantity:   decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		SellRequisites: sellerBTC.Requisites,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.USDT,
+		BuyRequisites:  sellerUSDT.Requisites,
+		Status:         types.OrderClosed,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	buyOrder := types.Order{
+		ID:             uuid.Must(uuid.NewV7()),
+		Type:           types.Buy,
+		Kind:           types.Limit,
+		TimeInForce:    types.GTC,
+		SellCurrency:   types.USDT,
+		SellRequisites: buyerUSDT.Requisites,
+		Price:          decimal.NewFromInt(100),
+		BuyCurrency:    types.BTC,
+		BuyQuantity:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		BuyRequisites:  buyerBTC.Requisites,
+		Status:         types.OrderClosed,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	trade, err := s.SettleTrade(ctx, store_types.SettleTradeArgs{
+		BuyOrder:  buyOrder,
+		SellOrder: sellOrder,
+		BaseTransfer: store_types.TransferArgs{
+			FromAddress: sellerBTC.Requisites.Address,
+			ToAddress:   buyerBTC.Requisites.Address,
+			Amount:      decimal.NewFromInt(1),
+			Fee:         decimal.RequireFromString("0.001"),
+			Currency:    types.BTC,
+		},
+		QuoteTransfer: store_types.TransferArgs{
+			FromAddress: buyerUSDT.Requisites.Address,
+			ToAddress:   sellerUSDT.Requisites.Address,
+			Amount:      decimal.NewFromInt(100),
+			Fee:         decimal.RequireFromString("0.2"),
+			Currency:    types.USDT,
+		},
+		Trade: types.Trade{
+			ID:            uuid.Must(uuid.NewV7()),
+			BaseCurrency:  types.BTC,
+			QuoteCurrency: types.USDT,
+			BuyOrderID:    buyOrder.ID,
+			SellOrderID:   sellOrder.ID,
+			BuyerUserID:   "bob",
+			SellerUserID:  "alice",
+			Price:         decimal.NewFromInt(100),
+			Quantity:      decimal.NewFromInt(1),
+			QuoteQuantity: decimal.NewFromInt(100),
+			MakerSide:     types.Sell,
+			BuyerFee:      decimal.RequireFromString("0.001"),
+			SellerFee:     decimal.RequireFromString("0.2"),
+			CreatedAt:     now,
+		},
+	})
+	require.NoError(t, err)
+
+	// Each side receives its amount less the fee, the fee wallets receive the rest.
+	requireBalance(t, s, sellerBTC.Requisites.Address, "0")
+	requireBalance(t, s, buyerBTC.Requisites.Address, "0.999")
+	requireBalance(t, s, buyerUSDT.Requisites.Address, "0")
+	requireBalance(t, s, sellerUSDT.Requisites.Address, "99.8")
+	requireBalance(t, s, types.FeeWalletAddress(types.BTC), "0.001")
+	requireBalance(t, s, types.FeeWalletAddress(types.USDT), "0.2")
+
+	transactions, err := s.ListTransactions(ctx, store_types.ListTransactionsArgs{UserIDsIn: []string{"bob"}})
+	require.NoError(t, err)
+	fees := lo.Map(transactions, func(transaction *types.Transaction, _ int) string {
+		return transaction.Fee.String()
+	})
+	require.ElementsMatch(t, []string{"0.001", "0.2"}, fees)
+
+	trades, err := s.ListTrades(ctx, store_types.ListTradesArgs{UserID: null.StringFrom("bob")})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	require.Equal(t, trade.ID, trades[0].ID)
+	require.True(t, trades[0].BuyerFee.Equal(decimal.RequireFromString("0.001")))
+	require.True(t, trades[0].SellerFee.Equal(decimal.RequireFromString("0.2")))
+
+	paid, err := s.ListPaidFees(ctx, store_types.ListPaidFeesArgs{UserID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, paid, 1)
+	require.Equal(t, types.USDT, paid[0].Currency)
+	require.True(t, paid[0].Amount.Equal(decimal.RequireFromString("0.2")))
+}
+
+func TestGetFeeSchedule(t *testing.T) {
+	s, pool := newTestStore(t)
+	ctx := context.Background()
+
+	// The seeded schedules are looked up in either orientation of the pair.
+	schedule, err := s.GetFeeSchedule(ctx, store_types.GetFeeScheduleArgs{
+		CurrencyPair: types.CurrencyPair{Currency1: types.USDT, Currency2: types.BTC},
+		Tier:         types.FeeTierVIP,
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.FeeTierVIP, schedule.Tier)
+	require.True(t, schedule.TakerRate.Equal(decimal.RequireFromString("0.001")))
+
+	_, err = pool.Exec(ctx, `DELETE FROM fee_schedules WHERE tier = 'VIP'`)
+	require.NoError(t, err)
+
+	schedule, err = s.GetFeeSchedule(ctx, store_types.GetFeeScheduleArgs{
+		CurrencyPair: types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT},
+		Tier:         types.FeeTierVIP,
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.FeeTierStandard, schedule.Tier)
+}
+
+func TestWithdrawalHoldsFunds(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	wallet := createTestWallet(t, s, "alice", types.BTC, "10")
+
+	newWithdrawal := func(amount int64) types.Withdrawal {
+		return types.Withdrawal{
+			ID:          uuid.Must(uuid.NewV7()),
+			Requisites:  wallet.Requisites,
+			Currency:    types.BTC,
+			Amount:      decimal.NewFromInt(amount),
+			Destination: "bc1qexternal",
+			Status:      types.WithdrawalPending,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+	}
+
+	completed := newWithdrawal(4)
+	require.NoError(t, s.CreateWithdrawal(ctx, completed))
+	rejected := newWithdrawal(5)
+	require.NoError(t, s.CreateWithdrawal(ctx, rejected))
+
+	// Both withdrawals hold their amounts, so the rest of the balance is too small.
+	require.ErrorIs(t, s.CreateWithdrawal(ctx, newWithdrawal(2)), ErrInsufficientFunds)
+
+	_, err := s.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+		ID:     completed.ID,
+		Status: types.WithdrawalCompleted,
+	})
+	require.ErrorIs(t, err, types.ErrWithdrawalTransition)
+
+	for _, status := range []types.WithdrawalStatus{types.WithdrawalApproved, types.WithdrawalSending, types.WithdrawalSent, types.WithdrawalCompleted} {
+		_, err = s.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+			ID:        completed.ID,
+			Status:    status,
+			Reference: null.StringFrom("payout-1"),
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = s.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+		ID:         rejected.ID,
+		Status:     types.WithdrawalRejected,
+		ReviewedBy: null.StringFrom("admin"),
+	})
+	require.NoError(t, err)
+
+	// The completed withdrawal is debited, the rejected one is released.
+	requireBalance(t, s, wallet.Requisites.Address, "6")
+
+	wallets, err := s.ListWallets(ctx, store_types.ListWalletsArgs{AddresssIn: []string{wallet.Requisites.Address}})
+	require.NoError(t, err)
+	require.True(t, wallets[0].Locked.IsZero())
+
+	stored, err := s.GetWithdrawal(ctx, completed.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.WithdrawalCompleted, stored.Status)
+	require.Equal(t, "payout-1", stored.Reference.String)
+
+	// Only the completed withdrawal leaves a ledger row.
+	transactions, err := s.ListTransactions(ctx, store_types.ListTransactionsArgs{AddresssIn: []string{wallet.Requisites.Address}})
+	require.NoError(t, err)
+	withdrawn := lo.Filter(transactions, func(transaction *types.Transaction, _ int) bool {
+		return transaction.Purpose.String == "Withdrawal"
+	})
+	require.Len(t, withdrawn, 1)
+	require.Equal(t, wallet.Requisites.Address, withdrawn[0].SenderRequisites.Address)
+	require.True(t, withdrawn[0].Amount.Equal(decimal.NewFromInt(4)))
+
+	reviewed, err := s.ListWithdrawals(ctx, store_types.ListWithdrawalsArgs{
+		UserID:   null.StringFrom("alice"),
+		StatusIn: []types.WithdrawalStatus{types.WithdrawalRejected},
+	})
+	require.NoError(t, err)
+	require.Len(t, reviewed, 1)
+	require.Equal(t, rejected.ID, reviewed[0].ID)
+	require.Equal(t, "admin", reviewed[0].ReviewedBy.String)
+}
+
+func TestSaveDepositCreditsOnce(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	wallet := createTestWallet(t, s, "alice", types.BTC, "0")
+	transfer := types.IncomingTransfer{
+		TxHash:        randomHex(t, 32),
+		Address:       wallet.Requisites.Address,
+		Currency:      types.BTC,
+		Amount:        decimal.RequireFromString("1.5"),
+		Confirmations: 1,
+	}
+
+	save := func(transfer types.IncomingTransfer) (*types.Deposit, error) {
+		return s.SaveDeposit(ctx, store_types.SaveDepositArgs{
+			Transfer:              transfer,
+			RequiredConfirmations: 3,
+		})
+	}
+
+	deposit, err := save(transfer)
+	require.NoError(t, err)
+	require.Equal(t, types.DepositPending, deposit.Status)
+	requireBalance(t, s, wallet.Requisites.Address, "0")
+
+	transfer.Confirmations = 3
+	deposit, err = save(transfer)
+	require.NoError(t, err)
+	require.Equal(t, types.DepositCredited, deposit.Status)
+	require.True(t, deposit.TransactionID.Valid)
+	requireBalance(t, s, wallet.Requisites.Address, "1.5")
+
+	// Reporting the transfer again does not credit it twice.
+	transfer.Confirmations = 4
+	_, err = save(transfer)
+	require.NoError(t, err)
+	requireBalance(t, s, wallet.Requisites.Address, "1.5")
+
+	transfer.Amount = decimal.NewFromInt(100)
+	_, err = save(transfer)
+	require.ErrorIs(t, err, ErrDepositConflict)
+
+	transfer.TxHash = randomHex(t, 32)
+	transfer.Address = "unknown"
+	_, err = save(transfer)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	deposits, err := s.ListDeposits(ctx, store_types.ListDepositsArgs{UserID: "alice"})
+	require.NoError(t, err)
+	require.NotEmpty(t, deposits)
+}
+
+func TestConcurrentSaveDepositCreditsOnce(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	wallet := createTestWallet(t, s, "alice", types.BTC, "0")
+	transfer := types.IncomingTransfer{
+		TxHash:        randomHex(t, 32),
+		Address:       wallet.Requisites.Address,
+		Currency:      types.BTC,
+		Amount:        decimal.RequireFromString("1.5"),
+		Confirmations: 3,
+	}
+
+	// Several instances report the same new transfer at once.
+	const reports = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, reports)
+	for i := 0; i < reports; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := s.SaveDeposit(ctx, store_types.SaveDepositArgs{
+				Transfer:              transfer,
+				RequiredConfirmations: 3,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	requireBalance(t, s, wallet.Requisites.Address, "1.5")
+}
+
+func TestRotateRefreshTokenOnce(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	newToken := func() types.RefreshToken {
+		return types.RefreshToken{
+			ID:        uuid.Must(uuid.NewV7()),
+			UserID:    "alice",
+			TokenHash: randomHex(t, 32),
+			AccessJTI: uuid.Must(uuid.NewV7()),
+			CreatedAt: time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	first := newToken()
+	require.NoError(t, s.CreateRefreshToken(ctx, first))
+
+	second := newToken()
+	require.NoError(t, s.RotateRefreshToken(ctx, store_types.RotateRefreshTokenArgs{
+		ID:        first.ID,
+		RevokedAt: time.Now(),
+		Next:      second,
+	}))
+
+	// A revoked token cannot be rotated again.
+	err := s.RotateRefreshToken(ctx, store_types.RotateRefreshTokenArgs{
+		ID:        first.ID,
+		RevokedAt: time.Now(),
+		Next:      newToken(),
+	})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	stored, err := s.GetRefreshToken(ctx, store_types.GetRefreshTokenArgs{
+		AccessJTI: uuid.NullUUID{UUID: first.AccessJTI, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, stored.RevokedAt.Valid)
+
+	require.NoError(t, s.RevokeRefreshToken(ctx, store_types.RevokeRefreshTokenArgs{ID: second.ID, RevokedAt: time.Now()}))
+
+	stored, err = s.GetRefreshToken(ctx, store_types.GetRefreshTokenArgs{TokenHash: null.StringFrom(second.TokenHash)})
+	require.NoError(t, err)
+	require.False(t, stored.IsActive(time.Now()))
+}
+
+func TestSetUserRole(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	user := types.User{
+		UserID:         "alice-" + randomHex(t, 4),
+		HashedPassword: "!",
+		FeeTier:        types.FeeTierStandard,
+		Role:           types.RoleUser,
+	}
+	require.NoError(t, s.SaveUser(ctx, user))
+
+	require.NoError(t, s.SetUserRole(ctx, store_types.SetUserRoleArgs{UserID: user.UserID, Role: types.RoleSupport}))
+
+	stored, err := s.GetUser(ctx, user.UserID)
+	require.NoError(t, err)
+	require.Equal(t, types.RoleSupport, stored.Role)
+
+	err = s.SetUserRole(ctx, store_types.SetUserRoleArgs{UserID: "nobody-" + randomHex(t, 4), Role: types.RoleAdmin})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSetUserPasswordRevokesRefreshTokens(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	user := types.User{
+		UserID:         "alice-" + randomHex(t, 4),
+		HashedPassword: "!",
+		FeeTier:        types.FeeTierStandard,
+		Role:           types.RoleUser,
+	}
+	require.NoError(t, s.SaveUser(ctx, user))
+
+	newToken := func(userID string) types.RefreshToken {
+		return types.RefreshToken{
+			ID:        uuid.Must(uuid.NewV7()),
+			UserID:    userID,
+			TokenHash: randomHex(t, 32),
+			AccessJTI: uuid.Must(uuid.NewV7()),
+			CreatedAt: time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	own := newToken(user.UserID)
+	other := newToken("bob-" + randomHex(t, 4))
+	require.NoError(t, s.CreateRefreshToken(ctx, own))
+	require.NoError(t, s.CreateRefreshToken(ctx, other))
+
+	require.NoError(t, s.SetUserPassword(ctx, store_types.SetUserPasswordArgs{
+		UserID:         user.UserID,
+		HashedPassword: "!!",
+		RevokedAt:      time.Now(),
+	}))
+
+	stored, err := s.GetRefreshToken(ctx, store_types.GetRefreshTokenArgs{TokenHash: null.StringFrom(own.TokenHash)})
+	require.NoError(t, err)
+	require.False(t, stored.IsActive(time.Now()))
+
+	// The tokens of other users are kept.
+	stored, err = s.GetRefreshToken(ctx, store_types.GetRefreshTokenArgs{TokenHash: null.StringFrom(other.TokenHash)})
+	require.NoError(t, err)
+	require.True(t, stored.IsActive(time.Now()))
+
+	err = s.SetUserPassword(ctx, store_types.SetUserPasswordArgs{UserID: "nobody-" + randomHex(t, 4), HashedPassword: "!"})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSetMarketHalted(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+	reverse := types.CurrencyPair{Currency1: types.USDT, Currency2: types.BTC}
+
+	require.NoError(t, s.SetMarketHalted(ctx, store_types.SetMarketHaltedArgs{CurrencyPair: pair, Halted: true, At: time.Now()}))
+	require.NoError(t, s.SetMarketHalted(ctx, store_types.SetMarketHaltedArgs{CurrencyPair: pair, Halted: true, At: time.Now()}))
+
+	halted, err := s.ListHaltedMarkets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{pair}, halted)
+
+	// Resuming the pair in the other orientation removes the halt.
+	require.NoError(t, s.SetMarketHalted(ctx, store_types.SetMarketHaltedArgs{CurrencyPair: reverse, Halted: false}))
+
+	halted, err = s.ListHaltedMarkets(ctx)
+	require.NoError(t, err)
+	require.Empty(t, halted)
+}
+
+func TestTOTPEnrollment(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+	userID := "alice-" + randomHex(t, 4)
+
+	enrollment := types.TOTPEnrollment{UserID: userID, Secret: "PENDING", CreatedAt: time.Now()}
+	require.NoError(t, s.SaveTOTPEnrollment(ctx, enrollment))
+
+	// A pending enrollment is replaced.
+	enrollment.Secret = "SECRET"
+	require.NoError(t, s.SaveTOTPEnrollment(ctx, enrollment))
+
+	codes := []types.RecoveryCode{
+		{ID: uuid.Must(uuid.NewV7()), UserID: userID, CodeHash: randomHex(t, 32), CreatedAt: time.Now()},
+		{ID: uuid.Must(uuid.NewV7()), UserID: userID, CodeHash: randomHex(t, 32), CreatedAt: time.Now()},
+	}
+	require.NoError(t, s.ConfirmTOTPEnrollment(ctx, store_types.ConfirmTOTPEnrollmentArgs{
+		UserID:        userID,
+		Step:          10,
+		ConfirmedAt:   time.Now(),
+		RecoveryCodes: codes,
+	}))
+
+	stored, err := s.GetTOTPEnrollment(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, "SECRET", stored.Secret)
+	require.True(t, stored.IsConfirmed())
+
+	// A confirmed enrollment is kept.
+	require.ErrorIs(t, s.SaveTOTPEnrollment(ctx, enrollment), ErrAlreadyExists)
+
+	// Steps and recovery codes are used once.
+	require.ErrorIs(t, s.UseTOTPStep(ctx, store_types.UseTOTPStepArgs{UserID: userID, Step: 10}), ErrNotFound)
+	require.NoError(t, s.UseTOTPStep(ctx, store_types.UseTOTPStepArgs{UserID: userID, Step: 11}))
+
+	useCode := store_types.UseRecoveryCodeArgs{UserID: userID, CodeHash: codes[0].CodeHash, UsedAt: time.Now()}
+	require.NoError(t, s.UseRecoveryCode(ctx, useCode))
+	require.ErrorIs(t, s.UseRecoveryCode(ctx, useCode), ErrNotFound)
+
+	require.NoError(t, s.DeleteTOTPEnrollment(ctx, userID))
+	_, err = s.GetTOTPEnrollment(ctx, userID)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, s.UseRecoveryCode(ctx, store_types.UseRecoveryCodeArgs{
+		UserID:   userID,
+		CodeHash: codes[1].CodeHash,
+		UsedAt:   time.Now(),
+	}), ErrNotFound)
+}
+
+func TestRevokeAPIKeyOfOwnerOnly(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	key := types.APIKey{
+		ID:         uuid.Must(uuid.NewV7()),
+		UserID:     "alice-" + randomHex(t, 4),
+		Name:       "bot",
+		SecretHash: randomHex(t, 32),
+		Scopes:     []types.APIKeyScope{types.APIKeyScopeRead, types.APIKeyScopeTrade},
+		AllowedIPs: []string{"203.0.113.7", "198.51.100.0/24"},
+		CreatedAt:  time.Now(),
+	}
+	require.NoError(t, s.CreateAPIKey(ctx, key))
+
+	stored, err := s.GetAPIKey(ctx, key.ID)
+	require.NoError(t, err)
+	require.Equal(t, key.Scopes, stored.Scopes)
+	require.Equal(t, key.AllowedIPs, stored.AllowedIPs)
+
+	err = s.RevokeAPIKey(ctx, store_types.RevokeAPIKeyArgs{ID: key.ID, UserID: "mallory", RevokedAt: time.Now()})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.RevokeAPIKey(ctx, store_types.RevokeAPIKeyArgs{ID: key.ID, UserID: key.UserID, RevokedAt: time.Now()}))
+
+	err = s.RevokeAPIKey(ctx, store_types.RevokeAPIKeyArgs{ID: key.ID, UserID: key.UserID, RevokedAt: time.Now()})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	keys, err := s.ListAPIKeys(ctx, key.UserID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.True(t, keys[0].RevokedAt.Valid)
+}
+
+func TestFoldCandles(t *testing.T) {
+	start := time.Date(2024, 11, 27, 12, 0, 0, 0, time.UTC)
+
+	trades := []*types.Trade{
+		{BaseCurrency: types.BTC, QuoteCurrency: types.USDT, Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(1), QuoteQuantity: decimal.NewFromInt(100), CreatedAt: start},
+		{BaseCurrency: types.BTC, QuoteCurrency: types.USDT, Price: decimal.NewFromInt(90), Quantity: decimal.NewFromInt(2), QuoteQuantity: decimal.NewFromInt(180), CreatedAt: start.Add(30 * time.Second)},
+		{BaseCurrency: types.BTC, QuoteCurrency: types.USDT, Price: decimal.NewFromInt(120), Quantity: decimal.NewFromInt(1), QuoteQuantity: decimal.NewFromInt(120), CreatedAt: start.Add(2 * time.Minute)},
+	}
+
+	candles := foldCandles(trades)
+
+	byInterval := make(map[types.CandleInterval][]types.Candle)
+	for _, candle := range candles {
+		byInterval[candle.Interval] = append(byInterval[candle.Interval], candle)
+	}
+
+	require.Len(t, byInterval[types.Candle1m], 2)
+	require.True(t, byInterval[types.Candle1m][0].Low.Equal(decimal.NewFromInt(90)))
+	require.True(t, byInterval[types.Candle1m][0].Close.Equal(decimal.NewFromInt(90)))
+	require.True(t, byInterval[types.Candle1m][0].Volume.Equal(decimal.NewFromInt(3)))
+	require.True(t, byInterval[types.Candle1m][1].OpenTime.Equal(start.Add(2*time.Minute)))
+
+	for _, interval := range []types.CandleInterval{types.Candle5m, types.Candle1h, types.Candle1d} {
+		require.Len(t, byInterval[interval], 1)
+		candle := byInterval[interval][0]
+		require.True(t, candle.Open.Equal(decimal.NewFromInt(100)))
+		require.True(t, candle.High.Equal(decimal.NewFromInt(120)))
+		require.True(t, candle.Low.Equal(decimal.NewFromInt(90)))
+		require.True(t, candle.Close.Equal(decimal.NewFromInt(120)))
+		require.True(t, candle.QuoteVolume.Equal(decimal.NewFromInt(400)))
+		require.Equal(t, 3, candle.TradeCount)
+	}
+}
@@ -0,0 +1,204 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/samber/lo"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// SaveTOTPEnrollment saves a pending enrollment, replacing a pending one of the
+// user. It fails with ErrAlreadyExists when the user has a confirmed enrollment.
+func (s *Store) SaveTOTPEnrollment(ctx context.Context, enrollment types.TOTPEnrollment) error {
+	sql, queryArgs := table.TotpEnrollments.
+		INSERT(table.TotpEnrollments.AllColumns).
+		MODEL(store_types.MapToTOTPEnrollmentStore(&enrollment)).
+		ON_CONFLICT(table.TotpEnrollments.UserID).
+		DO_UPDATE(postgres.SET(
+			table.TotpEnrollments.Secret.SET(table.TotpEnrollments.EXCLUDED.Secret),
+			table.TotpEnrollments.LastStep.SET(table.TotpEnrollments.EXCLUDED.LastStep),
+			table.TotpEnrollments.CreatedAt.SET(table.TotpEnrollments.EXCLUDED.CreatedAt),
+			table.TotpEnrollments.ConfirmedAt.SET(table.TotpEnrollments.EXCLUDED.ConfirmedAt),
+		).WHERE(table.TotpEnrollments.ConfirmedAt.IS_NULL())).
+		Sql()
+
+	tag, err := s.db.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyExists
+	}
+
+	return nil
+}
+
+func (s *Store) GetTOTPEnrollment(ctx context.Context, userID string) (*types.TOTPEnrollment, error) {
+	sql, queryArgs := table.TotpEnrollments.
+		SELECT(table.TotpEnrollments.AllColumns).
+		WHERE(table.TotpEnrollments.UserID.EQ(postgres.String(userID))).
+		Sql()
+
+	enrollments := []store_types.TOTPEnrollment{}
+	if err := pgxscan.Select(ctx, s.db, &enrollments, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(enrollments) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return store_types.MapToTOTPEnrollment(&enrollments[0]), nil
+}
+
+// ConfirmTOTPEnrollment confirms the pending enrollment of the user and replaces
+// its recovery codes atomically. It fails with ErrNotFound when the user has no
+// pending enrollment.
+func (s *Store) ConfirmTOTPEnrollment(ctx context.Context, args store_types.ConfirmTOTPEnrollmentArgs) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql, queryArgs := table.TotpEnrollments.
+		UPDATE(table.TotpEnrollments.LastStep, table.TotpEnrollments.ConfirmedAt).
+		SET(postgres.Int64(args.Step), postgres.TimestampzT(args.ConfirmedAt)).
+		WHERE(postgres.AND(
+			table.TotpEnrollments.UserID.EQ(postgres.String(args.UserID)),
+			table.TotpEnrollments.ConfirmedAt.IS_NULL(),
+		)).
+		Sql()
+
+	tag, err := tx.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	sql, queryArgs = table.TotpRecoveryCodes.
+		DELETE().
+		WHERE(table.TotpRecoveryCodes.UserID.EQ(postgres.String(args.UserID))).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if len(args.RecoveryCodes) > 0 {
+		sql, queryArgs = table.TotpRecoveryCodes.
+			INSERT(table.TotpRecoveryCodes.AllColumns).
+			MODELS(lo.Map(args.RecoveryCodes, func(code types.RecoveryCode, _ int) *store_types.RecoveryCode {
+				return store_types.MapToRecoveryCodeStore(&code)
+			})).
+			Sql()
+
+		if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+			return fmt.Errorf("tx.Exec failed: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep records that a code of the time step was accepted. It fails with
+// ErrNotFound when the user has no confirmed enrollment or a code of that or a
+// later step was already accepted, so a code cannot be replayed.
+func (s *Store) UseTOTPStep(ctx context.Context, args store_types.UseTOTPStepArgs) error {
+	sql, queryArgs := table.TotpEnrollments.
+		UPDATE(table.TotpEnrollments.LastStep).
+		SET(postgres.Int64(args.Step)).
+		WHERE(postgres.AND(
+			table.TotpEnrollments.UserID.EQ(postgres.String(args.UserID)),
+			table.TotpEnrollments.ConfirmedAt.IS_NOT_NULL(),
+			table.TotpEnrollments.LastStep.LT(postgres.Int64(args.Step)),
+		)).
+		Sql()
+
+	tag, err := s.db.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used. It fails with
+// ErrNotFound when the user has no such unused code.
+func (s *Store) UseRecoveryCode(ctx context.Context, args store_types.UseRecoveryCodeArgs) error {
+	sql, queryArgs := table.TotpRecoveryCodes.
+		UPDATE(table.TotpRecoveryCodes.UsedAt).
+		SET(postgres.TimestampzT(args.UsedAt)).
+		WHERE(postgres.AND(
+			table.TotpRecoveryCodes.UserID.EQ(postgres.String(args.UserID)),
+			table.TotpRecoveryCodes.CodeHash.EQ(postgres.String(args.CodeHash)),
+			table.TotpRecoveryCodes.UsedAt.IS_NULL(),
+		)).
+		Sql()
+
+	tag, err := s.db.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("db.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteTOTPEnrollment removes the enrollment of the user and its recovery codes.
+// It fails with ErrNotFound when the user has no enrollment.
+func (s *Store) DeleteTOTPEnrollment(ctx context.Context, userID string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql, queryArgs := table.TotpEnrollments.
+		DELETE().
+		WHERE(table.TotpEnrollments.UserID.EQ(postgres.String(userID))).
+		Sql()
+
+	tag, err := tx.Exec(ctx, sql, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	sql, queryArgs = table.TotpRecoveryCodes.
+		DELETE().
+		WHERE(table.TotpRecoveryCodes.UserID.EQ(postgres.String(userID))).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+func insertTrade(ctx context.Context, tx pgx.Tx, trade types.Trade) error {
+	sql, queryArgs := table.Trades.
+		INSERT(table.Trades.AllColumns).
+		MODEL(store_types.MapToTradeStore(&trade)).
+		Sql()
+
+	if _, err := tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package store_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/samber/lo"
+	"strings"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// APIKey keeps the scopes and the allowed IPs as comma-separated lists.
+type APIKey struct {
+	ID     uuid.UUID `db:"api_keys.id"`
+	UserID string    `db:"api_keys.user_id"`
+	Name   string    `db:"api_keys.name"`
+
+	SecretHash string `db:"api_keys.secret_hash"`
+
+	Scopes     string `db:"api_keys.scopes"`
+	AllowedIps string `db:"api_keys.allowed_ips"`
+
+	CreatedAt time.Time `db:"api_keys.created_at"`
+	RevokedAt null.Time `db:"api_keys.revoked_at"`
+}
+
+func MapToAPIKeyStore(key *types.APIKey) *APIKey {
+	return &APIKey{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		SecretHash: key.SecretHash,
+		Scopes: strings.Join(lo.Map(key.Scopes, func(scope types.APIKeyScope, _ int) string {
+			return string(scope)
+		}), ","),
+		AllowedIps: strings.Join(key.AllowedIPs, ","),
+		CreatedAt:  key.CreatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func MapToAPIKey(keyStore *APIKey) *types.APIKey {
+	return &types.APIKey{
+		ID:         keyStore.ID,
+		UserID:     keyStore.UserID,
+		Name:       keyStore.Name,
+		SecretHash: keyStore.SecretHash,
+		Scopes: lo.Map(splitList(keyStore.Scopes), func(scope string, _ int) types.APIKeyScope {
+			return types.APIKeyScope(scope)
+		}),
+		AllowedIPs: splitList(keyStore.AllowedIps),
+		CreatedAt:  keyStore.CreatedAt,
+		RevokedAt:  keyStore.RevokedAt,
+	}
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
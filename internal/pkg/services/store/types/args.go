@@ -1,7 +1,9 @@
 package store_types
 
 import (
+	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
 	"time"
 	"vitalik_backend/internal/pkg/types"
 )
@@ -14,7 +16,7 @@ type ListWalletsArgs struct {
 type DepositArgs struct {
 	Address   string
 	Currency  types.Currency
-	Amount    float64
+	Amount    decimal.Decimal
 	UpdatedAt time.Time
 }
 
@@ -23,10 +25,170 @@ type ListTransactionsArgs struct {
 	UserIDsIn  []string
 }
 
+// TransferArgs moves Amount from one wallet to another. Fee is withheld from the
+// receiver and credited to the fee wallet of the currency.
 type TransferArgs struct {
 	FromAddress string
 	ToAddress   string
-	Amount      float64
+	Amount      decimal.Decimal
+	Fee         decimal.Decimal
 	Currency    types.Currency
 	Purpose     null.String
 }
+
+type ListOrdersArgs struct {
+	StatusIn []types.OrderStatus
+}
+
+type SettleTradeArgs struct {
+	BuyOrder  types.Order
+	SellOrder types.Order
+
+	BaseTransfer  TransferArgs
+	QuoteTransfer TransferArgs
+
+	// Trade is recorded with the IDs of both transfer transactions.
+	Trade types.Trade
+}
+
+// ListTradesArgs selects trades of a currency pair, of a user or both, newest
+// first. Cursor is the ID of the last trade of the previous page, From the
+// earliest creation time to include.
+type ListTradesArgs struct {
+	CurrencyPair null.Value[types.CurrencyPair]
+	UserID       null.String
+	From         null.Time
+
+	Cursor uuid.NullUUID
+	Limit  int
+}
+
+type PlaceOrderArgs struct {
+	Order types.Order
+	Hold  types.Hold
+}
+
+// ListCandlesArgs selects candles of a currency pair and interval, oldest first.
+// From and To bound the open time of the candles, both inclusive.
+type ListCandlesArgs struct {
+	CurrencyPair types.CurrencyPair
+	Interval     types.CandleInterval
+
+	From  null.Time
+	To    null.Time
+	Limit int
+}
+
+// GetFeeScheduleArgs selects the fee schedule of a currency pair, in either
+// orientation, for a tier. Pairs without a schedule for the tier fall back to
+// the standard one.
+type GetFeeScheduleArgs struct {
+	CurrencyPair types.CurrencyPair
+	Tier         types.FeeTier
+}
+
+// ListPaidFeesArgs selects the trading fees a user paid, optionally since From.
+type ListPaidFeesArgs struct {
+	UserID string
+	From   null.Time
+}
+
+// ListWithdrawalsArgs selects withdrawals of a user, in some statuses or both,
+// newest first. Cursor is the ID of the last withdrawal of the previous page.
+type ListWithdrawalsArgs struct {
+	UserID   null.String
+	StatusIn []types.WithdrawalStatus
+
+	Cursor uuid.NullUUID
+	Limit  int
+}
+
+// TransitionWithdrawalArgs moves a withdrawal to Status. Reference, Reason and
+// ReviewedBy are stored when set.
+type TransitionWithdrawalArgs struct {
+	ID     uuid.UUID
+	Status types.WithdrawalStatus
+
+	Reference  null.String
+	Reason     null.String
+	ReviewedBy null.String
+}
+
+// SaveDepositArgs records an incoming transfer, crediting it once it has
+// RequiredConfirmations.
+type SaveDepositArgs struct {
+	Transfer              types.IncomingTransfer
+	RequiredConfirmations int
+}
+
+// ListDepositsArgs selects the deposits of a user, newest first.
+type ListDepositsArgs struct {
+	UserID string
+	Limit  int
+}
+
+// GetRefreshTokenArgs selects a refresh token by its hash or by the jti of the
+// access token issued with it.
+type GetRefreshTokenArgs struct {
+	TokenHash null.String
+	AccessJTI uuid.NullUUID
+}
+
+// RotateRefreshTokenArgs revokes the refresh token ID and saves Next in its place.
+type RotateRefreshTokenArgs struct {
+	ID        uuid.UUID
+	RevokedAt time.Time
+	Next      types.RefreshToken
+}
+
+type RevokeRefreshTokenArgs struct {
+	ID        uuid.UUID
+	RevokedAt time.Time
+}
+
+// RevokeAPIKeyArgs revokes the API key ID of the user UserID.
+type RevokeAPIKeyArgs struct {
+	ID        uuid.UUID
+	UserID    string
+	RevokedAt time.Time
+}
+
+type SetUserRoleArgs struct {
+	UserID string
+	Role   types.Role
+}
+
+// SetUserPasswordArgs replaces the password hash of the user and revokes the
+// user's refresh tokens at RevokedAt.
+type SetUserPasswordArgs struct {
+	UserID         string
+	HashedPassword string
+	RevokedAt      time.Time
+}
+
+// ConfirmTOTPEnrollmentArgs confirms the pending enrollment of the user with the
+// code of time step Step and replaces the recovery codes of the user.
+type ConfirmTOTPEnrollmentArgs struct {
+	UserID        string
+	Step          int64
+	ConfirmedAt   time.Time
+	RecoveryCodes []types.RecoveryCode
+}
+
+type UseTOTPStepArgs struct {
+	UserID string
+	Step   int64
+}
+
+type UseRecoveryCodeArgs struct {
+	UserID   string
+	CodeHash string
+	UsedAt   time.Time
+}
+
+// SetMarketHaltedArgs halts the currency pair at At, or resumes it when Halted is false.
+type SetMarketHaltedArgs struct {
+	CurrencyPair types.CurrencyPair
+	Halted       bool
+	At           time.Time
+}
@@ -0,0 +1,59 @@
+package store_types
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type Candle struct {
+	BaseCurrency  string    `db:"candles.base_currency"`
+	QuoteCurrency string    `db:"candles.quote_currency"`
+	Interval      string    `db:"candles.interval"`
+	OpenTime      time.Time `db:"candles.open_time"`
+
+	Open   decimal.Decimal `db:"candles.open"`
+	High   decimal.Decimal `db:"candles.high"`
+	Low    decimal.Decimal `db:"candles.low"`
+	Close  decimal.Decimal `db:"candles.close"`
+	Volume decimal.Decimal `db:"candles.volume"`
+
+	QuoteVolume decimal.Decimal `db:"candles.quote_volume"`
+	TradeCount  int32           `db:"candles.trade_count"`
+
+	UpdatedAt time.Time `db:"candles.updated_at"`
+}
+
+func MapToCandleStore(candle *types.Candle) *Candle {
+	return &Candle{
+		BaseCurrency:  string(candle.BaseCurrency),
+		QuoteCurrency: string(candle.QuoteCurrency),
+		Interval:      string(candle.Interval),
+		OpenTime:      candle.OpenTime,
+		Open:          candle.Open,
+		High:          candle.High,
+		Low:           candle.Low,
+		Close:         candle.Close,
+		Volume:        candle.Volume,
+		QuoteVolume:   candle.QuoteVolume,
+		TradeCount:    int32(candle.TradeCount),
+		UpdatedAt:     candle.UpdatedAt,
+	}
+}
+
+func MapToCandle(candleStore *Candle) *types.Candle {
+	return &types.Candle{
+		BaseCurrency:  types.Currency(candleStore.BaseCurrency),
+		QuoteCurrency: types.Currency(candleStore.QuoteCurrency),
+		Interval:      types.CandleInterval(candleStore.Interval),
+		OpenTime:      candleStore.OpenTime,
+		Open:          candleStore.Open,
+		High:          candleStore.High,
+		Low:           candleStore.Low,
+		Close:         candleStore.Close,
+		Volume:        candleStore.Volume,
+		QuoteVolume:   candleStore.QuoteVolume,
+		TradeCount:    int(candleStore.TradeCount),
+		UpdatedAt:     candleStore.UpdatedAt,
+	}
+}
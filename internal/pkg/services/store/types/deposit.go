@@ -0,0 +1,59 @@
+package store_types
+
+import (
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type Deposit struct {
+	TxHash string `db:"deposits.tx_hash"`
+
+	UserID   string          `db:"deposits.user_id"`
+	Address  string          `db:"deposits.address"`
+	Currency string          `db:"deposits.currency"`
+	Amount   decimal.Decimal `db:"deposits.amount"`
+
+	Confirmations int32       `db:"deposits.confirmations"`
+	Status        string      `db:"deposits.status"`
+	TransactionID null.String `db:"deposits.transaction_id"`
+
+	CreatedAt  time.Time `db:"deposits.created_at"`
+	UpdatedAt  time.Time `db:"deposits.updated_at"`
+	CreditedAt null.Time `db:"deposits.credited_at"`
+}
+
+func MapToDepositStore(deposit *types.Deposit) *Deposit {
+	return &Deposit{
+		TxHash:        deposit.TxHash,
+		UserID:        deposit.Requisites.UserID,
+		Address:       deposit.Requisites.Address,
+		Currency:      string(deposit.Currency),
+		Amount:        deposit.Amount,
+		Confirmations: int32(deposit.Confirmations),
+		Status:        string(deposit.Status),
+		TransactionID: deposit.TransactionID,
+		CreatedAt:     deposit.CreatedAt,
+		UpdatedAt:     deposit.UpdatedAt,
+		CreditedAt:    deposit.CreditedAt,
+	}
+}
+
+func MapToDeposit(depositStore *Deposit) *types.Deposit {
+	return &types.Deposit{
+		TxHash: depositStore.TxHash,
+		Requisites: types.Requisites{
+			UserID:  depositStore.UserID,
+			Address: depositStore.Address,
+		},
+		Currency:      types.Currency(depositStore.Currency),
+		Amount:        depositStore.Amount,
+		Confirmations: int(depositStore.Confirmations),
+		Status:        types.DepositStatus(depositStore.Status),
+		TransactionID: depositStore.TransactionID,
+		CreatedAt:     depositStore.CreatedAt,
+		UpdatedAt:     depositStore.UpdatedAt,
+		CreditedAt:    depositStore.CreditedAt,
+	}
+}
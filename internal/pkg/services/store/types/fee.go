@@ -0,0 +1,33 @@
+package store_types
+
+import (
+	"github.com/shopspring/decimal"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type FeeSchedule struct {
+	BaseCurrency  string `db:"fee_schedules.base_currency"`
+	QuoteCurrency string `db:"fee_schedules.quote_currency"`
+	Tier          string `db:"fee_schedules.tier"`
+
+	MakerRate decimal.Decimal `db:"fee_schedules.maker_rate"`
+	TakerRate decimal.Decimal `db:"fee_schedules.taker_rate"`
+}
+
+func MapToFeeSchedule(scheduleStore *FeeSchedule) *types.FeeSchedule {
+	return &types.FeeSchedule{
+		CurrencyPair: types.CurrencyPair{
+			Currency1: types.Currency(scheduleStore.BaseCurrency),
+			Currency2: types.Currency(scheduleStore.QuoteCurrency),
+		},
+		Tier:      types.FeeTier(scheduleStore.Tier),
+		MakerRate: scheduleStore.MakerRate,
+		TakerRate: scheduleStore.TakerRate,
+	}
+}
+
+// FeeTotal is a row of the fees paid by a user, summed per currency.
+type FeeTotal struct {
+	Currency string          `db:"currency"`
+	Amount   decimal.Decimal `db:"amount"`
+}
@@ -0,0 +1,38 @@
+package store_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type Hold struct {
+	OrderID uuid.UUID `db:"holds.order_id"`
+
+	Address  string          `db:"holds.address"`
+	Currency string          `db:"holds.currency"`
+	Amount   decimal.Decimal `db:"holds.amount"`
+
+	CreatedAt  time.Time `db:"holds.created_at"`
+	UpdatedAt  time.Time `db:"holds.updated_at"`
+	ReleasedAt null.Time `db:"holds.released_at"`
+}
+
+type LockedBalance struct {
+	Address string          `db:"holds.address"`
+	Locked  decimal.Decimal `db:"locked"`
+}
+
+func MapToHoldStore(hold *types.Hold) *Hold {
+	return &Hold{
+		OrderID:    hold.OrderID,
+		Address:    hold.Address,
+		Currency:   string(hold.Currency),
+		Amount:     hold.Amount,
+		CreatedAt:  hold.CreatedAt,
+		UpdatedAt:  hold.UpdatedAt,
+		ReleasedAt: hold.ReleasedAt,
+	}
+}
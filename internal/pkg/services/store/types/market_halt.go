@@ -0,0 +1,27 @@
+package store_types
+
+import (
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type MarketHalt struct {
+	BaseCurrency  string    `db:"market_halts.base_currency"`
+	QuoteCurrency string    `db:"market_halts.quote_currency"`
+	HaltedAt      time.Time `db:"market_halts.halted_at"`
+}
+
+func MapToMarketHaltStore(pair types.CurrencyPair, haltedAt time.Time) *MarketHalt {
+	return &MarketHalt{
+		BaseCurrency:  string(pair.Currency1),
+		QuoteCurrency: string(pair.Currency2),
+		HaltedAt:      haltedAt,
+	}
+}
+
+func MapToCurrencyPair(haltStore *MarketHalt) types.CurrencyPair {
+	return types.CurrencyPair{
+		Currency1: types.Currency(haltStore.BaseCurrency),
+		Currency2: types.Currency(haltStore.QuoteCurrency),
+	}
+}
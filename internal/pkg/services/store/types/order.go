@@ -3,6 +3,7 @@ package store_types
 import (
 	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
 	"time"
 	"vitalik_backend/internal/pkg/types"
 )
@@ -11,18 +12,31 @@ type Order struct {
 	ID uuid.UUID `db:"orders.id"`
 
 	Type string `db:"orders.type"`
+	Kind string `db:"orders.kind"`
 
-	SellCurrency string     `db:"orders.sell_currency"`
-	SellQuantity null.Float `db:"orders.sell_quantity"`
-	SellAddress  string     `db:"orders.sell_address"`
-	SellUserID   string     `db:"orders.sell_user_id"`
+	TimeInForce string    `db:"orders.time_in_force"`
+	ExpiresAt   null.Time `db:"orders.expires_at"`
 
-	Price float64 `db:"orders.price"`
+	SellCurrency string              `db:"orders.sell_currency"`
+	SellQuantity decimal.NullDecimal `db:"orders.sell_quantity"`
+	SellAddress  string              `db:"orders.sell_address"`
+	SellUserID   string              `db:"orders.sell_user_id"`
 
-	BuyCurrency string     `db:"orders.buy_currency"`
-	BuyQuantity null.Float `db:"orders.buy_quantity"`
-	BuyAddress  string     `db:"orders.buy_address"`
-	BuyUserID   string     `db:"orders.buy_user_id"`
+	Price decimal.Decimal `db:"orders.price"`
+
+	TriggerPrice decimal.NullDecimal `db:"orders.trigger_price"`
+	TriggeredAt  null.Time           `db:"orders.triggered_at"`
+
+	BuyCurrency string              `db:"orders.buy_currency"`
+	BuyQuantity decimal.NullDecimal `db:"orders.buy_quantity"`
+	BuyAddress  string              `db:"orders.buy_address"`
+	BuyUserID   string              `db:"orders.buy_user_id"`
+
+	OriginalQuantity    decimal.Decimal     `db:"orders.original_quantity"`
+	FilledQuantity      decimal.Decimal     `db:"orders.filled_quantity"`
+	FilledQuoteQuantity decimal.Decimal     `db:"orders.filled_quote_quantity"`
+	RemainingQuantity   decimal.Decimal     `db:"orders.remaining_quantity"`
+	AveragePrice        decimal.NullDecimal `db:"orders.average_price"`
 
 	Status string `db:"orders.status"`
 
@@ -30,26 +44,38 @@ type Order struct {
 	UpdatedAt time.Time `db:"orders.updated_at"`
 	RemovedAt null.Time `db:"orders.removed_at"`
 	ClosedAt  null.Time `db:"orders.closed_at"`
+	ExpiredAt null.Time `db:"orders.expired_at"`
 }
 
 func MapToOrderStore(order *types.Order) *Order {
 	return &Order{
-		ID:           order.ID,
-		Type:         string(order.Type),
-		SellCurrency: string(order.SellCurrency),
-		SellQuantity: order.SellQuantity,
-		SellAddress:  order.SellRequisites.Address,
-		SellUserID:   order.SellRequisites.UserID,
-		Price:        order.Price,
-		BuyCurrency:  string(order.BuyCurrency),
-		BuyQuantity:  order.BuyQuantity,
-		BuyAddress:   order.BuyRequisites.Address,
-		BuyUserID:    order.BuyRequisites.UserID,
-		Status:       string(order.Status),
-		CreatedAt:    order.CreatedAt,
-		UpdatedAt:    order.UpdatedAt,
-		RemovedAt:    order.RemovedAt,
-		ClosedAt:     order.ClosedAt,
+		ID:                  order.ID,
+		Type:                string(order.Type),
+		Kind:                string(order.Kind),
+		TimeInForce:         string(order.TimeInForce),
+		ExpiresAt:           order.ExpiresAt,
+		SellCurrency:        string(order.SellCurrency),
+		SellQuantity:        order.SellQuantity,
+		SellAddress:         order.SellRequisites.Address,
+		SellUserID:          order.SellRequisites.UserID,
+		Price:               order.Price,
+		TriggerPrice:        order.TriggerPrice,
+		TriggeredAt:         order.TriggeredAt,
+		BuyCurrency:         string(order.BuyCurrency),
+		BuyQuantity:         order.BuyQuantity,
+		BuyAddress:          order.BuyRequisites.Address,
+		BuyUserID:           order.BuyRequisites.UserID,
+		OriginalQuantity:    order.OriginalQuantity,
+		FilledQuantity:      order.FilledQuantity,
+		FilledQuoteQuantity: order.FilledQuoteQuantity,
+		RemainingQuantity:   order.RemainingQuantity,
+		AveragePrice:        order.AveragePrice,
+		Status:              string(order.Status),
+		CreatedAt:           order.CreatedAt,
+		UpdatedAt:           order.UpdatedAt,
+		RemovedAt:           order.RemovedAt,
+		ClosedAt:            order.ClosedAt,
+		ExpiredAt:           order.ExpiredAt,
 	}
 }
 
@@ -57,23 +83,34 @@ func MapToOrder(orderStore *Order) *types.Order {
 	return &types.Order{
 		ID:           orderStore.ID,
 		Type:         types.OrderType(orderStore.Type),
+		Kind:         types.OrderKind(orderStore.Kind),
+		TimeInForce:  types.TimeInForce(orderStore.TimeInForce),
+		ExpiresAt:    orderStore.ExpiresAt,
 		SellCurrency: types.Currency(orderStore.SellCurrency),
 		SellQuantity: orderStore.SellQuantity,
 		SellRequisites: types.Requisites{
 			Address: orderStore.SellAddress,
 			UserID:  orderStore.SellUserID,
 		},
-		Price:       orderStore.Price,
-		BuyCurrency: types.Currency(orderStore.BuyCurrency),
-		BuyQuantity: orderStore.BuyQuantity,
+		Price:        orderStore.Price,
+		TriggerPrice: orderStore.TriggerPrice,
+		TriggeredAt:  orderStore.TriggeredAt,
+		BuyCurrency:  types.Currency(orderStore.BuyCurrency),
+		BuyQuantity:  orderStore.BuyQuantity,
 		BuyRequisites: types.Requisites{
 			Address: orderStore.BuyAddress,
 			UserID:  orderStore.BuyUserID,
 		},
-		Status:    types.OrderStatus(orderStore.Status),
-		CreatedAt: orderStore.CreatedAt,
-		UpdatedAt: orderStore.UpdatedAt,
-		RemovedAt: orderStore.RemovedAt,
-		ClosedAt:  orderStore.ClosedAt,
+		OriginalQuantity:    orderStore.OriginalQuantity,
+		FilledQuantity:      orderStore.FilledQuantity,
+		FilledQuoteQuantity: orderStore.FilledQuoteQuantity,
+		RemainingQuantity:   orderStore.RemainingQuantity,
+		AveragePrice:        orderStore.AveragePrice,
+		Status:              types.OrderStatus(orderStore.Status),
+		CreatedAt:           orderStore.CreatedAt,
+		UpdatedAt:           orderStore.UpdatedAt,
+		RemovedAt:           orderStore.RemovedAt,
+		ClosedAt:            orderStore.ClosedAt,
+		ExpiredAt:           orderStore.ExpiredAt,
 	}
 }
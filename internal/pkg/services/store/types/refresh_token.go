@@ -0,0 +1,44 @@
+package store_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type RefreshToken struct {
+	ID     uuid.UUID `db:"refresh_tokens.id"`
+	UserID string    `db:"refresh_tokens.user_id"`
+
+	TokenHash string    `db:"refresh_tokens.token_hash"`
+	AccessJti uuid.UUID `db:"refresh_tokens.access_jti"`
+
+	CreatedAt time.Time `db:"refresh_tokens.created_at"`
+	ExpiresAt time.Time `db:"refresh_tokens.expires_at"`
+	RevokedAt null.Time `db:"refresh_tokens.revoked_at"`
+}
+
+func MapToRefreshTokenStore(token *types.RefreshToken) *RefreshToken {
+	return &RefreshToken{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		AccessJti: token.AccessJTI,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		RevokedAt: token.RevokedAt,
+	}
+}
+
+func MapToRefreshToken(tokenStore *RefreshToken) *types.RefreshToken {
+	return &types.RefreshToken{
+		ID:        tokenStore.ID,
+		UserID:    tokenStore.UserID,
+		TokenHash: tokenStore.TokenHash,
+		AccessJTI: tokenStore.AccessJti,
+		CreatedAt: tokenStore.CreatedAt,
+		ExpiresAt: tokenStore.ExpiresAt,
+		RevokedAt: tokenStore.RevokedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package store_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type TOTPEnrollment struct {
+	UserID string `db:"totp_enrollments.user_id"`
+
+	Secret   string `db:"totp_enrollments.secret"`
+	LastStep int64  `db:"totp_enrollments.last_step"`
+
+	CreatedAt   time.Time `db:"totp_enrollments.created_at"`
+	ConfirmedAt null.Time `db:"totp_enrollments.confirmed_at"`
+}
+
+func MapToTOTPEnrollmentStore(enrollment *types.TOTPEnrollment) *TOTPEnrollment {
+	return &TOTPEnrollment{
+		UserID:      enrollment.UserID,
+		Secret:      enrollment.Secret,
+		LastStep:    enrollment.LastStep,
+		CreatedAt:   enrollment.CreatedAt,
+		ConfirmedAt: enrollment.ConfirmedAt,
+	}
+}
+
+func MapToTOTPEnrollment(enrollmentStore *TOTPEnrollment) *types.TOTPEnrollment {
+	return &types.TOTPEnrollment{
+		UserID:      enrollmentStore.UserID,
+		Secret:      enrollmentStore.Secret,
+		LastStep:    enrollmentStore.LastStep,
+		CreatedAt:   enrollmentStore.CreatedAt,
+		ConfirmedAt: enrollmentStore.ConfirmedAt,
+	}
+}
+
+type RecoveryCode struct {
+	ID     uuid.UUID `db:"totp_recovery_codes.id"`
+	UserID string    `db:"totp_recovery_codes.user_id"`
+
+	CodeHash string `db:"totp_recovery_codes.code_hash"`
+
+	CreatedAt time.Time `db:"totp_recovery_codes.created_at"`
+	UsedAt    null.Time `db:"totp_recovery_codes.used_at"`
+}
+
+func MapToRecoveryCodeStore(code *types.RecoveryCode) *RecoveryCode {
+	return &RecoveryCode{
+		ID:        code.ID,
+		UserID:    code.UserID,
+		CodeHash:  code.CodeHash,
+		CreatedAt: code.CreatedAt,
+		UsedAt:    code.UsedAt,
+	}
+}
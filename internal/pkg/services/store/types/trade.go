@@ -0,0 +1,74 @@
+package store_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type Trade struct {
+	ID uuid.UUID `db:"trades.id"`
+
+	BaseCurrency  string `db:"trades.base_currency"`
+	QuoteCurrency string `db:"trades.quote_currency"`
+
+	BuyOrderID   uuid.UUID `db:"trades.buy_order_id"`
+	SellOrderID  uuid.UUID `db:"trades.sell_order_id"`
+	BuyerUserID  string    `db:"trades.buyer_user_id"`
+	SellerUserID string    `db:"trades.seller_user_id"`
+
+	Price         decimal.Decimal `db:"trades.price"`
+	Quantity      decimal.Decimal `db:"trades.quantity"`
+	QuoteQuantity decimal.Decimal `db:"trades.quote_quantity"`
+	MakerSide     string          `db:"trades.maker_side"`
+	BuyerFee      decimal.Decimal `db:"trades.buyer_fee"`
+	SellerFee     decimal.Decimal `db:"trades.seller_fee"`
+
+	BaseTransactionID  string `db:"trades.base_transaction_id"`
+	QuoteTransactionID string `db:"trades.quote_transaction_id"`
+
+	CreatedAt time.Time `db:"trades.created_at"`
+}
+
+func MapToTradeStore(trade *types.Trade) *Trade {
+	return &Trade{
+		ID:                 trade.ID,
+		BaseCurrency:       string(trade.BaseCurrency),
+		QuoteCurrency:      string(trade.QuoteCurrency),
+		BuyOrderID:         trade.BuyOrderID,
+		SellOrderID:        trade.SellOrderID,
+		BuyerUserID:        trade.BuyerUserID,
+		SellerUserID:       trade.SellerUserID,
+		Price:              trade.Price,
+		Quantity:           trade.Quantity,
+		QuoteQuantity:      trade.QuoteQuantity,
+		MakerSide:          string(trade.MakerSide),
+		BuyerFee:           trade.BuyerFee,
+		SellerFee:          trade.SellerFee,
+		BaseTransactionID:  trade.BaseTransactionID,
+		QuoteTransactionID: trade.QuoteTransactionID,
+		CreatedAt:          trade.CreatedAt,
+	}
+}
+
+func MapToTrade(tradeStore *Trade) *types.Trade {
+	return &types.Trade{
+		ID:                 tradeStore.ID,
+		BaseCurrency:       types.Currency(tradeStore.BaseCurrency),
+		QuoteCurrency:      types.Currency(tradeStore.QuoteCurrency),
+		BuyOrderID:         tradeStore.BuyOrderID,
+		SellOrderID:        tradeStore.SellOrderID,
+		BuyerUserID:        tradeStore.BuyerUserID,
+		SellerUserID:       tradeStore.SellerUserID,
+		Price:              tradeStore.Price,
+		Quantity:           tradeStore.Quantity,
+		QuoteQuantity:      tradeStore.QuoteQuantity,
+		MakerSide:          types.OrderType(tradeStore.MakerSide),
+		BuyerFee:           tradeStore.BuyerFee,
+		SellerFee:          tradeStore.SellerFee,
+		BaseTransactionID:  tradeStore.BaseTransactionID,
+		QuoteTransactionID: tradeStore.QuoteTransactionID,
+		CreatedAt:          tradeStore.CreatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package store_types
 
 import (
 	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
 	"time"
 	"vitalik_backend/internal/pkg/types"
 )
@@ -15,8 +16,9 @@ type Transaction struct {
 	ReceiverAddress string `db:"transactions.receiver_address"`
 	ReceiverUserID  string `db:"transactions.receiver_user_id"`
 
-	Amount   float64 `db:"transactions.amount"`
-	Currency string  `db:"transactions.currency"`
+	Amount   decimal.Decimal `db:"transactions.amount"`
+	Currency string          `db:"transactions.currency"`
+	Fee      decimal.Decimal `db:"transactions.fee"`
 
 	Purpose null.String `db:"transactions.purpose"`
 
@@ -34,6 +36,7 @@ func MapToTransactionStore(tx *types.Transaction) *Transaction {
 		ReceiverUserID:  tx.ReceiverRequisites.UserID,
 		Amount:          tx.Amount,
 		Currency:        string(tx.Currency),
+		Fee:             tx.Fee,
 		Purpose:         tx.Purpose,
 		CreatedAt:       tx.CreatedAt,
 		UpdatedAt:       tx.UpdatedAt,
@@ -54,6 +57,7 @@ func MapToTransaction(txStore *Transaction) *types.Transaction {
 		},
 		Amount:    txStore.Amount,
 		Currency:  types.Currency(txStore.Currency),
+		Fee:       txStore.Fee,
 		Purpose:   txStore.Purpose,
 		CreatedAt: txStore.CreatedAt,
 		UpdatedAt: txStore.UpdatedAt,
@@ -5,12 +5,16 @@ import "vitalik_backend/internal/pkg/types"
 type User struct {
 	UserID         string `db:"users.user_id"`
 	HashedPassword string `db:"users.hashed_password"`
+	FeeTier        string `db:"users.fee_tier"`
+	Role           string `db:"users.role"`
 }
 
 func MapToUserStore(user *types.User) *User {
 	return &User{
 		UserID:         user.UserID,
 		HashedPassword: user.HashedPassword,
+		FeeTier:        string(user.FeeTier),
+		Role:           string(user.Role),
 	}
 }
 
@@ -18,5 +22,7 @@ func MapFromUserStore(user *User) *types.User {
 	return &types.User{
 		UserID:         user.UserID,
 		HashedPassword: user.HashedPassword,
+		FeeTier:        types.FeeTier(user.FeeTier),
+		Role:           types.Role(user.Role),
 	}
 }
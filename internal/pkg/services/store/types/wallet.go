@@ -1,6 +1,7 @@
 package store_types
 
 import (
+	"github.com/shopspring/decimal"
 	"time"
 	"vitalik_backend/internal/pkg/types"
 )
@@ -9,8 +10,8 @@ type Wallet struct {
 	Address string `db:"wallets.address"`
 	UserID  string `db:"wallets.user_id"`
 
-	Currency string  `db:"wallets.currency"`
-	Balance  float64 `db:"wallets.balance"`
+	Currency string          `db:"wallets.currency"`
+	Balance  decimal.Decimal `db:"wallets.balance"`
 
 	CreatedAt time.Time `db:"wallets.created_at"`
 	UpdatedAt time.Time `db:"wallets.updated_at"`
@@ -37,7 +38,12 @@ func MapToWallet(walletStore Wallet) *types.Wallet {
 		},
 		Currency:  types.Currency(walletStore.Currency),
 		Balance:   walletStore.Balance,
+		Available: walletStore.Balance,
 		CreatedAt: walletStore.CreatedAt,
 		UpdatedAt: walletStore.UpdatedAt,
 	}
 }
+
+// BalanceListener is called with the IDs of the users whose balances changed.
+// It runs on the goroutine of the store call, so it must return quickly.
+type BalanceListener func(userIDs ...string)
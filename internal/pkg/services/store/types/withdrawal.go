@@ -0,0 +1,63 @@
+package store_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"time"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type Withdrawal struct {
+	ID uuid.UUID `db:"withdrawals.id"`
+
+	UserID      string          `db:"withdrawals.user_id"`
+	Address     string          `db:"withdrawals.address"`
+	Currency    string          `db:"withdrawals.currency"`
+	Amount      decimal.Decimal `db:"withdrawals.amount"`
+	Destination string          `db:"withdrawals.destination"`
+
+	Status     string      `db:"withdrawals.status"`
+	Reference  null.String `db:"withdrawals.reference"`
+	Reason     null.String `db:"withdrawals.reason"`
+	ReviewedBy null.String `db:"withdrawals.reviewed_by"`
+
+	CreatedAt time.Time `db:"withdrawals.created_at"`
+	UpdatedAt time.Time `db:"withdrawals.updated_at"`
+}
+
+func MapToWithdrawalStore(withdrawal *types.Withdrawal) *Withdrawal {
+	return &Withdrawal{
+		ID:          withdrawal.ID,
+		UserID:      withdrawal.Requisites.UserID,
+		Address:     withdrawal.Requisites.Address,
+		Currency:    string(withdrawal.Currency),
+		Amount:      withdrawal.Amount,
+		Destination: withdrawal.Destination,
+		Status:      string(withdrawal.Status),
+		Reference:   withdrawal.Reference,
+		Reason:      withdrawal.Reason,
+		ReviewedBy:  withdrawal.ReviewedBy,
+		CreatedAt:   withdrawal.CreatedAt,
+		UpdatedAt:   withdrawal.UpdatedAt,
+	}
+}
+
+func MapToWithdrawal(withdrawalStore *Withdrawal) *types.Withdrawal {
+	return &types.Withdrawal{
+		ID: withdrawalStore.ID,
+		Requisites: types.Requisites{
+			UserID:  withdrawalStore.UserID,
+			Address: withdrawalStore.Address,
+		},
+		Currency:    types.Currency(withdrawalStore.Currency),
+		Amount:      withdrawalStore.Amount,
+		Destination: withdrawalStore.Destination,
+		Status:      types.WithdrawalStatus(withdrawalStore.Status),
+		Reference:   withdrawalStore.Reference,
+		Reason:      withdrawalStore.Reason,
+		ReviewedBy:  withdrawalStore.ReviewedBy,
+		CreatedAt:   withdrawalStore.CreatedAt,
+		UpdatedAt:   withdrawalStore.UpdatedAt,
+	}
+}
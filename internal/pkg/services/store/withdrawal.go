@@ -0,0 +1,243 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/samber/lo"
+	"time"
+	"vitalik_backend/.gen/vitalik/public/table"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// CreateWithdrawal saves a pending withdrawal and holds its amount in the wallet.
+func (s *Store) CreateWithdrawal(ctx context.Context, withdrawal types.Withdrawal) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	address := withdrawal.Requisites.Address
+
+	wallets, err := lockWallets(ctx, tx, address)
+	if err != nil {
+		return err
+	}
+
+	wallet, ok := wallets[address]
+	if !ok || wallet.Currency != string(withdrawal.Currency) {
+		return ErrNotFound
+	}
+
+	locked, err := lockedBalances(ctx, tx, []string{address})
+	if err != nil {
+		return fmt.Errorf("lockedBalances failed: %w", err)
+	}
+
+	if wallet.Balance.Sub(locked[address]).LessThan(withdrawal.Amount) {
+		return ErrInsufficientFunds
+	}
+
+	hold := types.Hold{
+		OrderID:   withdrawal.ID,
+		Address:   address,
+		Currency:  withdrawal.Currency,
+		Amount:    withdrawal.Amount,
+		CreatedAt: withdrawal.CreatedAt,
+		UpdatedAt: withdrawal.CreatedAt,
+	}
+	if err = insertHold(ctx, tx, hold); err != nil {
+		return fmt.Errorf("insertHold failed: %w", err)
+	}
+
+	sql, queryArgs := table.Withdrawals.
+		INSERT(table.Withdrawals.AllColumns).
+		MODEL(store_types.MapToWithdrawalStore(&withdrawal)).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	s.notifyBalances(wallet.UserID)
+
+	return nil
+}
+
+// TransitionWithdrawal moves the withdrawal to the next status and settles its
+// hold: a completed withdrawal is debited from the wallet and recorded as a
+// transaction, a rejected or failed one releases the held amount. Transitions
+// the state machine does not allow fail with types.ErrWithdrawalTransition.
+func (s *Store) TransitionWithdrawal(ctx context.Context, args store_types.TransitionWithdrawalArgs) (*types.Withdrawal, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("db.Begin failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	withdrawal, err := getWithdrawal(ctx, tx, args.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !withdrawal.Status.CanTransitionTo(args.Status) {
+		return nil, fmt.Errorf("%s to %s: %w", withdrawal.Status, args.Status, types.ErrWithdrawalTransition)
+	}
+
+	now := time.Now()
+
+	switch args.Status {
+	case types.WithdrawalCompleted:
+		address := withdrawal.Requisites.Address
+
+		wallets, err := lockWallets(ctx, tx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		wallet, ok := wallets[address]
+		if !ok {
+			return nil, ErrNotFound
+		}
+
+		if err = updateBalance(ctx, tx, address, wallet.Balance.Sub(withdrawal.Amount), now); err != nil {
+			return nil, err
+		}
+
+		transaction := &types.Transaction{
+			ID:               uuid.NewString(),
+			SenderRequisites: withdrawal.Requisites,
+			Amount:           withdrawal.Amount,
+			Currency:         withdrawal.Currency,
+			Purpose:          null.StringFrom("Withdrawal"),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if err = insertTransaction(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+
+		if err = releaseHold(ctx, tx, withdrawal.ID); err != nil {
+			return nil, fmt.Errorf("releaseHold failed: %w", err)
+		}
+
+	case types.WithdrawalRejected, types.WithdrawalFailed:
+		if err = releaseHold(ctx, tx, withdrawal.ID); err != nil {
+			return nil, fmt.Errorf("releaseHold failed: %w", err)
+		}
+	}
+
+	withdrawal.Status = args.Status
+	withdrawal.UpdatedAt = now
+	if args.Reference.Valid {
+		withdrawal.Reference = args.Reference
+	}
+	if args.Reason.Valid {
+		withdrawal.Reason = args.Reason
+	}
+	if args.ReviewedBy.Valid {
+		withdrawal.ReviewedBy = args.ReviewedBy
+	}
+
+	sql, queryArgs := table.Withdrawals.
+		UPDATE(table.Withdrawals.MutableColumns).
+		MODEL(store_types.MapToWithdrawalStore(withdrawal)).
+		WHERE(table.Withdrawals.ID.EQ(postgres.UUID(withdrawal.ID))).
+		Sql()
+
+	if _, err = tx.Exec(ctx, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("tx.Exec failed: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tx.Commit failed: %w", err)
+	}
+
+	if withdrawal.Status.IsFinal() {
+		s.notifyBalances(withdrawal.Requisites.UserID)
+	}
+
+	return withdrawal, nil
+}
+
+func (s *Store) GetWithdrawal(ctx context.Context, id uuid.UUID) (*types.Withdrawal, error) {
+	return getWithdrawal(ctx, s.db, id, false)
+}
+
+func (s *Store) ListWithdrawals(ctx context.Context, args store_types.ListWithdrawalsArgs) ([]*types.Withdrawal, error) {
+	predicates := make([]postgres.BoolExpression, 0)
+
+	if args.UserID.Valid {
+		predicates = append(predicates, table.Withdrawals.UserID.EQ(postgres.String(args.UserID.String)))
+	}
+
+	if len(args.StatusIn) > 0 {
+		predicates = append(predicates, table.Withdrawals.Status.IN(
+			lo.Map(args.StatusIn, func(status types.WithdrawalStatus, _ int) postgres.Expression {
+				return postgres.String(string(status))
+			})...,
+		))
+	}
+
+	if args.Cursor.Valid {
+		predicates = append(predicates, table.Withdrawals.ID.LT(postgres.UUID(args.Cursor.UUID)))
+	}
+
+	query := table.Withdrawals.
+		SELECT(table.Withdrawals.AllColumns)
+
+	if len(predicates) > 0 {
+		query = query.
+			WHERE(postgres.AND(predicates...))
+	}
+
+	query = query.ORDER_BY(table.Withdrawals.ID.DESC())
+
+	if args.Limit > 0 {
+		query = query.LIMIT(int64(args.Limit))
+	}
+
+	sql, queryArgs := query.Sql()
+
+	withdrawals := []store_types.Withdrawal{}
+	if err := pgxscan.Select(ctx, s.db, &withdrawals, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	return lo.Map(withdrawals, func(withdrawal store_types.Withdrawal, _ int) *types.Withdrawal {
+		return store_types.MapToWithdrawal(&withdrawal)
+	}), nil
+}
+
+// getWithdrawal selects the withdrawal, FOR UPDATE when forUpdate is set.
+func getWithdrawal(ctx context.Context, db pgxscan.Querier, id uuid.UUID, forUpdate bool) (*types.Withdrawal, error) {
+	query := table.Withdrawals.
+		SELECT(table.Withdrawals.AllColumns).
+		WHERE(table.Withdrawals.ID.EQ(postgres.UUID(id)))
+
+	if forUpdate {
+		query = query.FOR(postgres.UPDATE())
+	}
+
+	sql, queryArgs := query.Sql()
+
+	withdrawals := []store_types.Withdrawal{}
+	if err := pgxscan.Select(ctx, db, &withdrawals, sql, queryArgs...); err != nil {
+		return nil, fmt.Errorf("pgxscan.Select failed: %w", err)
+	}
+
+	if len(withdrawals) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return store_types.MapToWithdrawal(&withdrawals[0]), nil
+}
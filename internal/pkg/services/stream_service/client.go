@@ -0,0 +1,108 @@
+package stream_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/guregu/null/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// clientSendBuffer is the number of messages queued for a client before it is
+	// considered too slow and disconnected.
+	clientSendBuffer = 256
+	// clientRequestBuffer is the number of requests of a client queued for the run
+	// goroutine before it is considered flooding and disconnected.
+	clientRequestBuffer = 64
+
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxRequestSize = 4096
+)
+
+// client is a single WebSocket connection. Its send channel and topics are owned
+// by the run goroutine of the service; the read and write pumps only touch conn.
+// pending counts its queued requests and is guarded by the queue mutex.
+type client struct {
+	conn   *websocket.Conn
+	userID null.String
+
+	pending int
+
+	send   chan []byte
+	topics map[string]struct{}
+	closed bool
+}
+
+func newClient(conn *websocket.Conn, userID null.String) *client {
+	return &client{
+		conn:   conn,
+		userID: userID,
+		send:   make(chan []byte, clientSendBuffer),
+		topics: make(map[string]struct{}),
+	}
+}
+
+// readPump queues the requests of the client until the connection fails or is closed.
+func (s *StreamService) readPump(c *client) {
+	defer func() {
+		s.enqueue(event{kind: eventDisconnect, client: c})
+		_ = c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxRequestSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, payload, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		e := event{kind: eventRequest, client: c}
+		if err = json.Unmarshal(payload, &e.request); err != nil {
+			e.err = fmt.Errorf("invalid request: %w", err)
+		}
+
+		if !s.enqueueRequest(e) {
+			s.logger.Warn("stream client sends too many requests, disconnecting", zap.String("user_id", c.userID.String))
+			return
+		}
+	}
+}
+
+// writePump writes queued messages and keeps the connection alive with pings.
+// It sends a close frame once the service closes the send channel.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
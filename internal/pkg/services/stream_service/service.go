@@ -0,0 +1,549 @@
+package stream_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/guregu/null/v5"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	stream_service_types "vitalik_backend/internal/pkg/services/stream_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type eventKind int
+
+const (
+	eventConnect eventKind = iota
+	eventDisconnect
+	eventRequest
+	eventTrade
+	eventOrder
+	eventBalances
+)
+
+type event struct {
+	kind eventKind
+
+	client  *client
+	request stream_service_types.Request
+	err     error
+
+	trade   types.Trade
+	order   types.Order
+	userIDs []string
+}
+
+// StreamService streams order book, trade and ticker changes of currency pairs and
+// order and wallet changes of users to WebSocket clients.
+//
+// Trades, orders and balance changes reported by the order book manager and the
+// store are queued without blocking, so the reporting goroutine is never held up,
+// and handled together with client requests by a single run goroutine. That
+// goroutine owns all topics and clients, so the updates of every topic are
+// published in order and always follow its snapshot.
+type StreamService struct {
+	store            dependencies.IStore
+	orderBookManager dependencies.IOrderBookManager
+	tickerService    dependencies.ITickerService
+	logger           *zap.Logger
+
+	upgrader websocket.Upgrader
+
+	queueMu sync.Mutex
+	queue   []event
+	stopped bool
+	wake    chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	topics  map[string]*topic
+	clients map[*client]struct{}
+}
+
+func NewStreamService(
+	store dependencies.IStore,
+	orderBookManager dependencies.IOrderBookManager,
+	tickerService dependencies.ITickerService,
+	logger *zap.Logger,
+) (*StreamService, error) {
+	if store == nil || orderBookManager == nil || tickerService == nil || logger == nil {
+		return nil, errors.New("failed to initialize stream service")
+	}
+
+	return &StreamService{
+		store:            store,
+		orderBookManager: orderBookManager,
+		tickerService:    tickerService,
+		logger:           logger,
+		upgrader: websocket.Upgrader{
+			// Origins are not restricted, the same as for the REST API.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		topics:  make(map[string]*topic),
+		clients: make(map[*client]struct{}),
+	}, nil
+}
+
+var _ dependencies.IStreamService = (*StreamService)(nil)
+
+// Start subscribes to trades, orders and balance changes and starts the run goroutine.
+func (s *StreamService) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.orderBookManager.SubscribeTrades(func(trade types.Trade) {
+		s.enqueue(event{kind: eventTrade, trade: trade})
+	})
+	s.orderBookManager.SubscribeOrders(func(order types.Order) {
+		s.enqueue(event{kind: eventOrder, order: order})
+	})
+	s.store.SubscribeBalances(func(userIDs ...string) {
+		s.enqueue(event{kind: eventBalances, userIDs: userIDs})
+	})
+
+	go s.run(ctx)
+
+	return nil
+}
+
+// Stop disconnects all clients and waits for the run goroutine to finish.
+func (s *StreamService) Stop() {
+	s.queueMu.Lock()
+	s.stopped = true
+	s.queue = nil
+	s.queueMu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+}
+
+// ServeWS upgrades the request to a WebSocket connection and serves it until it
+// is closed. Private channels are available when userID is set.
+func (s *StreamService) ServeWS(w http.ResponseWriter, r *http.Request, userID null.String) error {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return fmt.Errorf("upgrader.Upgrade failed: %w", err)
+	}
+
+	c := newClient(conn, userID)
+	s.enqueue(event{kind: eventConnect, client: c})
+
+	go c.writePump()
+	s.readPump(c)
+
+	return nil
+}
+
+func (s *StreamService) enqueue(e event) {
+	s.queueMu.Lock()
+	if s.stopped {
+		s.queueMu.Unlock()
+		return
+	}
+	s.queue = append(s.queue, e)
+	s.queueMu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueueRequest queues a request of the client unless it already has
+// clientRequestBuffer requests queued, and reports whether it did.
+func (s *StreamService) enqueueRequest(e event) bool {
+	s.queueMu.Lock()
+	if e.client.pending >= clientRequestBuffer {
+		s.queueMu.Unlock()
+		return false
+	}
+	e.client.pending++
+	s.queueMu.Unlock()
+
+	s.enqueue(e)
+
+	return true
+}
+
+func (s *StreamService) dequeue() []event {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	events := s.queue
+	s.queue = nil
+
+	for _, e := range events {
+		if e.kind == eventRequest {
+			e.client.pending--
+		}
+	}
+
+	return events
+}
+
+func (s *StreamService) run(ctx context.Context) {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for c := range s.clients {
+				s.dropClient(c)
+			}
+			return
+		case <-s.wake:
+		}
+
+		s.handleEvents(ctx, s.dequeue())
+	}
+}
+
+// handleEvents handles a batch of events. Depth, ticker and wallet changes are
+// read once per batch for every pair and user the batch touched.
+func (s *StreamService) handleEvents(ctx context.Context, events []event) {
+	pairs := make(map[string]types.CurrencyPair)
+	userIDs := make(map[string]struct{})
+
+	for _, e := range events {
+		switch e.kind {
+		case eventConnect:
+			s.clients[e.client] = struct{}{}
+
+		case eventDisconnect:
+			s.dropClient(e.client)
+
+		case eventRequest:
+			s.handleRequest(ctx, e.client, e.request, e.err)
+
+		case eventTrade:
+			pair := canonicalPair(types.CurrencyPair{Currency1: e.trade.BaseCurrency, Currency2: e.trade.QuoteCurrency})
+			pairs[pair.String()] = pair
+
+			if t, ok := s.topics[topicKey(stream_service_types.ChannelTrades, pair, "")]; ok && t.addTrade(e.trade) {
+				s.publish(t, e.trade)
+			}
+
+		case eventOrder:
+			pair := canonicalPair(types.CurrencyPair{Currency1: e.order.SellCurrency, Currency2: e.order.BuyCurrency})
+			pairs[pair.String()] = pair
+
+			for _, userID := range lo.Uniq([]string{e.order.SellRequisites.UserID, e.order.BuyRequisites.UserID}) {
+				if t, ok := s.topics[topicKey(stream_service_types.ChannelOrders, types.CurrencyPair{}, userID)]; ok {
+					t.setOrder(e.order)
+					s.publish(t, e.order)
+				}
+			}
+
+		case eventBalances:
+			for _, userID := range e.userIDs {
+				userIDs[userID] = struct{}{}
+			}
+		}
+	}
+
+	for _, pair := range pairs {
+		s.refreshDepth(ctx, pair)
+		s.refreshTicker(ctx, pair)
+	}
+
+	for userID := range userIDs {
+		s.refreshWallets(ctx, userID)
+	}
+}
+
+func (s *StreamService) handleRequest(ctx context.Context, c *client, request stream_service_types.Request, err error) {
+	if c.closed {
+		return
+	}
+
+	if err != nil {
+		s.send(c, stream_service_types.Message{Type: stream_service_types.MessageError, Message: err.Error()})
+		return
+	}
+
+	t, err := s.requestTopic(c, request)
+	if err != nil {
+		s.send(c, stream_service_types.Message{
+			Type:    stream_service_types.MessageError,
+			Channel: request.Channel,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	switch request.Op {
+	case stream_service_types.OpSubscribe:
+		if err = s.subscribe(ctx, c, t); err != nil {
+			s.logger.Error("subscribe failed", zap.String("topic", t.key), zap.Error(err))
+			s.send(c, stream_service_types.Message{
+				Type:    stream_service_types.MessageError,
+				Channel: request.Channel,
+				Message: "failed to subscribe",
+			})
+		}
+
+	case stream_service_types.OpUnsubscribe:
+		s.unsubscribe(c, t.key)
+		s.send(c, message(t, stream_service_types.MessageUnsubscribed, nil))
+	}
+}
+
+// requestTopic validates the request and returns its topic, existing or new.
+func (s *StreamService) requestTopic(c *client, request stream_service_types.Request) (*topic, error) {
+	if request.Op != stream_service_types.OpSubscribe && request.Op != stream_service_types.OpUnsubscribe {
+		return nil, errors.New("invalid op")
+	}
+
+	if !request.Channel.Validate() {
+		return nil, errors.New("invalid channel")
+	}
+
+	var (
+		pair   types.CurrencyPair
+		userID string
+	)
+	if request.Channel.IsPrivate() {
+		if !c.userID.Valid {
+			return nil, errors.New("channel requires authentication")
+		}
+		userID = c.userID.String
+	} else {
+		if !request.CurrencyPair.Valid || !request.CurrencyPair.V.Validate() {
+			return nil, errors.New("invalid currency_pair")
+		}
+		pair = canonicalPair(request.CurrencyPair.V)
+	}
+
+	key := topicKey(request.Channel, pair, userID)
+	if t, ok := s.topics[key]; ok {
+		return t, nil
+	}
+
+	// The snapshot of a new topic has sequence 1 and its first update 2.
+	return &topic{
+		key:      key,
+		channel:  request.Channel,
+		pair:     pair,
+		userID:   userID,
+		sequence: 1,
+		clients:  make(map[*client]struct{}),
+	}, nil
+}
+
+// subscribe adds the client to the topic and sends it the snapshot. A topic without
+// subscribers loads its state first. Subscribing again resends the snapshot.
+func (s *StreamService) subscribe(ctx context.Context, c *client, t *topic) error {
+	if _, ok := s.topics[t.key]; !ok {
+		if err := s.load(ctx, t); err != nil {
+			return err
+		}
+		s.topics[t.key] = t
+	}
+
+	t.clients[c] = struct{}{}
+	c.topics[t.key] = struct{}{}
+
+	s.send(c, message(t, stream_service_types.MessageSnapshot, t.snapshot()))
+
+	return nil
+}
+
+// unsubscribe removes the client from the topic and drops the topic once it has no subscribers.
+func (s *StreamService) unsubscribe(c *client, key string) {
+	delete(c.topics, key)
+
+	t, ok := s.topics[key]
+	if !ok {
+		return
+	}
+
+	delete(t.clients, c)
+	if len(t.clients) == 0 {
+		delete(s.topics, key)
+	}
+}
+
+func (s *StreamService) refreshDepth(ctx context.Context, pair types.CurrencyPair) {
+	t, ok := s.topics[topicKey(stream_service_types.ChannelDepth, pair, "")]
+	if !ok {
+		return
+	}
+
+	depth, err := s.orderBookManager.GetDepth(ctx, order_book_types.GetDepthArgs{CurrencyPair: pair})
+	if err != nil {
+		s.logger.Error("orderBookManager.GetDepth failed", zap.String("pair", pair.String()), zap.Error(err))
+		return
+	}
+
+	if update := t.setDepth(depth); len(update.Bids) > 0 || len(update.Asks) > 0 {
+		s.publish(t, update)
+	}
+}
+
+func (s *StreamService) refreshTicker(ctx context.Context, pair types.CurrencyPair) {
+	t, ok := s.topics[topicKey(stream_service_types.ChannelTicker, pair, "")]
+	if !ok {
+		return
+	}
+
+	ticker, err := s.tickerService.GetTicker(ctx, pair)
+	if err != nil {
+		s.logger.Error("tickerService.GetTicker failed", zap.String("pair", pair.String()), zap.Error(err))
+		return
+	}
+
+	if tickerChanged(t.ticker, ticker) {
+		t.ticker = ticker
+		s.publish(t, ticker)
+	}
+}
+
+func (s *StreamService) refreshWallets(ctx context.Context, userID string) {
+	t, ok := s.topics[topicKey(stream_service_types.ChannelWallets, types.CurrencyPair{}, userID)]
+	if !ok {
+		return
+	}
+
+	wallets, err := s.listWallets(ctx, userID)
+	if err != nil {
+		s.logger.Error("listWallets failed", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+
+	if changed := t.setWallets(wallets); len(changed) > 0 {
+		s.publish(t, changed)
+	}
+}
+
+// publish sends the next update of the topic to all of its clients.
+func (s *StreamService) publish(t *topic, data any) {
+	t.sequence++
+
+	payload, err := json.Marshal(message(t, stream_service_types.MessageUpdate, data))
+	if err != nil {
+		s.logger.Error("json.Marshal failed", zap.String("topic", t.key), zap.Error(err))
+		return
+	}
+
+	for c := range t.clients {
+		s.sendPayload(c, payload)
+	}
+}
+
+func (s *StreamService) send(c *client, msg stream_service_types.Message) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		s.logger.Error("json.Marshal failed", zap.Error(err))
+		return
+	}
+
+	s.sendPayload(c, payload)
+}
+
+// sendPayload queues the payload for the client, disconnecting it when its queue is full.
+func (s *StreamService) sendPayload(c *client, payload []byte) {
+	if c.closed {
+		return
+	}
+
+	select {
+	case c.send <- payload:
+	default:
+		s.logger.Warn("stream client is too slow, disconnecting", zap.String("user_id", c.userID.String))
+		s.dropClient(c)
+	}
+}
+
+// dropClient unsubscribes the client from all topics and closes its send channel,
+// which makes the write pump close the connection.
+func (s *StreamService) dropClient(c *client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+
+	for key := range c.topics {
+		s.unsubscribe(c, key)
+	}
+	delete(s.clients, c)
+
+	close(c.send)
+}
+
+// listActiveOrders returns the open and pending orders of the user across all order books.
+func (s *StreamService) listActiveOrders(ctx context.Context, userID string) ([]*types.Order, error) {
+	pairs, err := s.orderBookManager.ListAvailableCurrencyPairs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("orderBookManager.ListAvailableCurrencyPairs failed: %w", err)
+	}
+
+	active := make([]*types.Order, 0)
+	for _, pair := range pairs {
+		orders, err := s.orderBookManager.ListOrders(ctx, order_book_types.ListOrdersArgs{
+			CurrencyPair: pair,
+			UserIDIn:     []string{userID},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("orderBookManager.ListOrders failed: %w", err)
+		}
+
+		active = append(active, lo.Filter(orders, func(order *types.Order, _ int) bool {
+			return isActiveOrder(order)
+		})...)
+	}
+
+	return active, nil
+}
+
+func (s *StreamService) listWallets(ctx context.Context, userID string) (map[string]types.Wallet, error) {
+	wallets, err := s.store.ListWallets(ctx, store_types.ListWalletsArgs{UserIDsIn: []string{userID}})
+	if err != nil {
+		return nil, fmt.Errorf("store.ListWallets failed: %w", err)
+	}
+
+	return lo.SliceToMap(wallets, func(wallet *types.Wallet) (string, types.Wallet) {
+		return wallet.Requisites.Address, *wallet
+	}), nil
+}
+
+func message(t *topic, messageType stream_service_types.MessageType, data any) stream_service_types.Message {
+	msg := stream_service_types.Message{
+		Type:     messageType,
+		Channel:  t.channel,
+		Sequence: t.sequence,
+		Data:     data,
+	}
+	if !t.channel.IsPrivate() {
+		pair := t.pair
+		msg.CurrencyPair = &pair
+	}
+
+	return msg
+}
+
+// tickerChanged reports whether the tickers differ in anything but their time.
+func tickerChanged(previous, next *types.Ticker) bool {
+	if previous == nil {
+		return true
+	}
+
+	a, b := *previous, *next
+	a.Time, b.Time = time.Time{}, time.Time{}
+
+	return !reflect.DeepEqual(a, b)
+}
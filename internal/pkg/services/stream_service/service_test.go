@@ -0,0 +1,169 @@
+package stream_service
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	stream_service_types "vitalik_backend/internal/pkg/services/stream_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type fakeStore struct {
+	dependencies.IStore
+}
+
+func (s *fakeStore) SubscribeBalances(listener store_types.BalanceListener) {}
+
+// fakeOrderBookManager serves a depth the test can change and keeps the order listener.
+type fakeOrderBookManager struct {
+	dependencies.IOrderBookManager
+
+	mu            sync.Mutex
+	depth         types.Depth
+	orderListener order_book_types.OrderListener
+}
+
+func (m *fakeOrderBookManager) SubscribeTrades(listener order_book_types.TradeListener) {}
+
+func (m *fakeOrderBookManager) SubscribeOrders(listener order_book_types.OrderListener) {
+	m.orderListener = listener
+}
+
+func (m *fakeOrderBookManager) GetDepth(ctx context.Context, args order_book_types.GetDepthArgs) (*types.Depth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	depth := m.depth
+	return &depth, nil
+}
+
+func (m *fakeOrderBookManager) setDepth(depth types.Depth) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.depth = depth
+}
+
+type fakeTickerService struct {
+	dependencies.ITickerService
+}
+
+func level(price, quantity int64) types.DepthLevel {
+	return types.DepthLevel{Price: decimal.NewFromInt(price), Quantity: decimal.NewFromInt(quantity), OrderCount: 1}
+}
+
+func dial(t *testing.T, s *StreamService, userID null.String) *websocket.Conn {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = s.ServeWS(w, r, userID)
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) map[string]any {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var msg map[string]any
+	require.NoError(t, conn.ReadJSON(&msg))
+
+	return msg
+}
+
+func TestStreamDepth(t *testing.T) {
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	manager := &fakeOrderBookManager{
+		depth: types.Depth{
+			CurrencyPair: pair,
+			Bids:         []types.DepthLevel{level(99, 1)},
+			Asks:         []types.DepthLevel{level(101, 1)},
+		},
+	}
+
+	s, err := NewStreamService(&fakeStore{}, manager, &fakeTickerService{}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	t.Cleanup(s.Stop)
+
+	conn := dial(t, s, null.String{})
+
+	// The pair is subscribed in the reverse orientation, the stream uses the canonical one.
+	require.NoError(t, conn.WriteJSON(stream_service_types.Request{
+		Op:           stream_service_types.OpSubscribe,
+		Channel:      stream_service_types.ChannelDepth,
+		CurrencyPair: null.ValueFrom(types.CurrencyPair{Currency1: types.USDT, Currency2: types.BTC}),
+	}))
+
+	snapshot := read(t, conn)
+	require.Equal(t, "snapshot", snapshot["type"])
+	require.Equal(t, "depth", snapshot["channel"])
+	require.EqualValues(t, 1, snapshot["sequence"])
+	require.Len(t, snapshot["data"].(map[string]any)["bids"], 1)
+
+	// The best ask is taken and a new bid rests.
+	manager.setDepth(types.Depth{
+		CurrencyPair: pair,
+		Bids:         []types.DepthLevel{level(100, 2), level(99, 1)},
+	})
+	manager.orderListener(types.Order{SellCurrency: types.USDT, BuyCurrency: types.BTC})
+
+	update := read(t, conn)
+	require.Equal(t, "update", update["type"])
+	require.EqualValues(t, 2, update["sequence"])
+
+	data := update["data"].(map[string]any)
+	require.Len(t, data["bids"], 1)
+	require.Equal(t, "100", data["bids"].([]any)[0].(map[string]any)["price"])
+	require.Len(t, data["asks"], 1)
+	require.Equal(t, "0", data["asks"].([]any)[0].(map[string]any)["quantity"])
+}
+
+func TestStreamPrivateChannelRequiresUser(t *testing.T) {
+	s, err := NewStreamService(&fakeStore{}, &fakeOrderBookManager{}, &fakeTickerService{}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	t.Cleanup(s.Stop)
+
+	conn := dial(t, s, null.String{})
+
+	require.NoError(t, conn.WriteJSON(stream_service_types.Request{
+		Op:      stream_service_types.OpSubscribe,
+		Channel: stream_service_types.ChannelOrders,
+	}))
+
+	msg := read(t, conn)
+	require.Equal(t, "error", msg["type"])
+	require.Equal(t, "channel requires authentication", msg["message"])
+}
+
+func TestStreamLimitsQueuedRequests(t *testing.T) {
+	s, err := NewStreamService(&fakeStore{}, &fakeOrderBookManager{}, &fakeTickerService{}, zap.NewNop())
+	require.NoError(t, err)
+
+	c := newClient(nil, null.String{})
+	for range clientRequestBuffer {
+		require.True(t, s.enqueueRequest(event{kind: eventRequest, client: c}))
+	}
+	require.False(t, s.enqueueRequest(event{kind: eventRequest, client: c}))
+
+	// Handled requests free the queue of the client.
+	require.Len(t, s.dequeue(), clientRequestBuffer)
+	require.True(t, s.enqueueRequest(event{kind: eventRequest, client: c}))
+}
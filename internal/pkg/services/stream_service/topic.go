@@ -0,0 +1,193 @@
+package stream_service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/samber/lo"
+	"sort"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	stream_service_types "vitalik_backend/internal/pkg/services/stream_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// recentTradesLimit is the number of trades in the snapshot of the trades channel.
+const recentTradesLimit = 50
+
+// topic is a channel of a single pair or user together with the state its
+// snapshot is built from. Sequence is the sequence of the last published update.
+type topic struct {
+	key      string
+	channel  stream_service_types.Channel
+	pair     types.CurrencyPair
+	userID   string
+	sequence int64
+
+	clients map[*client]struct{}
+
+	depth   *types.Depth
+	trades  []types.Trade
+	ticker  *types.Ticker
+	orders  map[uuid.UUID]types.Order
+	wallets map[string]types.Wallet
+}
+
+func topicKey(channel stream_service_types.Channel, pair types.CurrencyPair, userID string) string {
+	if channel.IsPrivate() {
+		return fmt.Sprintf("%s:%s", channel, userID)
+	}
+	return fmt.Sprintf("%s:%s", channel, pair.String())
+}
+
+// canonicalPair orders the currencies of a pair alphabetically, so that both
+// orientations of a pair share the same topics.
+func canonicalPair(pair types.CurrencyPair) types.CurrencyPair {
+	if pair.Currency2 < pair.Currency1 {
+		return types.CurrencyPair{Currency1: pair.Currency2, Currency2: pair.Currency1}
+	}
+	return pair
+}
+
+// load reads the current state of the topic.
+func (s *StreamService) load(ctx context.Context, t *topic) error {
+	switch t.channel {
+	case stream_service_types.ChannelDepth:
+		depth, err := s.orderBookManager.GetDepth(ctx, order_book_types.GetDepthArgs{CurrencyPair: t.pair})
+		if err != nil {
+			return fmt.Errorf("orderBookManager.GetDepth failed: %w", err)
+		}
+		t.depth = depth
+
+	case stream_service_types.ChannelTrades:
+		trades, err := s.store.ListTrades(ctx, store_types.ListTradesArgs{
+			CurrencyPair: null.ValueFrom(t.pair),
+			Limit:        recentTradesLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("store.ListTrades failed: %w", err)
+		}
+		t.trades = lo.Map(trades, func(trade *types.Trade, _ int) types.Trade { return *trade })
+
+	case stream_service_types.ChannelTicker:
+		ticker, err := s.tickerService.GetTicker(ctx, t.pair)
+		if err != nil {
+			return fmt.Errorf("tickerService.GetTicker failed: %w", err)
+		}
+		t.ticker = ticker
+
+	case stream_service_types.ChannelOrders:
+		orders, err := s.listActiveOrders(ctx, t.userID)
+		if err != nil {
+			return fmt.Errorf("listActiveOrders failed: %w", err)
+		}
+		t.orders = lo.SliceToMap(orders, func(order *types.Order) (uuid.UUID, types.Order) { return order.ID, *order })
+
+	case stream_service_types.ChannelWallets:
+		wallets, err := s.listWallets(ctx, t.userID)
+		if err != nil {
+			return fmt.Errorf("listWallets failed: %w", err)
+		}
+		t.wallets = wallets
+	}
+
+	return nil
+}
+
+// snapshot returns the data of the snapshot message of the topic.
+func (t *topic) snapshot() any {
+	switch t.channel {
+	case stream_service_types.ChannelDepth:
+		return t.depth
+	case stream_service_types.ChannelTrades:
+		return t.trades
+	case stream_service_types.ChannelTicker:
+		return t.ticker
+	case stream_service_types.ChannelOrders:
+		orders := lo.Values(t.orders)
+		sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.Before(orders[j].CreatedAt) })
+		return orders
+	default:
+		return sortedWallets(lo.Values(t.wallets))
+	}
+}
+
+// addTrade puts the trade in front of the recent trades unless it is already there.
+func (t *topic) addTrade(trade types.Trade) bool {
+	if lo.SomeBy(t.trades, func(recent types.Trade) bool { return recent.ID == trade.ID }) {
+		return false
+	}
+
+	t.trades = append([]types.Trade{trade}, t.trades...)
+	if len(t.trades) > recentTradesLimit {
+		t.trades = t.trades[:recentTradesLimit]
+	}
+
+	return true
+}
+
+// setOrder keeps the order in the state while it is active and drops it afterwards.
+func (t *topic) setOrder(order types.Order) {
+	if isActiveOrder(&order) {
+		t.orders[order.ID] = order
+	} else {
+		delete(t.orders, order.ID)
+	}
+}
+
+// setDepth replaces the depth and returns the levels that changed.
+func (t *topic) setDepth(depth *types.Depth) stream_service_types.DepthUpdate {
+	update := stream_service_types.DepthUpdate{
+		Bids: diffLevels(t.depth.Bids, depth.Bids),
+		Asks: diffLevels(t.depth.Asks, depth.Asks),
+	}
+	t.depth = depth
+
+	return update
+}
+
+// setWallets replaces the wallets and returns the ones that changed.
+func (t *topic) setWallets(wallets map[string]types.Wallet) []types.Wallet {
+	changed := lo.Filter(lo.Values(wallets), func(wallet types.Wallet, _ int) bool {
+		previous, ok := t.wallets[wallet.Requisites.Address]
+		return !ok ||
+			!previous.Balance.Equal(wallet.Balance) ||
+			!previous.Locked.Equal(wallet.Locked)
+	})
+	t.wallets = wallets
+
+	return sortedWallets(changed)
+}
+
+// diffLevels returns the levels of next that are new or differ from previous, and
+// the levels of previous missing from next with a zero quantity.
+func diffLevels(previous, next []types.DepthLevel) []types.DepthLevel {
+	changed := make([]types.DepthLevel, 0)
+
+	for _, level := range next {
+		old, ok := lo.Find(previous, func(old types.DepthLevel) bool { return old.Price.Equal(level.Price) })
+		if !ok || !old.Quantity.Equal(level.Quantity) || old.OrderCount != level.OrderCount {
+			changed = append(changed, level)
+		}
+	}
+
+	for _, old := range previous {
+		if !lo.SomeBy(next, func(level types.DepthLevel) bool { return level.Price.Equal(old.Price) }) {
+			changed = append(changed, types.DepthLevel{Price: old.Price})
+		}
+	}
+
+	return changed
+}
+
+func isActiveOrder(order *types.Order) bool {
+	return order.Status.IsOpen() || order.Status == types.OrderPending
+}
+
+func sortedWallets(wallets []types.Wallet) []types.Wallet {
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].Requisites.Address < wallets[j].Requisites.Address
+	})
+	return wallets
+}
@@ -0,0 +1,78 @@
+package stream_service_types
+
+import (
+	"github.com/guregu/null/v5"
+	"vitalik_backend/internal/pkg/types"
+)
+
+type Channel string
+
+const (
+	// ChannelDepth streams the aggregated order book of a pair as level diffs.
+	ChannelDepth Channel = "depth"
+	// ChannelTrades streams the trades of a pair.
+	ChannelTrades Channel = "trades"
+	// ChannelTicker streams the 24-hour ticker of a pair.
+	ChannelTicker Channel = "ticker"
+	// ChannelOrders streams status changes of the orders of the authenticated user.
+	ChannelOrders Channel = "orders"
+	// ChannelWallets streams balance changes of the wallets of the authenticated user.
+	ChannelWallets Channel = "wallets"
+)
+
+func (c *Channel) Validate() bool {
+	switch *c {
+	case ChannelDepth, ChannelTrades, ChannelTicker, ChannelOrders, ChannelWallets:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPrivate reports whether the channel requires an authenticated connection.
+func (c *Channel) IsPrivate() bool {
+	return *c == ChannelOrders || *c == ChannelWallets
+}
+
+type Op string
+
+const (
+	OpSubscribe   Op = "subscribe"
+	OpUnsubscribe Op = "unsubscribe"
+)
+
+// Request is a message sent by the client. CurrencyPair is required by public channels only.
+type Request struct {
+	Op           Op                             `json:"op"`
+	Channel      Channel                        `json:"channel"`
+	CurrencyPair null.Value[types.CurrencyPair] `json:"currency_pair"`
+}
+
+type MessageType string
+
+const (
+	MessageSnapshot     MessageType = "snapshot"
+	MessageUpdate       MessageType = "update"
+	MessageUnsubscribed MessageType = "unsubscribed"
+	MessageError        MessageType = "error"
+)
+
+// Message is a message sent by the server. Every channel starts with a snapshot
+// whose Sequence is the sequence of the last update already included in it; each
+// following update has the next sequence, so a skipped number means a lost update
+// and the client should subscribe again.
+type Message struct {
+	Type         MessageType         `json:"type"`
+	Channel      Channel             `json:"channel,omitempty"`
+	CurrencyPair *types.CurrencyPair `json:"currency_pair,omitempty"`
+	Sequence     int64               `json:"sequence,omitempty"`
+	Data         any                 `json:"data,omitempty"`
+	Message      string              `json:"message,omitempty"`
+}
+
+// DepthUpdate holds the price levels that changed since the previous message.
+// A level with a zero quantity has been removed from the book.
+type DepthUpdate struct {
+	Bids []types.DepthLevel `json:"bids"`
+	Asks []types.DepthLevel `json:"asks"`
+}
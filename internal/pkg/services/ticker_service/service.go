@@ -0,0 +1,292 @@
+package ticker_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+const (
+	// window is the period the rolling statistics cover.
+	window = 24 * time.Hour
+
+	loadTradesBatchSize = 500
+
+	percentPrecision = 2
+)
+
+// TickerService keeps the trades of the last 24 hours of every currency pair in
+// memory and derives the ticker statistics from them on request. Trades are keyed
+// by their base and quote currencies.
+type TickerService struct {
+	store            dependencies.IStore
+	orderBookManager dependencies.IOrderBookManager
+	logger           *zap.Logger
+
+	mu         sync.Mutex
+	pairs      map[string]types.CurrencyPair
+	trades     map[string][]types.Trade
+	lastTrades map[string]types.Trade
+}
+
+func NewTickerService(
+	store dependencies.IStore,
+	orderBookManager dependencies.IOrderBookManager,
+	logger *zap.Logger,
+) (*TickerService, error) {
+	if store == nil || orderBookManager == nil || logger == nil {
+		return nil, errors.New("failed to initialize ticker service")
+	}
+
+	return &TickerService{
+		store:            store,
+		orderBookManager: orderBookManager,
+		logger:           logger,
+		pairs:            make(map[string]types.CurrencyPair),
+		trades:           make(map[string][]types.Trade),
+		lastTrades:       make(map[string]types.Trade),
+	}, nil
+}
+
+var _ dependencies.ITickerService = (*TickerService)(nil)
+
+// Load reads the trades of the last 24 hours and the last trade of every loaded
+// order book from the store, then subscribes to new trades. It must be called
+// after the order books are loaded and before trading starts.
+func (s *TickerService) Load(ctx context.Context) error {
+	trades, err := s.listTradesSince(ctx, time.Now().Add(-window))
+	if err != nil {
+		return fmt.Errorf("listTradesSince failed: %w", err)
+	}
+
+	pairs, err := s.orderBookManager.ListAvailableCurrencyPairs(ctx)
+	if err != nil {
+		return fmt.Errorf("orderBookManager.ListAvailableCurrencyPairs failed: %w", err)
+	}
+
+	lastTrades := make([]*types.Trade, 0, len(pairs))
+	for _, pair := range pairs {
+		pairTrades, err := s.store.ListTrades(ctx, store_types.ListTradesArgs{
+			CurrencyPair: null.ValueFrom(pair),
+			Limit:        1,
+		})
+		if err != nil {
+			return fmt.Errorf("store.ListTrades failed: %w", err)
+		}
+		lastTrades = append(lastTrades, pairTrades...)
+	}
+
+	// Older trades go first so the trades of the window end up last.
+	sort.Slice(lastTrades, func(i, j int) bool {
+		return lastTrades[i].CreatedAt.Before(lastTrades[j].CreatedAt)
+	})
+
+	for _, trade := range lastTrades {
+		s.recordLastTrade(*trade)
+	}
+	for _, trade := range trades {
+		s.RecordTrade(*trade)
+	}
+
+	s.orderBookManager.SubscribeTrades(s.RecordTrade)
+
+	return nil
+}
+
+// RecordTrade adds a settled trade to the statistics of its pair and drops the
+// trades that fell out of the window, so pairs nobody reads do not grow forever.
+func (s *TickerService) RecordTrade(trade types.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.addPair(tradePair(trade))
+
+	s.trades[key] = append(s.dropExpiredTrades(key, time.Now()), trade)
+	if last, ok := s.lastTrades[key]; !ok || !trade.CreatedAt.Before(last.CreatedAt) {
+		s.lastTrades[key] = trade
+	}
+}
+
+// GetTicker returns the ticker of the pair, turned to the orientation the pair is traded in.
+func (s *TickerService) GetTicker(ctx context.Context, currencyPair types.CurrencyPair) (*types.Ticker, error) {
+	s.mu.Lock()
+	if pair, ok := s.pairs[currencyPair.StringReverse()]; ok {
+		currencyPair = pair
+	}
+	s.mu.Unlock()
+
+	return s.buildTicker(ctx, currencyPair, time.Now())
+}
+
+// ListTickers returns the tickers of every pair that has an order book or has been traded.
+func (s *TickerService) ListTickers(ctx context.Context) ([]*types.Ticker, error) {
+	bookPairs, err := s.orderBookManager.ListAvailableCurrencyPairs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("orderBookManager.ListAvailableCurrencyPairs failed: %w", err)
+	}
+
+	s.mu.Lock()
+	pairs := lo.Values(s.pairs)
+	s.mu.Unlock()
+
+	for _, pair := range bookPairs {
+		if !lo.SomeBy(pairs, func(p types.CurrencyPair) bool { return p.Equals(&pair) }) {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].String() < pairs[j].String()
+	})
+
+	now := time.Now()
+	tickers := make([]*types.Ticker, 0, len(pairs))
+	for _, pair := range pairs {
+		ticker, err := s.buildTicker(ctx, pair, now)
+		if err != nil {
+			return nil, fmt.Errorf("buildTicker failed: %w", err)
+		}
+		tickers = append(tickers, ticker)
+	}
+
+	return tickers, nil
+}
+
+// buildTicker computes the statistics of the pair from the trades of the window
+// ending at now. The ticker lock is released before the order book is read, since
+// trades are recorded while the book is locked.
+func (s *TickerService) buildTicker(ctx context.Context, currencyPair types.CurrencyPair, now time.Time) (*types.Ticker, error) {
+	ticker := &types.Ticker{
+		CurrencyPair: currencyPair,
+		Time:         now,
+	}
+
+	s.mu.Lock()
+	key := currencyPair.String()
+	trades := s.pruneTrades(key, now)
+	if last, ok := s.lastTrades[key]; ok {
+		ticker.LastPrice = decimal.NewNullDecimal(last.Price)
+	}
+	s.mu.Unlock()
+
+	applyTrades(ticker, trades)
+
+	depth, err := s.orderBookManager.GetDepth(ctx, order_book_types.GetDepthArgs{
+		CurrencyPair: currencyPair,
+		Limit:        1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("orderBookManager.GetDepth failed: %w", err)
+	}
+
+	if len(depth.Bids) > 0 {
+		ticker.BestBid = decimal.NewNullDecimal(depth.Bids[0].Price)
+	}
+	if len(depth.Asks) > 0 {
+		ticker.BestAsk = decimal.NewNullDecimal(depth.Asks[0].Price)
+	}
+
+	return ticker, nil
+}
+
+// applyTrades fills the window statistics of the ticker from trades, oldest first.
+func applyTrades(ticker *types.Ticker, trades []types.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+
+	open := trades[0].Price
+	high, low := open, open
+	for _, trade := range trades {
+		high = decimal.Max(high, trade.Price)
+		low = decimal.Min(low, trade.Price)
+		ticker.Volume = ticker.Volume.Add(trade.Quantity)
+		ticker.QuoteVolume = ticker.QuoteVolume.Add(trade.QuoteQuantity)
+	}
+
+	change := trades[len(trades)-1].Price.Sub(open)
+
+	ticker.OpenPrice = decimal.NewNullDecimal(open)
+	ticker.HighPrice = decimal.NewNullDecimal(high)
+	ticker.LowPrice = decimal.NewNullDecimal(low)
+	ticker.PriceChange = decimal.NewNullDecimal(change)
+	ticker.PriceChangePercent = decimal.NewNullDecimal(change.Mul(decimal.NewFromInt(100)).DivRound(open, percentPrecision))
+	ticker.TradeCount = len(trades)
+}
+
+// pruneTrades drops the trades of the pair that fell out of the window and returns
+// a copy of the rest. The caller must hold the lock.
+func (s *TickerService) pruneTrades(key string, now time.Time) []types.Trade {
+	return append([]types.Trade(nil), s.dropExpiredTrades(key, now)...)
+}
+
+// dropExpiredTrades drops the trades of the pair that fell out of the window and
+// returns the rest. The caller must hold the lock.
+func (s *TickerService) dropExpiredTrades(key string, now time.Time) []types.Trade {
+	trades := s.trades[key]
+
+	start := sort.Search(len(trades), func(i int) bool {
+		return trades[i].CreatedAt.After(now.Add(-window))
+	})
+	trades = trades[start:]
+	s.trades[key] = trades
+
+	return trades
+}
+
+func (s *TickerService) recordLastTrade(trade types.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastTrades[s.addPair(tradePair(trade))] = trade
+}
+
+// addPair remembers the pair and returns its key. The caller must hold the lock.
+func (s *TickerService) addPair(pair types.CurrencyPair) string {
+	key := pair.String()
+	s.pairs[key] = pair
+	return key
+}
+
+// listTradesSince pages through the trades created at or after from, oldest first.
+func (s *TickerService) listTradesSince(ctx context.Context, from time.Time) ([]*types.Trade, error) {
+	trades := make([]*types.Trade, 0)
+
+	args := store_types.ListTradesArgs{
+		From:  null.TimeFrom(from),
+		Limit: loadTradesBatchSize,
+	}
+	for {
+		page, err := s.store.ListTrades(ctx, args)
+		if err != nil {
+			return nil, fmt.Errorf("store.ListTrades failed: %w", err)
+		}
+
+		trades = append(trades, page...)
+		if len(page) < loadTradesBatchSize {
+			break
+		}
+
+		args.Cursor.UUID, args.Cursor.Valid = page[len(page)-1].ID, true
+	}
+
+	slices.Reverse(trades)
+
+	return trades, nil
+}
+
+func tradePair(trade types.Trade) types.CurrencyPair {
+	return types.CurrencyPair{Currency1: trade.BaseCurrency, Currency2: trade.QuoteCurrency}
+}
@@ -0,0 +1,128 @@
+package ticker_service
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	order_book_types "vitalik_backend/internal/pkg/services/order_book/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// fakeStore is never called by the tests, which record trades directly.
+type fakeStore struct {
+	dependencies.IStore
+}
+
+// fakeOrderBookManager serves a fixed depth for every pair.
+type fakeOrderBookManager struct {
+	dependencies.IOrderBookManager
+
+	depth types.Depth
+}
+
+func (m *fakeOrderBookManager) ListAvailableCurrencyPairs(ctx context.Context) ([]types.CurrencyPair, error) {
+	return []types.CurrencyPair{m.depth.CurrencyPair}, nil
+}
+
+func (m *fakeOrderBookManager) GetDepth(ctx context.Context, args order_book_types.GetDepthArgs) (*types.Depth, error) {
+	depth := m.depth
+	return &depth, nil
+}
+
+func newTrade(price, quantity int64, createdAt time.Time) types.Trade {
+	return types.Trade{
+		BaseCurrency:  types.BTC,
+		QuoteCurrency: types.USDT,
+		Price:         decimal.NewFromInt(price),
+		Quantity:      decimal.NewFromInt(quantity),
+		QuoteQuantity: decimal.NewFromInt(price * quantity),
+		CreatedAt:     createdAt,
+	}
+}
+
+func TestTicker(t *testing.T) {
+	ctx := context.Background()
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	manager := &fakeOrderBookManager{
+		depth: types.Depth{
+			CurrencyPair: pair,
+			Bids:         []types.DepthLevel{{Price: decimal.NewFromInt(109), Quantity: decimal.NewFromInt(1), OrderCount: 1}},
+			Asks:         []types.DepthLevel{{Price: decimal.NewFromInt(111), Quantity: decimal.NewFromInt(1), OrderCount: 1}},
+		},
+	}
+
+	s, err := NewTickerService(&fakeStore{}, manager, zap.NewNop())
+	require.NoError(t, err)
+
+	now := time.Now()
+	s.RecordTrade(newTrade(90, 5, now.Add(-25*time.Hour)))
+	s.RecordTrade(newTrade(100, 1, now.Add(-2*time.Hour)))
+	s.RecordTrade(newTrade(120, 2, now.Add(-time.Hour)))
+	s.RecordTrade(newTrade(110, 1, now.Add(-time.Minute)))
+
+	ticker, err := s.GetTicker(ctx, types.CurrencyPair{Currency1: types.USDT, Currency2: types.BTC})
+	require.NoError(t, err)
+
+	// The trade older than 24 hours is out of the window.
+	require.Equal(t, pair, ticker.CurrencyPair)
+	require.True(t, ticker.LastPrice.Decimal.Equal(decimal.NewFromInt(110)))
+	require.True(t, ticker.OpenPrice.Decimal.Equal(decimal.NewFromInt(100)))
+	require.True(t, ticker.HighPrice.Decimal.Equal(decimal.NewFromInt(120)))
+	require.True(t, ticker.LowPrice.Decimal.Equal(decimal.NewFromInt(100)))
+	require.True(t, ticker.PriceChange.Decimal.Equal(decimal.NewFromInt(10)))
+	require.True(t, ticker.PriceChangePercent.Decimal.Equal(decimal.NewFromInt(10)))
+	require.True(t, ticker.Volume.Equal(decimal.NewFromInt(4)))
+	require.True(t, ticker.QuoteVolume.Equal(decimal.NewFromInt(450)))
+	require.Equal(t, 3, ticker.TradeCount)
+	require.True(t, ticker.BestBid.Decimal.Equal(decimal.NewFromInt(109)))
+	require.True(t, ticker.BestAsk.Decimal.Equal(decimal.NewFromInt(111)))
+
+	tickers, err := s.ListTickers(ctx)
+	require.NoError(t, err)
+	require.Len(t, tickers, 1)
+	require.Equal(t, 3, tickers[0].TradeCount)
+}
+
+func TestTickerWithoutTradesInWindow(t *testing.T) {
+	ctx := context.Background()
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	s, err := NewTickerService(&fakeStore{}, &fakeOrderBookManager{depth: types.Depth{CurrencyPair: pair}}, zap.NewNop())
+	require.NoError(t, err)
+
+	s.RecordTrade(newTrade(90, 5, time.Now().Add(-48*time.Hour)))
+
+	ticker, err := s.GetTicker(ctx, pair)
+	require.NoError(t, err)
+
+	// The last price outlives the window, the window statistics do not.
+	require.True(t, ticker.LastPrice.Decimal.Equal(decimal.NewFromInt(90)))
+	require.False(t, ticker.OpenPrice.Valid)
+	require.False(t, ticker.BestBid.Valid)
+	require.True(t, ticker.Volume.IsZero())
+	require.Zero(t, ticker.TradeCount)
+}
+
+func TestRecordTradeDropsExpiredTrades(t *testing.T) {
+	pair := types.CurrencyPair{Currency1: types.BTC, Currency2: types.USDT}
+
+	s, err := NewTickerService(&fakeStore{}, &fakeOrderBookManager{depth: types.Depth{CurrencyPair: pair}}, zap.NewNop())
+	require.NoError(t, err)
+
+	now := time.Now()
+	s.RecordTrade(newTrade(90, 5, now.Add(-48*time.Hour)))
+	s.RecordTrade(newTrade(95, 1, now.Add(-25*time.Hour)))
+	s.RecordTrade(newTrade(100, 1, now.Add(-time.Minute)))
+
+	// The pair is never read, yet only the trade inside the window is kept.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	require.Len(t, s.trades[pair.String()], 1)
+	require.True(t, s.trades[pair.String()][0].Price.Equal(decimal.NewFromInt(100)))
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"time"
 	"vitalik_backend/internal/dependencies"
 	walet_service_types "vitalik_backend/internal/pkg/services/wallet_service/types"
@@ -40,7 +41,7 @@ func (s *WalletService) CreateWallet(ctx context.Context, args walet_service_typ
 			UserID:  args.UserID,
 		},
 		Currency:  args.Currency,
-		Balance:   0,
+		Balance:   decimal.Zero,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
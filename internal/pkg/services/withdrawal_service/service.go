@@ -0,0 +1,259 @@
+package withdrawal_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"go.uber.org/zap"
+	"time"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	withdrawal_service_types "vitalik_backend/internal/pkg/services/withdrawal_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// syncInterval is how often sent withdrawals are checked at the payout backend.
+const syncInterval = 10 * time.Second
+
+// WithdrawalService runs withdrawals through their states. Requested withdrawals
+// wait for an admin, approved ones are sent to the payout backend right away and
+// sent ones are completed or failed as the backend reports them.
+//
+// A withdrawal is moved from approved to sending before it goes to the backend,
+// so only the caller that made that move sends it. One left sending by a crash
+// is not sent again and has to be checked at the backend by hand.
+type WithdrawalService struct {
+	store  dependencies.IStore
+	payout dependencies.IPayout
+	logger *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWithdrawalService(
+	store dependencies.IStore,
+	payout dependencies.IPayout,
+	logger *zap.Logger,
+) (*WithdrawalService, error) {
+	if store == nil || payout == nil || logger == nil {
+		return nil, errors.New("failed to initialize withdrawal service")
+	}
+
+	return &WithdrawalService{
+		store:  store,
+		payout: payout,
+		logger: logger,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+var _ dependencies.IWithdrawalService = (*WithdrawalService)(nil)
+
+// Start checks the sent withdrawals periodically until Stop is called.
+func (s *WithdrawalService) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.SyncPayouts(ctx); err != nil {
+					s.logger.Error("SyncPayouts failed", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *WithdrawalService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+}
+
+// RequestWithdrawal creates a pending withdrawal from a wallet of the user and holds its amount.
+func (s *WithdrawalService) RequestWithdrawal(
+	ctx context.Context,
+	args withdrawal_service_types.RequestWithdrawalArgs,
+) (*types.Withdrawal, error) {
+	wallets, err := s.store.ListWallets(ctx, store_types.ListWalletsArgs{
+		AddresssIn: []string{args.Address},
+		UserIDsIn:  []string{args.UserID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store.ListWallets failed: %w", err)
+	}
+	if len(wallets) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	wallet := wallets[0]
+	if err = wallet.Currency.ValidateAmount(args.Amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	withdrawal := types.Withdrawal{
+		ID:          uuid.Must(uuid.NewV7()),
+		Requisites:  wallet.Requisites,
+		Currency:    wallet.Currency,
+		Amount:      args.Amount,
+		Destination: args.Destination,
+		Status:      types.WithdrawalPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = s.store.CreateWithdrawal(ctx, withdrawal); err != nil {
+		return nil, fmt.Errorf("store.CreateWithdrawal failed: %w", err)
+	}
+
+	return &withdrawal, nil
+}
+
+// ApproveWithdrawal approves a pending withdrawal and sends it to the payout backend.
+func (s *WithdrawalService) ApproveWithdrawal(
+	ctx context.Context,
+	args withdrawal_service_types.ReviewWithdrawalArgs,
+) (*types.Withdrawal, error) {
+	withdrawal, err := s.store.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+		ID:         args.ID,
+		Status:     types.WithdrawalApproved,
+		Reason:     args.Reason,
+		ReviewedBy: null.StringFrom(args.AdminID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store.TransitionWithdrawal failed: %w", err)
+	}
+
+	sent, err := s.send(ctx, *withdrawal)
+	if err != nil {
+		return nil, err
+	}
+	if sent == nil {
+		// The sync loop claimed the withdrawal first and sends it.
+		return s.store.GetWithdrawal(ctx, withdrawal.ID)
+	}
+
+	return sent, nil
+}
+
+// RejectWithdrawal rejects a pending withdrawal and releases its amount.
+func (s *WithdrawalService) RejectWithdrawal(
+	ctx context.Context,
+	args withdrawal_service_types.ReviewWithdrawalArgs,
+) (*types.Withdrawal, error) {
+	withdrawal, err := s.store.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+		ID:         args.ID,
+		Status:     types.WithdrawalRejected,
+		Reason:     args.Reason,
+		ReviewedBy: null.StringFrom(args.AdminID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store.TransitionWithdrawal failed: %w", err)
+	}
+
+	return withdrawal, nil
+}
+
+// SyncPayouts sends the withdrawals left approved and completes or fails the sent
+// ones the payout backend has finished.
+func (s *WithdrawalService) SyncPayouts(ctx context.Context) error {
+	withdrawals, err := s.store.ListWithdrawals(ctx, store_types.ListWithdrawalsArgs{
+		StatusIn: []types.WithdrawalStatus{types.WithdrawalApproved, types.WithdrawalSent},
+	})
+	if err != nil {
+		return fmt.Errorf("store.ListWithdrawals failed: %w", err)
+	}
+
+	var errs []error
+	for _, withdrawal := range withdrawals {
+		if withdrawal.Status == types.WithdrawalApproved {
+			_, err = s.send(ctx, *withdrawal)
+		} else {
+			err = s.settle(ctx, *withdrawal)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("withdrawal %s: %w", withdrawal.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// send claims an approved withdrawal, submits it to the payout backend and marks
+// it sent, or failed when the backend refuses it. It returns nil without sending
+// when the withdrawal was claimed by someone else.
+func (s *WithdrawalService) send(ctx context.Context, withdrawal types.Withdrawal) (*types.Withdrawal, error) {
+	claimed, err := s.store.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+		ID:     withdrawal.ID,
+		Status: types.WithdrawalSending,
+	})
+	if errors.Is(err, types.ErrWithdrawalTransition) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store.TransitionWithdrawal failed: %w", err)
+	}
+
+	args := store_types.TransitionWithdrawalArgs{ID: withdrawal.ID}
+
+	reference, err := s.payout.Send(ctx, *claimed)
+	if err != nil {
+		s.logger.Warn("payout failed", zap.String("withdrawal_id", withdrawal.ID.String()), zap.Error(err))
+
+		args.Status = types.WithdrawalFailed
+		args.Reason = null.StringFrom(err.Error())
+	} else {
+		args.Status = types.WithdrawalSent
+		args.Reference = null.StringFrom(reference)
+	}
+
+	sent, err := s.store.TransitionWithdrawal(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("store.TransitionWithdrawal failed: %w", err)
+	}
+
+	return sent, nil
+}
+
+// settle completes or fails a sent withdrawal once the payout backend has finished it.
+func (s *WithdrawalService) settle(ctx context.Context, withdrawal types.Withdrawal) error {
+	status, err := s.payout.Status(ctx, withdrawal.Reference.String)
+	if err != nil {
+		return fmt.Errorf("payout.Status failed: %w", err)
+	}
+
+	args := store_types.TransitionWithdrawalArgs{ID: withdrawal.ID}
+
+	switch status {
+	case types.PayoutCompleted:
+		args.Status = types.WithdrawalCompleted
+	case types.PayoutFailed:
+		args.Status = types.WithdrawalFailed
+		args.Reason = null.StringFrom("payout failed")
+	default:
+		return nil
+	}
+
+	if _, err = s.store.TransitionWithdrawal(ctx, args); err != nil {
+		return fmt.Errorf("store.TransitionWithdrawal failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package withdrawal_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/services/payout_service"
+	"vitalik_backend/internal/pkg/services/store"
+	store_types "vitalik_backend/internal/pkg/services/store/types"
+	withdrawal_service_types "vitalik_backend/internal/pkg/services/withdrawal_service/types"
+	"vitalik_backend/internal/pkg/types"
+)
+
+// fakeStore keeps wallets and withdrawals in memory and follows the withdrawal
+// state machine like the real store, without balances.
+type fakeStore struct {
+	dependencies.IStore
+
+	mu          sync.Mutex
+	wallets     []*types.Wallet
+	withdrawals map[uuid.UUID]types.Withdrawal
+}
+
+func newFakeStore(wallets ...*types.Wallet) *fakeStore {
+	return &fakeStore{
+		wallets:     wallets,
+		withdrawals: make(map[uuid.UUID]types.Withdrawal),
+	}
+}
+
+func (s *fakeStore) ListWallets(ctx context.Context, args store_types.ListWalletsArgs) ([]*types.Wallet, error) {
+	wallets := make([]*types.Wallet, 0)
+	for _, wallet := range s.wallets {
+		if slices.Contains(args.AddresssIn, wallet.Requisites.Address) && slices.Contains(args.UserIDsIn, wallet.Requisites.UserID) {
+			wallets = append(wallets, wallet)
+		}
+	}
+	return wallets, nil
+}
+
+func (s *fakeStore) CreateWithdrawal(ctx context.Context, withdrawal types.Withdrawal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.withdrawals[withdrawal.ID] = withdrawal
+	return nil
+}
+
+func (s *fakeStore) TransitionWithdrawal(ctx context.Context, args store_types.TransitionWithdrawalArgs) (*types.Withdrawal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	withdrawal, ok := s.withdrawals[args.ID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	if !withdrawal.Status.CanTransitionTo(args.Status) {
+		return nil, fmt.Errorf("%s to %s: %w", withdrawal.Status, args.Status, types.ErrWithdrawalTransition)
+	}
+
+	withdrawal.Status = args.Status
+	if args.Reference.Valid {
+		withdrawal.Reference = args.Reference
+	}
+	if args.Reason.Valid {
+		withdrawal.Reason = args.Reason
+	}
+	if args.ReviewedBy.Valid {
+		withdrawal.ReviewedBy = args.ReviewedBy
+	}
+	s.withdrawals[args.ID] = withdrawal
+
+	return &withdrawal, nil
+}
+
+func (s *fakeStore) ListWithdrawals(ctx context.Context, args store_types.ListWithdrawalsArgs) ([]*types.Withdrawal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	withdrawals := make([]*types.Withdrawal, 0)
+	for _, withdrawal := range s.withdrawals {
+		if slices.Contains(args.StatusIn, withdrawal.Status) {
+			withdrawalCopy := withdrawal
+			withdrawals = append(withdrawals, &withdrawalCopy)
+		}
+	}
+	return withdrawals, nil
+}
+
+func (s *fakeStore) status(id uuid.UUID) types.WithdrawalStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withdrawals[id].Status
+}
+
+// countingPayout counts the payouts sent to the fake backend.
+type countingPayout struct {
+	*payout_service.FakePayout
+
+	sends atomic.Int32
+}
+
+func (p *countingPayout) Send(ctx context.Context, withdrawal types.Withdrawal) (string, error) {
+	p.sends.Add(1)
+	return p.FakePayout.Send(ctx, withdrawal)
+}
+
+func newTestService(t *testing.T) (*WithdrawalService, *fakeStore, *payout_service.FakePayout) {
+	t.Helper()
+
+	fakeStore := newFakeStore(&types.Wallet{
+		Requisites: types.Requisites{UserID: "alice", Address: "alice-BTC"},
+		Currency:   types.BTC,
+		Balance:    decimal.NewFromInt(10),
+	})
+	payout := payout_service.NewFakePayout()
+
+	s, err := NewWithdrawalService(fakeStore, payout, zap.NewNop())
+	require.NoError(t, err)
+
+	return s, fakeStore, payout
+}
+
+func requestWithdrawal(t *testing.T, s *WithdrawalService) *types.Withdrawal {
+	t.Helper()
+
+	withdrawal, err := s.RequestWithdrawal(context.Background(), withdrawal_service_types.RequestWithdrawalArgs{
+		UserID:      "alice",
+		Address:     "alice-BTC",
+		Amount:      decimal.NewFromInt(1),
+		Destination: "bc1qexternal",
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.WithdrawalPending, withdrawal.Status)
+	require.Equal(t, types.BTC, withdrawal.Currency)
+
+	return withdrawal
+}
+
+func TestWithdrawalCompletes(t *testing.T) {
+	ctx := context.Background()
+	s, fakeStore, payout := newTestService(t)
+	payout.Result = types.PayoutPending
+
+	withdrawal := requestWithdrawal(t, s)
+
+	approved, err := s.ApproveWithdrawal(ctx, withdrawal_service_types.ReviewWithdrawalArgs{ID: withdrawal.ID, AdminID: "admin"})
+	require.NoError(t, err)
+	require.Equal(t, types.WithdrawalSent, approved.Status)
+	require.Equal(t, "admin", approved.ReviewedBy.String)
+	require.True(t, approved.Reference.Valid)
+
+	// The payout is still in flight.
+	require.NoError(t, s.SyncPayouts(ctx))
+	require.Equal(t, types.WithdrawalSent, fakeStore.status(withdrawal.ID))
+
+	payout.SetStatus(approved.Reference.String, types.PayoutCompleted)
+	require.NoError(t, s.SyncPayouts(ctx))
+	require.Equal(t, types.WithdrawalCompleted, fakeStore.status(withdrawal.ID))
+
+	// A completed withdrawal cannot be reviewed again.
+	_, err = s.RejectWithdrawal(ctx, withdrawal_service_types.ReviewWithdrawalArgs{ID: withdrawal.ID, AdminID: "admin"})
+	require.ErrorIs(t, err, types.ErrWithdrawalTransition)
+}
+
+func TestWithdrawalRejected(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newTestService(t)
+
+	withdrawal := requestWithdrawal(t, s)
+
+	rejected, err := s.RejectWithdrawal(ctx, withdrawal_service_types.ReviewWithdrawalArgs{ID: withdrawal.ID, AdminID: "admin"})
+	require.NoError(t, err)
+	require.Equal(t, types.WithdrawalRejected, rejected.Status)
+
+	_, err = s.ApproveWithdrawal(ctx, withdrawal_service_types.ReviewWithdrawalArgs{ID: withdrawal.ID, AdminID: "admin"})
+	require.ErrorIs(t, err, types.ErrWithdrawalTransition)
+}
+
+func TestWithdrawalFails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("send", func(t *testing.T) {
+		s, _, payout := newTestService(t)
+		payout.SendErr = errors.New("destination is not reachable")
+
+		withdrawal := requestWithdrawal(t, s)
+
+		failed, err := s.ApproveWithdrawal(ctx, withdrawal_service_types.ReviewWithdrawalArgs{ID: withdrawal.ID, AdminID: "admin"})
+		require.NoError(t, err)
+		require.Equal(t, types.WithdrawalFailed, failed.Status)
+		require.Equal(t, "destination is not reachable", failed.Reason.String)
+	})
+
+	t.Run("payout", func(t *testing.T) {
+		s, fakeStore, payout := newTestService(t)
+		payout.Result = types.PayoutFailed
+
+		withdrawal := requestWithdrawal(t, s)
+
+		_, err := s.ApproveWithdrawal(ctx, withdrawal_service_types.ReviewWithdrawalArgs{ID: withdrawal.ID, AdminID: "admin"})
+		require.NoError(t, err)
+
+		require.NoError(t, s.SyncPayouts(ctx))
+		require.Equal(t, types.WithdrawalFailed, fakeStore.status(withdrawal.ID))
+	})
+}
+
+func TestApprovedWithdrawalIsSentOnce(t *testing.T) {
+	ctx := context.Background()
+	fakeStore := newFakeStore(&types.Wallet{
+		Requisites: types.Requisites{UserID: "alice", Address: "alice-BTC"},
+		Currency:   types.BTC,
+		Balance:    decimal.NewFromInt(10),
+	})
+	payout := &countingPayout{FakePayout: payout_service.NewFakePayout()}
+	payout.Result = types.PayoutPending
+
+	s, err := NewWithdrawalService(fakeStore, payout, zap.NewNop())
+	require.NoError(t, err)
+
+	withdrawal := requestWithdrawal(t, s)
+	_, err = fakeStore.TransitionWithdrawal(ctx, store_types.TransitionWithdrawalArgs{
+		ID:     withdrawal.ID,
+		Status: types.WithdrawalApproved,
+	})
+	require.NoError(t, err)
+
+	// The sync loops of several instances find the same approved withdrawal.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, s.SyncPayouts(ctx))
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), payout.sends.Load())
+	require.Equal(t, types.WithdrawalSent, fakeStore.status(withdrawal.ID))
+}
+
+func TestRequestWithdrawalFromForeignWallet(t *testing.T) {
+	s, _, _ := newTestService(t)
+
+	_, err := s.RequestWithdrawal(context.Background(), withdrawal_service_types.RequestWithdrawalArgs{
+		UserID:      "bob",
+		Address:     "alice-BTC",
+		Amount:      decimal.NewFromInt(1),
+		Destination: "bc1qexternal",
+	})
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,21 @@
+package withdrawal_service_types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+)
+
+type RequestWithdrawalArgs struct {
+	UserID      string
+	Address     string
+	Amount      decimal.Decimal
+	Destination string
+}
+
+// ReviewWithdrawalArgs approves or rejects a pending withdrawal on behalf of an admin.
+type ReviewWithdrawalArgs struct {
+	ID      uuid.UUID
+	AdminID string
+	Reason  null.String
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrNonPositiveAmount = errors.New("amount must be greater than zero")
+	ErrAmountPrecision   = errors.New("amount has too many decimal places")
+)
+
+// ValidateAmount checks that the amount is positive and fits the currency precision.
+func (c *Currency) ValidateAmount(amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return ErrNonPositiveAmount
+	}
+
+	if !amount.Equal(c.Quantize(amount)) {
+		return fmt.Errorf("%w: %s supports up to %d", ErrAmountPrecision, c.String(), c.Precision())
+	}
+
+	return nil
+}
+
+// Quantize truncates the amount to the number of decimal places supported by the currency.
+func (c *Currency) Quantize(amount decimal.Decimal) decimal.Decimal {
+	return amount.Truncate(c.Precision())
+}
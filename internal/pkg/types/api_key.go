@@ -0,0 +1,83 @@
+package types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIKeyScope limits what an API key may do. Keys are never allowed to manage
+// other keys or to reach the admin routes.
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead     APIKeyScope = "read"
+	APIKeyScopeTrade    APIKeyScope = "trade"
+	APIKeyScopeWithdraw APIKeyScope = "withdraw"
+)
+
+func (s *APIKeyScope) Validate() bool {
+	switch *s {
+	case APIKeyScopeRead, APIKeyScopeTrade, APIKeyScopeWithdraw:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKey authenticates programmatic requests signed with its secret. Only the
+// SHA-256 hash of the secret is kept. An empty AllowedIPs accepts every address,
+// otherwise each entry is an IP address or a CIDR range.
+type APIKey struct {
+	ID     uuid.UUID `json:"id"`
+	UserID string    `json:"user_id"`
+	Name   string    `json:"name"`
+
+	SecretHash string `json:"-"`
+
+	Scopes     []APIKeyScope `json:"scopes"`
+	AllowedIPs []string      `json:"allowed_ips"`
+
+	CreatedAt time.Time `json:"created_at"`
+	RevokedAt null.Time `json:"revoked_at"`
+}
+
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+func (k *APIKey) AllowsIP(ip string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, allowed := range k.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			if _, network, err := net.ParseCIDR(allowed); err == nil && network.Contains(addr) {
+				return true
+			}
+		} else if allowedAddr := net.ParseIP(allowed); allowedAddr != nil && allowedAddr.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAllowedIP reports whether an allow-list entry is an IP address or a
+// CIDR range.
+func ValidateAllowedIP(allowed string) bool {
+	if strings.Contains(allowed, "/") {
+		_, _, err := net.ParseCIDR(allowed)
+		return err == nil
+	}
+	return net.ParseIP(allowed) != nil
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type CandleInterval string
+
+const (
+	Candle1m CandleInterval = "1m"
+	Candle5m CandleInterval = "5m"
+	Candle1h CandleInterval = "1h"
+	Candle1d CandleInterval = "1d"
+)
+
+// CandleIntervals lists every interval trades are aggregated into.
+var CandleIntervals = []CandleInterval{Candle1m, Candle5m, Candle1h, Candle1d}
+
+func (i *CandleInterval) Validate() bool {
+	switch *i {
+	case Candle1m, Candle5m, Candle1h, Candle1d:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *CandleInterval) Duration() time.Duration {
+	switch *i {
+	case Candle1m:
+		return time.Minute
+	case Candle5m:
+		return 5 * time.Minute
+	case Candle1h:
+		return time.Hour
+	case Candle1d:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// OpenTime returns the start of the interval containing t. Buckets are aligned to UTC.
+func (i *CandleInterval) OpenTime(t time.Time) time.Time {
+	return t.UTC().Truncate(i.Duration())
+}
+
+// Candle holds the open, high, low and close prices and the traded volume of a
+// currency pair over one interval. Volume is in the base currency, QuoteVolume
+// in the quote currency.
+type Candle struct {
+	BaseCurrency  Currency       `json:"base_currency"`
+	QuoteCurrency Currency       `json:"quote_currency"`
+	Interval      CandleInterval `json:"interval"`
+	OpenTime      time.Time      `json:"open_time"`
+
+	Open   decimal.Decimal `json:"open"`
+	High   decimal.Decimal `json:"high"`
+	Low    decimal.Decimal `json:"low"`
+	Close  decimal.Decimal `json:"close"`
+	Volume decimal.Decimal `json:"volume"`
+
+	QuoteVolume decimal.Decimal `json:"quote_volume"`
+	TradeCount  int             `json:"trade_count"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCandle returns the candle of the interval containing the trade, made of that trade alone.
+func NewCandle(trade Trade, interval CandleInterval) Candle {
+	return Candle{
+		BaseCurrency:  trade.BaseCurrency,
+		QuoteCurrency: trade.QuoteCurrency,
+		Interval:      interval,
+		OpenTime:      interval.OpenTime(trade.CreatedAt),
+		Open:          trade.Price,
+		High:          trade.Price,
+		Low:           trade.Price,
+		Close:         trade.Price,
+		Volume:        trade.Quantity,
+		QuoteVolume:   trade.QuoteQuantity,
+		TradeCount:    1,
+		UpdatedAt:     trade.CreatedAt,
+	}
+}
+
+// Add folds a later trade of the same interval into the candle.
+func (c *Candle) Add(trade Trade) {
+	c.High = decimal.Max(c.High, trade.Price)
+	c.Low = decimal.Min(c.Low, trade.Price)
+	c.Close = trade.Price
+	c.Volume = c.Volume.Add(trade.Quantity)
+	c.QuoteVolume = c.QuoteVolume.Add(trade.QuoteQuantity)
+	c.TradeCount++
+	c.UpdatedAt = trade.CreatedAt
+}
@@ -12,10 +12,33 @@ const (
 
 var availableCurrencies = []Currency{BTC, USDT, ETH}
 
+// currencyPrecisions holds the number of decimal places supported by each currency.
+var currencyPrecisions = map[Currency]int32{
+	BTC:  8,
+	USDT: 6,
+	ETH:  18,
+}
+
+// currencyConfirmations holds the number of network confirmations a deposit of
+// each currency needs before it is credited.
+var currencyConfirmations = map[Currency]int{
+	BTC:  3,
+	USDT: 12,
+	ETH:  12,
+}
+
 func (c *Currency) String() string {
 	return string(*c)
 }
 
+func (c *Currency) Precision() int32 {
+	return currencyPrecisions[*c]
+}
+
+func (c *Currency) RequiredConfirmations() int {
+	return currencyConfirmations[*c]
+}
+
 func (c *Currency) Validate() bool {
 	switch *c {
 	case BTC, USDT, ETH:
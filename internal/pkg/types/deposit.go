@@ -0,0 +1,42 @@
+package types
+
+import (
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// IncomingTransfer is a transfer to an exchange wallet as reported by a deposit
+// source. It is reported again as its confirmations grow.
+type IncomingTransfer struct {
+	TxHash        string
+	Address       string
+	Currency      Currency
+	Amount        decimal.Decimal
+	Confirmations int
+}
+
+type DepositStatus string
+
+const (
+	DepositPending  DepositStatus = "PENDING"
+	DepositCredited DepositStatus = "CREDITED"
+)
+
+// Deposit tracks an incoming transfer until it has enough confirmations to be
+// credited to the wallet. Every tx hash is credited at most once.
+type Deposit struct {
+	TxHash string `json:"tx_hash"`
+
+	Requisites Requisites      `json:"requisites"`
+	Currency   Currency        `json:"currency"`
+	Amount     decimal.Decimal `json:"amount"`
+
+	Confirmations int           `json:"confirmations"`
+	Status        DepositStatus `json:"status"`
+	TransactionID null.String   `json:"transaction_id,omitempty"`
+
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	CreditedAt null.Time `json:"credited_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+package types
+
+import "github.com/shopspring/decimal"
+
+// DepthLevel is the resting liquidity at one price of the book.
+type DepthLevel struct {
+	Price      decimal.Decimal `json:"price"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	OrderCount int             `json:"order_count"`
+}
+
+// Depth is the aggregated view of an order book, best price first on both sides.
+type Depth struct {
+	CurrencyPair CurrencyPair `json:"currency_pair"`
+
+	Bids []DepthLevel `json:"bids"`
+	Asks []DepthLevel `json:"asks"`
+}
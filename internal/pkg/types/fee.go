@@ -0,0 +1,57 @@
+package types
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// FeeUserID owns the fee wallets the exchange collects trading fees into.
+const FeeUserID = "exchange"
+
+// FeeWalletAddress returns the address of the fee wallet of the currency.
+func FeeWalletAddress(currency Currency) string {
+	return "fee-" + currency.String()
+}
+
+type FeeTier string
+
+const (
+	FeeTierStandard    FeeTier = "STANDARD"
+	FeeTierVIP         FeeTier = "VIP"
+	FeeTierMarketMaker FeeTier = "MARKET_MAKER"
+)
+
+func (t *FeeTier) Validate() bool {
+	switch *t {
+	case FeeTierStandard, FeeTierVIP, FeeTierMarketMaker:
+		return true
+	default:
+		return false
+	}
+}
+
+// FeeSchedule holds the fee rates of a currency pair for a user tier. The maker
+// rate applies to the resting order of a trade, the taker rate to the incoming one.
+type FeeSchedule struct {
+	CurrencyPair CurrencyPair `json:"currency_pair"`
+	Tier         FeeTier      `json:"tier"`
+
+	MakerRate decimal.Decimal `json:"maker_rate"`
+	TakerRate decimal.Decimal `json:"taker_rate"`
+}
+
+// Fee returns the fee on an amount of the currency received by the maker or the
+// taker, rounded up to the currency precision.
+func (s *FeeSchedule) Fee(amount decimal.Decimal, currency Currency, maker bool) decimal.Decimal {
+	rate := s.TakerRate
+	if maker {
+		rate = s.MakerRate
+	}
+
+	return amount.Mul(rate).RoundCeil(currency.Precision())
+}
+
+// FeeTotal is the sum of the fees a user paid in one currency.
+type FeeTotal struct {
+	Currency Currency        `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+}
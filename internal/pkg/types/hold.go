@@ -0,0 +1,23 @@
+package types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Hold reserves wallet funds for an open order until it is filled or cancelled,
+// or for a withdrawal until it is completed, rejected or fails. OrderID is the ID
+// of the withdrawal for the latter.
+type Hold struct {
+	OrderID uuid.UUID `json:"order_id"`
+
+	Address  string          `json:"address"`
+	Currency Currency        `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	ReleasedAt null.Time `json:"released_at,omitempty"`
+}
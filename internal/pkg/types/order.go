@@ -3,6 +3,7 @@ package types
 import (
 	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
 	"time"
 )
 
@@ -13,28 +14,125 @@ const (
 	Sell OrderType = "SELL"
 )
 
+// OrderKind tells how the order is priced: limit orders rest on the book at
+// their price, market orders sweep the opposite side and never rest.
+type OrderKind string
+
+const (
+	Limit  OrderKind = "LIMIT"
+	Market OrderKind = "MARKET"
+
+	// StopMarket, StopLimit and TakeProfit orders wait off-book until the last trade
+	// price crosses their trigger price, then turn into a market or limit order.
+	StopMarket OrderKind = "STOP_MARKET"
+	StopLimit  OrderKind = "STOP_LIMIT"
+	TakeProfit OrderKind = "TAKE_PROFIT"
+)
+
+func (k *OrderKind) Validate() bool {
+	switch *k {
+	case Limit, Market, StopMarket, StopLimit, TakeProfit:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsMarket reports whether the order executes at market prices, now or once triggered.
+func (k *OrderKind) IsMarket() bool {
+	switch *k {
+	case Market, StopMarket, TakeProfit:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTrigger reports whether the order waits for a trigger price.
+func (k *OrderKind) IsTrigger() bool {
+	switch *k {
+	case StopMarket, StopLimit, TakeProfit:
+		return true
+	default:
+		return false
+	}
+}
+
+// TimeInForce tells how long the order stays on the book.
+type TimeInForce string
+
+const (
+	// GTC orders stay on the book until they are filled or cancelled.
+	GTC TimeInForce = "GTC"
+	// IOC orders fill what they can immediately and cancel the rest.
+	IOC TimeInForce = "IOC"
+	// FOK orders fill completely and immediately or not at all.
+	FOK TimeInForce = "FOK"
+	// GTD orders stay on the book until ExpiresAt.
+	GTD TimeInForce = "GTD"
+)
+
+func (t *TimeInForce) Validate() bool {
+	switch *t {
+	case GTC, IOC, FOK, GTD:
+		return true
+	default:
+		return false
+	}
+}
+
 type OrderStatus string
 
 const (
-	OrderOpen      OrderStatus = "ORDER_OPEN"
-	OrderClosed    OrderStatus = "ORDER_CLOSED"
-	OrderCancelled OrderStatus = "ORDER_CANCELLED"
+	OrderPending         OrderStatus = "ORDER_PENDING"
+	OrderOpen            OrderStatus = "ORDER_OPEN"
+	OrderPartiallyFilled OrderStatus = "ORDER_PARTIALLY_FILLED"
+	OrderClosed          OrderStatus = "ORDER_CLOSED"
+	OrderCancelled       OrderStatus = "ORDER_CANCELLED"
+	OrderExpired         OrderStatus = "ORDER_EXPIRED"
 )
 
+// IsOpen reports whether an order with the status can still be matched.
+func (s *OrderStatus) IsOpen() bool {
+	switch *s {
+	case OrderOpen, OrderPartiallyFilled:
+		return true
+	default:
+		return false
+	}
+}
+
 type Order struct {
 	ID uuid.UUID `json:"id"`
 
 	Type OrderType `json:"type"`
+	Kind OrderKind `json:"kind"`
+
+	TimeInForce TimeInForce `json:"time_in_force"`
+	ExpiresAt   null.Time   `json:"expires_at,omitempty"`
+
+	SellCurrency   Currency            `json:"sell_currency"`
+	SellQuantity   decimal.NullDecimal `json:"sell_quantity"`
+	SellRequisites Requisites          `json:"sell_requisites"`
 
-	SellCurrency   Currency   `json:"sell_currency"`
-	SellQuantity   null.Float `json:"sell_quantity"`
-	SellRequisites Requisites `json:"sell_requisites"`
+	Price decimal.Decimal `json:"price"`
 
-	Price float64 `json:"price"`
+	TriggerPrice decimal.NullDecimal `json:"trigger_price,omitempty"`
+	TriggeredAt  null.Time           `json:"triggered_at,omitempty"`
 
-	BuyCurrency   Currency   `json:"buy_currency"`
-	BuyQuantity   null.Float `json:"buy_quantity"`
-	BuyRequisites Requisites `json:"buy_requisites"`
+	BuyCurrency   Currency            `json:"buy_currency"`
+	BuyQuantity   decimal.NullDecimal `json:"buy_quantity"`
+	BuyRequisites Requisites          `json:"buy_requisites"`
+
+	// OriginalQuantity and RemainingQuantity are in the unit the order is sized in:
+	// the quote budget for market buys and the base currency otherwise.
+	// FilledQuantity and FilledQuoteQuantity are the base and quote volume traded so far,
+	// AveragePrice is their volume-weighted price.
+	OriginalQuantity    decimal.Decimal     `json:"original_quantity"`
+	FilledQuantity      decimal.Decimal     `json:"filled_quantity"`
+	FilledQuoteQuantity decimal.Decimal     `json:"filled_quote_quantity"`
+	RemainingQuantity   decimal.Decimal     `json:"remaining_quantity"`
+	AveragePrice        decimal.NullDecimal `json:"average_price"`
 
 	Status OrderStatus `json:"status"`
 
@@ -42,4 +140,24 @@ type Order struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	RemovedAt null.Time `json:"removed_at,omitempty"`
 	ClosedAt  null.Time `json:"closed_at,omitempty"`
+	ExpiredAt null.Time `json:"expired_at,omitempty"`
+
+	// Fills are the executions made while the order was being placed.
+	// They are only reported in the create order response.
+	Fills []Fill `json:"fills,omitempty"`
+}
+
+// Fill is a single execution of an order against a counter order.
+type Fill struct {
+	TradeID        uuid.UUID       `json:"trade_id"`
+	CounterOrderID uuid.UUID       `json:"counter_order_id"`
+	Price          decimal.Decimal `json:"price"`
+	BaseQuantity   decimal.Decimal `json:"base_quantity"`
+	QuoteQuantity  decimal.Decimal `json:"quote_quantity"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// IsExpired reports whether a GTD order has reached its expiry time.
+func (o *Order) IsExpired(now time.Time) bool {
+	return o.TimeInForce == GTD && o.ExpiresAt.Valid && !o.ExpiresAt.Time.After(now)
 }
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"time"
+)
+
+// RefreshToken is a login session. Only the SHA-256 hash of the token is kept.
+// AccessJTI is the jti of the access token issued with it, so revoking the
+// refresh token revokes that access token as well.
+type RefreshToken struct {
+	ID     uuid.UUID `json:"id"`
+	UserID string    `json:"user_id"`
+
+	TokenHash string    `json:"-"`
+	AccessJTI uuid.UUID `json:"access_jti"`
+
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt null.Time `json:"revoked_at"`
+}
+
+// IsActive reports whether the token is neither revoked nor expired at the time.
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return !t.RevokedAt.Valid && now.Before(t.ExpiresAt)
+}
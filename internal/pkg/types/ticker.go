@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Ticker holds the rolling 24-hour statistics of a currency pair together with
+// its last price and the best prices of its order book. Prices are in the quote
+// currency, Volume in the base currency.
+type Ticker struct {
+	CurrencyPair CurrencyPair `json:"currency_pair"`
+
+	LastPrice decimal.NullDecimal `json:"last_price"`
+	BestBid   decimal.NullDecimal `json:"best_bid"`
+	BestAsk   decimal.NullDecimal `json:"best_ask"`
+
+	OpenPrice          decimal.NullDecimal `json:"open_price"`
+	HighPrice          decimal.NullDecimal `json:"high_price"`
+	LowPrice           decimal.NullDecimal `json:"low_price"`
+	PriceChange        decimal.NullDecimal `json:"price_change"`
+	PriceChangePercent decimal.NullDecimal `json:"price_change_percent"`
+
+	Volume      decimal.Decimal `json:"volume"`
+	QuoteVolume decimal.Decimal `json:"quote_volume"`
+	TradeCount  int             `json:"trade_count"`
+
+	Time time.Time `json:"time"`
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"time"
+)
+
+// TOTPEnrollment is the authenticator of a user. It protects the account once it
+// is confirmed with a first code. LastStep is the last time step a code was
+// accepted for, so every code works only once.
+type TOTPEnrollment struct {
+	UserID string `json:"user_id"`
+
+	Secret   string `json:"-"`
+	LastStep int64  `json:"-"`
+
+	CreatedAt   time.Time `json:"created_at"`
+	ConfirmedAt null.Time `json:"confirmed_at"`
+}
+
+// IsConfirmed reports whether the enrollment protects the account.
+func (e *TOTPEnrollment) IsConfirmed() bool {
+	return e.ConfirmedAt.Valid
+}
+
+// RecoveryCode replaces a TOTP code once, when the authenticator is lost. Only the
+// SHA-256 hash of the code is kept.
+type RecoveryCode struct {
+	ID     uuid.UUID `json:"id"`
+	UserID string    `json:"user_id"`
+
+	CodeHash string `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UsedAt    null.Time `json:"used_at"`
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Trade is a single execution between a buy and a sell order. Quantity is in the
+// base currency, Price and QuoteQuantity in the quote currency. BuyerFee is in
+// the base currency the buyer receives, SellerFee in the quote currency the
+// seller receives.
+type Trade struct {
+	ID uuid.UUID `json:"id"`
+
+	BaseCurrency  Currency `json:"base_currency"`
+	QuoteCurrency Currency `json:"quote_currency"`
+
+	BuyOrderID   uuid.UUID `json:"buy_order_id"`
+	SellOrderID  uuid.UUID `json:"sell_order_id"`
+	BuyerUserID  string    `json:"-"`
+	SellerUserID string    `json:"-"`
+
+	Price         decimal.Decimal `json:"price"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	QuoteQuantity decimal.Decimal `json:"quote_quantity"`
+	MakerSide     OrderType       `json:"maker_side"`
+
+	BuyerFee  decimal.Decimal `json:"buyer_fee"`
+	SellerFee decimal.Decimal `json:"seller_fee"`
+
+	BaseTransactionID  string `json:"-"`
+	QuoteTransactionID string `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
 	"time"
 )
 
@@ -11,8 +12,11 @@ type Transaction struct {
 	SenderRequisites   Requisites `json:"sender_requisites,omitempty"`
 	ReceiverRequisites Requisites `json:"receiver_requisites,omitempty"`
 
-	Amount   float64  `json:"amount,omitempty"`
-	Currency Currency `json:"currency,omitempty"`
+	Amount   decimal.Decimal `json:"amount,omitempty"`
+	Currency Currency        `json:"currency,omitempty"`
+
+	// Fee is the part of the amount credited to the fee wallet instead of the receiver.
+	Fee decimal.Decimal `json:"fee"`
 
 	Purpose null.String `json:"purpose,omitempty"`
 
@@ -1,6 +1,27 @@
 package types
 
 type User struct {
-	UserID         string `json:"user_id"`
-	HashedPassword string `json:"hashed_password"`
+	UserID         string  `json:"user_id"`
+	HashedPassword string  `json:"hashed_password"`
+	FeeTier        FeeTier `json:"fee_tier"`
+	Role           Role    `json:"role"`
+}
+
+// Role grants access to the operational routes. Support staff can inspect every
+// user's data, admins can also change it and run the exchange.
+type Role string
+
+const (
+	RoleUser    Role = "USER"
+	RoleSupport Role = "SUPPORT"
+	RoleAdmin   Role = "ADMIN"
+)
+
+func (r *Role) Validate() bool {
+	switch *r {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return true
+	default:
+		return false
+	}
 }
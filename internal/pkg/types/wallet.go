@@ -1,12 +1,19 @@
 package types
 
-import "time"
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
 
 type Wallet struct {
 	Requisites Requisites `json:"requisites"`
 
-	Currency Currency `json:"currency"`
-	Balance  float64  `json:"balance"`
+	Currency Currency        `json:"currency"`
+	Balance  decimal.Decimal `json:"balance"`
+
+	// Locked is the part of the balance held by open orders, Available is the rest.
+	Locked    decimal.Decimal `json:"locked"`
+	Available decimal.Decimal `json:"available"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
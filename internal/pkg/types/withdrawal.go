@@ -0,0 +1,86 @@
+package types
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v5"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// ErrWithdrawalTransition is returned when a withdrawal cannot move to the requested status.
+var ErrWithdrawalTransition = errors.New("invalid withdrawal status transition")
+
+type WithdrawalStatus string
+
+const (
+	WithdrawalPending   WithdrawalStatus = "PENDING"
+	WithdrawalApproved  WithdrawalStatus = "APPROVED"
+	WithdrawalSending   WithdrawalStatus = "SENDING"
+	WithdrawalSent      WithdrawalStatus = "SENT"
+	WithdrawalCompleted WithdrawalStatus = "COMPLETED"
+	WithdrawalRejected  WithdrawalStatus = "REJECTED"
+	WithdrawalFailed    WithdrawalStatus = "FAILED"
+)
+
+// withdrawalTransitions lists the statuses each status can move to.
+var withdrawalTransitions = map[WithdrawalStatus][]WithdrawalStatus{
+	WithdrawalPending:  {WithdrawalApproved, WithdrawalRejected},
+	WithdrawalApproved: {WithdrawalSending},
+	WithdrawalSending:  {WithdrawalSent, WithdrawalFailed},
+	WithdrawalSent:     {WithdrawalCompleted, WithdrawalFailed},
+}
+
+func (s *WithdrawalStatus) Validate() bool {
+	switch *s {
+	case WithdrawalPending, WithdrawalApproved, WithdrawalSending, WithdrawalSent, WithdrawalCompleted, WithdrawalRejected, WithdrawalFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo reports whether a withdrawal in this status can move to next.
+func (s *WithdrawalStatus) CanTransitionTo(next WithdrawalStatus) bool {
+	for _, status := range withdrawalTransitions[*s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether the withdrawal is completed, rejected or failed.
+func (s *WithdrawalStatus) IsFinal() bool {
+	return len(withdrawalTransitions[*s]) == 0
+}
+
+// Withdrawal moves Amount out of a wallet to an external Destination. The amount
+// is held in the wallet until the withdrawal completes, which debits it, or is
+// rejected or fails, which releases it.
+type Withdrawal struct {
+	ID uuid.UUID `json:"id"`
+
+	Requisites  Requisites      `json:"requisites"`
+	Currency    Currency        `json:"currency"`
+	Amount      decimal.Decimal `json:"amount"`
+	Destination string          `json:"destination"`
+
+	Status WithdrawalStatus `json:"status"`
+
+	// Reference identifies the payout at the payout backend once it is sent.
+	Reference  null.String `json:"reference,omitempty"`
+	Reason     null.String `json:"reason,omitempty"`
+	ReviewedBy null.String `json:"reviewed_by,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PayoutStatus string
+
+const (
+	PayoutPending   PayoutStatus = "PENDING"
+	PayoutCompleted PayoutStatus = "COMPLETED"
+	PayoutFailed    PayoutStatus = "FAILED"
+)
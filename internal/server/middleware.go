@@ -1,11 +1,22 @@
 package server
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"vitalik_backend/internal/pkg/services/api_key_service"
+	api_key_service_types "vitalik_backend/internal/pkg/services/api_key_service/types"
+	"vitalik_backend/internal/pkg/services/auth_service"
+	auth_types "vitalik_backend/internal/pkg/services/auth_service/types"
+	"vitalik_backend/internal/pkg/types"
 )
 
 func requestLogger(logger *zap.SugaredLogger) echo.MiddlewareFunc {
@@ -19,7 +30,7 @@ func requestLogger(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 			if v.Status >= 200 && v.Status < 300 {
 				logger.Infow("REQUEST",
 					"method", v.Method,
-					"uri", v.URI,
+					"uri", redactURI(v.URI),
 					"status", v.Status,
 					"user_agent", v.UserAgent,
 					"remote_ip", v.RemoteIP,
@@ -27,7 +38,7 @@ func requestLogger(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 			} else {
 				logger.Infow("REQUEST_ERROR",
 					"method", v.Method,
-					"uri", v.URI,
+					"uri", redactURI(v.URI),
 					"status", v.Status,
 					"user_agent", v.UserAgent,
 					"remote_ip", v.RemoteIP,
@@ -40,8 +51,73 @@ func requestLogger(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 	})
 }
 
+// redactURI hides the token query parameter, which carries the access token of
+// WebSocket requests, so it is not written to the logs. A query that can not be
+// parsed is dropped.
+func redactURI(uri string) string {
+	path, rawQuery, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path
+	}
+	if !query.Has("token") {
+		return uri
+	}
+	query.Set("token", "REDACTED")
+
+	return path + "?" + query.Encode()
+}
+
+const (
+	apiKeyHeader          = "X-API-KEY"
+	apiKeyTimestampHeader = "X-API-TIMESTAMP"
+	apiKeySignatureHeader = "X-API-SIGNATURE"
+
+	otpCodeHeader = "X-OTP-CODE"
+)
+
+// apiKeyScopes lists the routes that accept API keys and the scope each one needs.
+// Every other route, such as the API key management and the admin routes, accepts
+// only access tokens.
+var apiKeyScopes = map[string]types.APIKeyScope{
+	"POST /auth/wallets":      types.APIKeyScopeRead,
+	"POST /auth/deposits":     types.APIKeyScopeRead,
+	"POST /auth/transactions": types.APIKeyScopeRead,
+	"POST /auth/orders":       types.APIKeyScopeRead,
+	"POST /auth/trades":       types.APIKeyScopeRead,
+	"POST /auth/trades/my":    types.APIKeyScopeRead,
+	"POST /auth/candles":      types.APIKeyScopeRead,
+	"POST /auth/ticker":       types.APIKeyScopeRead,
+	"GET /auth/tickers":       types.APIKeyScopeRead,
+	"POST /auth/fees":         types.APIKeyScopeRead,
+	"POST /auth/fees/paid":    types.APIKeyScopeRead,
+	"POST /auth/withdrawals":  types.APIKeyScopeRead,
+	"GET /auth/currencies":    types.APIKeyScopeRead,
+
+	"POST /auth/wallets/create": types.APIKeyScopeTrade,
+	"POST /auth/orders/create":  types.APIKeyScopeTrade,
+	"DELETE /auth/orders":       types.APIKeyScopeTrade,
+
+	"POST /auth/transfer":           types.APIKeyScopeWithdraw,
+	"POST /auth/withdrawals/create": types.APIKeyScopeWithdraw,
+}
+
+// authMiddleware authenticates the request by the access token in the
+// Authorization header or, when the API key headers are set, by the API key
+// signature.
 func (s *Server) authMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if c.Request().Header.Get(apiKeyHeader) != "" {
+			if err := s.authenticateAPIKey(c); err != nil {
+				return err
+			}
+			return next(c)
+		}
+
 		authHeader := c.Request().Header.Get("Authorization")
 		if authHeader == "" {
 			return echo.NewHTTPError(http.StatusUnauthorized, "missing authorization header")
@@ -53,12 +129,98 @@ func (s *Server) authMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 		tokenString := tokenParts[1]
 
-		userID, err := s.auth.Authenticate(c.Request().Context(), tokenString)
+		identity, err := s.auth.Authenticate(c.Request().Context(), tokenString)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 		}
 
-		c.Set("user_id", userID)
+		c.Set("user_id", identity.UserID)
+		c.Set("role", identity.Role)
+
+		return next(c)
+	}
+}
+
+// authenticateAPIKey checks the signature of the request and that its API key has
+// the scope the route needs.
+func (s *Server) authenticateAPIKey(c echo.Context) error {
+	scope, ok := apiKeyScopes[c.Request().Method+" "+c.Path()]
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden, "route is not available to API keys")
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body")
+	}
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	key, err := s.apiKeys.Authenticate(c.Request().Context(), api_key_service_types.AuthenticateArgs{
+		KeyID:     c.Request().Header.Get(apiKeyHeader),
+		Timestamp: c.Request().Header.Get(apiKeyTimestampHeader),
+		Signature: c.Request().Header.Get(apiKeySignatureHeader),
+		Method:    c.Request().Method,
+		Path:      c.Request().URL.RequestURI(),
+		Body:      body,
+		RemoteIP:  c.RealIP(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, api_key_service.ErrIPNotAllowed):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		case errors.Is(err, api_key_service.ErrInvalidAPIKey),
+			errors.Is(err, api_key_service.ErrInvalidSignature),
+			errors.Is(err, api_key_service.ErrRequestExpired),
+			errors.Is(err, api_key_service.ErrReplayedRequest):
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if !key.HasScope(scope) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("API key has no %s scope", scope))
+	}
+
+	c.Set("user_id", key.UserID)
+
+	return nil
+}
+
+// requireRole lets through only users with one of the roles. It runs after
+// authMiddleware. Requests signed by API keys carry no role and are rejected.
+func requireRole(roles ...types.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, ok := c.Get("role").(types.Role)
+			if !ok || !slices.Contains(roles, role) {
+				return echo.NewHTTPError(http.StatusForbidden, "insufficient role")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// requireOTP asks users with two-factor authentication for a fresh TOTP code in
+// the X-OTP-CODE header. It guards the sensitive routes and runs after
+// authMiddleware.
+func (s *Server) requireOTP(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, ok := c.Get("user_id").(string)
+		if !ok || userID == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "user is not authenticated")
+		}
+
+		err := s.auth.VerifyOTP(c.Request().Context(), auth_types.VerifyOTPArgs{
+			UserID: userID,
+			Code:   c.Request().Header.Get(otpCodeHeader),
+		})
+		if err != nil {
+			if errors.Is(err, auth_service.ErrOTPRequired) || errors.Is(err, auth_service.ErrInvalidOTP) {
+				return echo.NewHTTPError(http.StatusForbidden, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
 
 		return next(c)
 	}
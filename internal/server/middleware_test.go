@@ -0,0 +1,13 @@
+package server
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRedactURI(t *testing.T) {
+	require.Equal(t, "/ws?token=REDACTED", redactURI("/ws?token=eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	require.Equal(t, "/ws?pair=BTC&token=REDACTED", redactURI("/ws?token=secret&pair=BTC"))
+	require.Equal(t, "/auth/orders?limit=10", redactURI("/auth/orders?limit=10"))
+	require.Equal(t, "/ws", redactURI("/ws?token=%zz"))
+}
@@ -7,23 +7,37 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
 	"vitalik_backend/internal/dependencies"
+	"vitalik_backend/internal/pkg/types"
 )
 
+// Config holds the server settings. Port is the port the server listens on.
+// DepositFaucetEnabled routes the admin deposit faucet, which credits any wallet
+// with any amount and is meant for development only.
+type Config struct {
+	Port                 int
+	DepositFaucetEnabled bool
+}
+
 type Server struct {
 	logger  *zap.SugaredLogger
 	echo    *echo.Echo
 	handler dependencies.IHandler
 	auth    dependencies.IAuthService
+	apiKeys dependencies.IAPIKeyService
+	config  Config
 }
 
 func NewServer(
 	logger *zap.Logger,
 	handler dependencies.IHandler,
 	auth dependencies.IAuthService,
+	apiKeys dependencies.IAPIKeyService,
+	config Config,
 ) (*Server, error) {
 	if logger == nil ||
 		handler == nil ||
-		auth == nil {
+		auth == nil ||
+		apiKeys == nil {
 		return nil, errors.New("failed to initialize server")
 	}
 
@@ -31,12 +45,16 @@ func NewServer(
 
 	e.HideBanner = true
 	e.HidePort = true
+	// API key IP allow-lists must see the peer address, not a client supplied header.
+	e.IPExtractor = echo.ExtractIPDirect()
 
 	s := &Server{
 		logger:  logger.Sugar(),
 		echo:    e,
 		handler: handler,
 		auth:    auth,
+		apiKeys: apiKeys,
+		config:  config,
 	}
 
 	s.setupMiddleware()
@@ -64,7 +82,7 @@ func (s *Server) setupMiddleware() {
 	s.echo.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.OPTIONS},
-		AllowHeaders: []string{"Content-Type", "Authorization"},
+		AllowHeaders: []string{"Content-Type", "Authorization", apiKeyHeader, apiKeyTimestampHeader, apiKeySignatureHeader, otpCodeHeader},
 	}))
 }
 
@@ -72,20 +90,65 @@ func (s *Server) setupRoutes() {
 	s.echo.GET("/healthCheck", s.handler.HealthCheck())
 	s.echo.POST("/register", s.handler.Register())
 	s.echo.POST("/login", s.handler.Login())
+	s.echo.POST("/refresh", s.handler.Refresh())
+	s.echo.POST("/logout", s.handler.Logout())
+	s.echo.GET("/ws", s.handler.Stream())
+	s.echo.POST("/orders/depth", s.handler.GetDepth())
 
 	authGroup := s.echo.Group("/auth")
 	authGroup.Use(s.authMiddleware)
 
 	authGroup.POST("/wallets/create", s.handler.CreateWallet())
 	authGroup.POST("/wallets", s.handler.ListWallets())
-	authGroup.PUT("/wallets/deposit", s.handler.Deposit())
+	authGroup.POST("/deposits", s.handler.ListDeposits())
 
-	authGroup.POST("/transfer", s.handler.Transfer())
+	authGroup.POST("/transfer", s.handler.Transfer(), s.requireOTP)
 	authGroup.POST("/transactions", s.handler.ListTransactions())
 	authGroup.POST("/orders/create", s.handler.CreateOrder())
 	authGroup.DELETE("/orders", s.handler.CancelOrder())
 	authGroup.POST("/orders", s.handler.ListOrders())
-	authGroup.POST("/orders/match", s.handler.MatchOrders())
+
+	authGroup.POST("/trades", s.handler.ListTrades())
+	authGroup.POST("/trades/my", s.handler.ListMyTrades())
+
+	authGroup.POST("/candles", s.handler.ListCandles())
+	authGroup.POST("/ticker", s.handler.GetTicker())
+	authGroup.GET("/tickers", s.handler.ListTickers())
+
+	authGroup.POST("/fees", s.handler.GetFeeRate())
+	authGroup.POST("/fees/paid", s.handler.ListPaidFees())
+
+	authGroup.POST("/withdrawals/create", s.handler.RequestWithdrawal(), s.requireOTP)
+	authGroup.POST("/withdrawals", s.handler.ListWithdrawals())
 
 	authGroup.GET("/currencies", s.handler.ListAvailableCurrencies())
+
+	authGroup.POST("/api-keys/create", s.handler.CreateAPIKey(), s.requireOTP)
+	authGroup.POST("/api-keys", s.handler.ListAPIKeys())
+	authGroup.POST("/api-keys/revoke", s.handler.RevokeAPIKey())
+
+	authGroup.POST("/password", s.handler.ChangePassword(), s.requireOTP)
+	authGroup.POST("/2fa/enroll", s.handler.EnrollTOTP())
+	authGroup.POST("/2fa/confirm", s.handler.ConfirmTOTP())
+	authGroup.POST("/2fa/disable", s.handler.DisableTOTP(), s.requireOTP)
+
+	// Support staff can inspect every user's data, only admins can change it.
+	adminGroup := authGroup.Group("/admin")
+	adminGroup.Use(requireRole(types.RoleSupport, types.RoleAdmin))
+	adminOnly := requireRole(types.RoleAdmin)
+
+	adminGroup.POST("/wallets", s.handler.ListAllWallets())
+	if s.config.DepositFaucetEnabled {
+		adminGroup.PUT("/wallets/deposit", s.handler.Deposit(), adminOnly)
+	}
+
+	adminGroup.POST("/withdrawals", s.handler.ListAllWithdrawals())
+	adminGroup.POST("/withdrawals/approve", s.handler.ApproveWithdrawal(), adminOnly)
+	adminGroup.POST("/withdrawals/reject", s.handler.RejectWithdrawal(), adminOnly)
+
+	adminGroup.POST("/orders/match", s.handler.MatchOrders(), adminOnly)
+	adminGroup.POST("/markets/halt", s.handler.HaltMarket(), adminOnly)
+	adminGroup.POST("/markets/resume", s.handler.ResumeMarket(), adminOnly)
+
+	adminGroup.POST("/users/role", s.handler.SetUserRole(), adminOnly)
 }